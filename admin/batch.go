@@ -0,0 +1,195 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// DefaultBatchConcurrency is the number of requests sent in parallel
+// by batch operations unless changed with SetBatchConcurrency.
+const DefaultBatchConcurrency = 10
+
+// BatchItemError is the error of a single item of a batch operation.
+type BatchItemError struct {
+	// Index is the position of the item in the batch.
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return fmt.Sprintf("item %d: %v", e.Index, e.Err)
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
+
+// BatchError holds the errors of all failed items of a batch operation.
+type BatchError struct {
+	Total  int
+	Errors []*BatchItemError
+}
+
+func (e *BatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d of %d operations failed: %s",
+		len(e.Errors), e.Total, strings.Join(messages, "; "))
+}
+
+// SetBatchConcurrency sets the number of requests sent in parallel
+// by batch operations. Values lower than 1 reset it to
+// DefaultBatchConcurrency. Requests remain subject to the rate limiter
+// of the underlying HTTP client, if any.
+func (c *Client) SetBatchConcurrency(concurrency int) {
+	if concurrency < 1 {
+		concurrency = DefaultBatchConcurrency
+	}
+	c.batchConcurrency = concurrency
+}
+
+func (c *Client) concurrency() int {
+	if c.batchConcurrency < 1 {
+		return DefaultBatchConcurrency
+	}
+	return c.batchConcurrency
+}
+
+// Batch calls fn for every item using at most concurrency goroutines.
+// Results are returned in the order of items; the result of a failed item
+// is the zero value of R. All items are processed even if some fail,
+// unless ctx is done. If any item fails, a *BatchError is returned.
+func Batch[T any, R any](ctx context.Context, concurrency int, items []T,
+	fn func(context.Context, T) (R, error),
+) ([]R, error) {
+	if concurrency < 1 {
+		concurrency = DefaultBatchConcurrency
+	}
+	results := make([]R, len(items))
+	var (
+		mu   sync.Mutex
+		errs []*BatchItemError
+		wg   sync.WaitGroup
+	)
+	indexes := make(chan int)
+	for w := 0; w < concurrency && w < len(items); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				var (
+					res R
+					err = ctx.Err()
+				)
+				if err == nil {
+					res, err = fn(ctx, items[i])
+				}
+				if err != nil {
+					mu.Lock()
+					errs = append(errs, &BatchItemError{Index: i, Err: err})
+					mu.Unlock()
+					continue
+				}
+				results[i] = res
+			}
+		}()
+	}
+	for i := range items {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Index < errs[j].Index })
+		return results, &BatchError{Total: len(items), Errors: errs}
+	}
+	return results, nil
+}
+
+// batchCreate creates items in collection in parallel. Items with an
+// ID, as returned by id, are created with it.
+func batchCreate[T any](ctx context.Context, c *Client, collection string,
+	items []*T, id func(*T) *string,
+) ([]*T, error) {
+	entities := NewEntityService[T](c, collection)
+	return Batch(ctx, c.concurrency(), items, func(ctx context.Context, item *T) (*T, error) {
+		if item != nil {
+			if key := id(item); key != nil && *key != "" {
+				return entities.Upsert(ctx, *key, item)
+			}
+		}
+		return entities.Create(ctx, item)
+	})
+}
+
+// batchDelete deletes the entities of collection identified by
+// nameOrIDs in parallel.
+func batchDelete(ctx context.Context, c *Client, collection string, nameOrIDs []string) error {
+	entities := NewEntityService[json.RawMessage](c, collection)
+	_, err := Batch(ctx, c.concurrency(), nameOrIDs,
+		func(ctx context.Context, nameOrID string) (struct{}, error) {
+			return struct{}{}, entities.Delete(ctx, nameOrID)
+		})
+	return err
+}
+
+// CreateBatch creates services in parallel.
+func (s *ServiceService) CreateBatch(ctx context.Context,
+	services []*kong.Service,
+) ([]*kong.Service, error) {
+	return batchCreate(ctx, s.client, "/services", services,
+		func(svc *kong.Service) *string { return svc.ID })
+}
+
+// DeleteBatch deletes services, identified by name or ID, in parallel.
+func (s *ServiceService) DeleteBatch(ctx context.Context, nameOrIDs []string) error {
+	return batchDelete(ctx, s.client, "/services", nameOrIDs)
+}
+
+// CreateBatch creates routes in parallel.
+func (s *RouteService) CreateBatch(ctx context.Context,
+	routes []*kong.Route,
+) ([]*kong.Route, error) {
+	return batchCreate(ctx, s.client, "/routes", routes,
+		func(route *kong.Route) *string { return route.ID })
+}
+
+// DeleteBatch deletes routes, identified by name or ID, in parallel.
+func (s *RouteService) DeleteBatch(ctx context.Context, nameOrIDs []string) error {
+	return batchDelete(ctx, s.client, "/routes", nameOrIDs)
+}
+
+// CreateBatch creates consumers in parallel.
+func (s *ConsumerService) CreateBatch(ctx context.Context,
+	consumers []*kong.Consumer,
+) ([]*kong.Consumer, error) {
+	return batchCreate(ctx, s.client, "/consumers", consumers,
+		func(consumer *kong.Consumer) *string { return consumer.ID })
+}
+
+// DeleteBatch deletes consumers, identified by username or ID, in parallel.
+func (s *ConsumerService) DeleteBatch(ctx context.Context, usernameOrIDs []string) error {
+	return batchDelete(ctx, s.client, "/consumers", usernameOrIDs)
+}
+
+// CreateBatch creates plugins in parallel.
+func (s *PluginService) CreateBatch(ctx context.Context,
+	plugins []*kong.Plugin,
+) ([]*kong.Plugin, error) {
+	return batchCreate(ctx, s.client, "/plugins", plugins,
+		func(plugin *kong.Plugin) *string { return plugin.ID })
+}
+
+// DeleteBatch deletes plugins, identified by ID, in parallel.
+func (s *PluginService) DeleteBatch(ctx context.Context, ids []string) error {
+	return batchDelete(ctx, s.client, "/plugins", ids)
+}
@@ -0,0 +1,88 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchConcurrency(t *testing.T) {
+	var running, maxRunning int32
+	items := make([]int, 20)
+	for i := range items {
+		items[i] = i
+	}
+	results, err := Batch(context.Background(), 3, items, func(_ context.Context, i int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		if i%7 == 0 {
+			return 0, fmt.Errorf("boom %d", i)
+		}
+		return i * 2, nil
+	})
+	require.Error(t, err)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+	assert.Equal(t, 38, results[19])
+	assert.Equal(t, 0, results[7])
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Equal(t, 20, batchErr.Total)
+	require.Len(t, batchErr.Errors, 3)
+	assert.Equal(t, []int{0, 7, 14}, []int{
+		batchErr.Errors[0].Index, batchErr.Errors[1].Index, batchErr.Errors[2].Index,
+	})
+	assert.Contains(t, err.Error(), "3 of 20 operations failed: item 0: boom 0")
+}
+
+func TestConsumersCreateBatch(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		var consumer kong.Consumer
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&consumer))
+		if *consumer.Username == "dup" {
+			w.WriteHeader(http.StatusConflict)
+			_, _ = w.Write([]byte(`{"message":"UNIQUE violation detected"}`))
+			return
+		}
+		consumer.ID = kong.String("id-" + *consumer.Username)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(consumer)
+	}))
+	client.SetBatchConcurrency(4)
+
+	consumers := []*kong.Consumer{
+		{Username: kong.String("alice")},
+		{Username: kong.String("dup")},
+		{Username: kong.String("bob")},
+	}
+	created, err := client.Consumers.CreateBatch(context.Background(), consumers)
+	require.Error(t, err)
+	assert.Equal(t, "id-alice", *created[0].ID)
+	assert.Nil(t, created[1])
+	assert.Equal(t, "id-bob", *created[2].ID)
+
+	var batchErr *BatchError
+	require.True(t, errors.As(err, &batchErr))
+	require.Len(t, batchErr.Errors, 1)
+	assert.Equal(t, 1, batchErr.Errors[0].Index)
+	var apiErr *kong.APIError
+	require.True(t, errors.As(batchErr.Errors[0], &apiErr))
+	assert.Equal(t, http.StatusConflict, apiErr.Code())
+}
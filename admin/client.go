@@ -0,0 +1,221 @@
+// Package admin extends go-kong's Client with Kong Admin API
+// endpoints which are not covered by go-kong.
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+type service struct {
+	client *Client
+}
+
+// Client talks to the Admin API of a Kong node, using an
+// underlying go-kong Client for transport, authentication and logging.
+type Client struct {
+	kong   *kong.Client
+	common service
+
+	batchConcurrency int
+
+	// overrides of Clone
+	workspace *string
+	headers   http.Header
+	logger    Logger
+
+	Config    *ConfigService
+	Status    *StatusService
+	Schemas   *SchemaService
+	Upstreams *UpstreamService
+	Targets   *TargetService
+
+	Services       *ServiceService
+	Routes         *RouteService
+	Consumers      *ConsumerService
+	ConsumerGroups *ConsumerGroupService
+	Plugins        *PluginService
+	Certificates   *CertificateService
+	CACertificates *CACertificateService
+	SNIs           *SNIService
+	Vaults         *VaultService
+
+	FilterChains *FilterChainService
+	Partials     *PartialService
+
+	DegraphqlRoutes        *DegraphqlRouteService
+	GraphqlCostDecorations *GraphqlCostDecorationService
+
+	Clustering *ClusteringService
+	Portal     *PortalService
+	Vitals     *VitalsService
+	Keyring    *KeyringService
+}
+
+// NewClient returns a Client which sends requests using kongClient.
+func NewClient(kongClient *kong.Client) (*Client, error) {
+	if kongClient == nil {
+		return nil, fmt.Errorf("kong client cannot be nil")
+	}
+	client := new(Client)
+	client.kong = kongClient
+
+	client.registerServices()
+	return client, nil
+}
+
+func (c *Client) registerServices() {
+	c.common.client = c
+	c.Config = (*ConfigService)(&c.common)
+	c.Status = (*StatusService)(&c.common)
+	c.Schemas = (*SchemaService)(&c.common)
+	c.Upstreams = (*UpstreamService)(&c.common)
+	c.Targets = (*TargetService)(&c.common)
+
+	c.Services = (*ServiceService)(&c.common)
+	c.Routes = (*RouteService)(&c.common)
+	c.Consumers = (*ConsumerService)(&c.common)
+	c.ConsumerGroups = (*ConsumerGroupService)(&c.common)
+	c.Plugins = (*PluginService)(&c.common)
+	c.Certificates = (*CertificateService)(&c.common)
+	c.CACertificates = (*CACertificateService)(&c.common)
+	c.SNIs = (*SNIService)(&c.common)
+	c.Vaults = (*VaultService)(&c.common)
+
+	c.FilterChains = (*FilterChainService)(&c.common)
+	c.Partials = (*PartialService)(&c.common)
+
+	c.DegraphqlRoutes = (*DegraphqlRouteService)(&c.common)
+	c.GraphqlCostDecorations = (*GraphqlCostDecorationService)(&c.common)
+
+	c.Clustering = (*ClusteringService)(&c.common)
+	c.Portal = (*PortalService)(&c.common)
+	c.Vitals = (*VitalsService)(&c.common)
+	c.Keyring = (*KeyringService)(&c.common)
+}
+
+// CloneOptions overrides the settings of a Client in Clone.
+type CloneOptions struct {
+	// Workspace, if set, is the workspace of the requests of the clone.
+	// An empty string targets the default workspace.
+	Workspace *string
+	// Headers are set on the requests of the clone, replacing the
+	// headers of the same name set by the cloned Client. Headers injected
+	// by the transport of the go-kong Client are still added.
+	Headers http.Header
+	// Logger, if set, receives the requests sent by the clone.
+	Logger Logger
+}
+
+// Clone returns a copy of the Client with the settings of opts.
+// The clone shares the underlying go-kong Client and its transport, so
+// that cloning is cheap, e.g. to use per-tenant credentials.
+// The settings of opts apply to all the requests sent by the methods of
+// the clone and its services, but not to the requests sent with the
+// go-kong Client returned by Kong.
+func (c *Client) Clone(opts CloneOptions) *Client {
+	clone := &Client{
+		kong:             c.kong,
+		batchConcurrency: c.batchConcurrency,
+		workspace:        c.workspace,
+		headers:          c.headers.Clone(),
+		logger:           c.logger,
+	}
+	if opts.Workspace != nil {
+		workspace := *opts.Workspace
+		clone.workspace = &workspace
+	}
+	for name, values := range opts.Headers {
+		if clone.headers == nil {
+			clone.headers = http.Header{}
+		}
+		clone.headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	if opts.Logger != nil {
+		clone.logger = opts.Logger
+	}
+	clone.registerServices()
+	return clone
+}
+
+// Kong returns the underlying go-kong Client.
+// Its requests ignore the settings of Clone and WithRequestOptions.
+func (c *Client) Kong() *kong.Client {
+	return c.kong
+}
+
+// NewRequest creates a request against the workspace of the Client,
+// which defaults to the one configured in the underlying go-kong Client.
+// endpoint should be relative to the Admin API address.
+// The body of the request is replayable, even if it is an io.Reader.
+func (c *Client) NewRequest(method, endpoint string, qs interface{},
+	body interface{},
+) (*http.Request, error) {
+	baseURL := c.kong.BaseRootURL()
+	workspace := c.kong.Workspace()
+	if c.workspace != nil {
+		workspace = *c.workspace
+	}
+	if workspace != "" {
+		baseURL += "/" + workspace
+	}
+	req, err := c.kong.NewRequestRaw(method, baseURL, endpoint, qs, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := bufferBody(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// NewRootRequest creates a request for an endpoint which is not
+// scoped to a workspace, like /config or /status.
+func (c *Client) NewRootRequest(method, endpoint string, qs interface{},
+	body interface{},
+) (*http.Request, error) {
+	req, err := c.kong.NewRequestRaw(method, c.kong.BaseRootURL(), endpoint, qs, body)
+	if err != nil {
+		return nil, err
+	}
+	if err := bufferBody(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// Do executes a HTTP request and decodes the JSON response into v.
+// If v is an io.Writer, the raw response body is written to it instead.
+// Non 2xx/3xx responses are returned as *kong.APIError.
+// The body of req is sent again from the start, so req can be reused.
+func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
+	resp, err := c.doRaw(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if err = hasError(resp); err != nil {
+		return resp, err
+	}
+
+	if v != nil {
+		if writer, ok := v.(io.Writer); ok {
+			_, err = io.Copy(writer, resp.Body)
+		} else {
+			err = json.NewDecoder(resp.Body).Decode(v)
+			if err == io.EOF {
+				err = nil
+			}
+		}
+		if err != nil {
+			return resp, err
+		}
+	}
+	return resp, nil
+}
@@ -0,0 +1,101 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// ClusteringService handles data plane nodes connected to a
+// Kong control plane in hybrid mode.
+type ClusteringService service
+
+// DataPlane represents a data plane node connected to the control plane.
+type DataPlane struct {
+	ID         *string           `json:"id,omitempty"`
+	Hostname   *string           `json:"hostname,omitempty"`
+	IP         *string           `json:"ip,omitempty"`
+	ConfigHash *string           `json:"config_hash,omitempty"`
+	LastSeen   *int64            `json:"last_seen,omitempty"`
+	Version    *string           `json:"version,omitempty"`
+	SyncStatus *string           `json:"sync_status,omitempty"`
+	TTL        *int64            `json:"ttl,omitempty"`
+	Labels     map[string]string `json:"labels,omitempty"`
+}
+
+// Status fetches the status of all data planes connected to the
+// control plane, keyed by data plane ID.
+func (s *ClusteringService) Status(ctx context.Context) (map[string]*DataPlane, error) {
+	req, err := s.client.NewRootRequest(http.MethodGet, "/clustering/status", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var status map[string]*DataPlane
+	_, err = s.client.Do(ctx, req, &status)
+	if err != nil {
+		return nil, err
+	}
+	for id, dp := range status {
+		if dp.ID == nil {
+			dp.ID = kong.String(id)
+		}
+	}
+	return status, nil
+}
+
+// ListDataPlanes fetches a list of data planes.
+// opt can be used to control pagination.
+func (s *ClusteringService) ListDataPlanes(ctx context.Context,
+	opt *kong.ListOpt,
+) ([]*DataPlane, *kong.ListOpt, error) {
+	data, next, err := s.client.list(ctx, "/clustering/data-planes", opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	var dataPlanes []*DataPlane
+	for _, object := range data {
+		var dataPlane DataPlane
+		if err := json.Unmarshal(object, &dataPlane); err != nil {
+			return nil, nil, err
+		}
+		dataPlanes = append(dataPlanes, &dataPlane)
+	}
+	return dataPlanes, next, nil
+}
+
+// ListAllDataPlanes fetches all data planes.
+func (s *ClusteringService) ListAllDataPlanes(ctx context.Context) ([]*DataPlane, error) {
+	var dataPlanes, data []*DataPlane
+	var err error
+	opt := &kong.ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.ListDataPlanes(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		dataPlanes = append(dataPlanes, data...)
+	}
+	return dataPlanes, nil
+}
+
+// OutOfSync returns the data planes which have not loaded
+// the configuration identified by configHash yet.
+// An empty result means the whole cluster runs that configuration.
+func (s *ClusteringService) OutOfSync(ctx context.Context,
+	configHash string,
+) ([]*DataPlane, error) {
+	dataPlanes, err := s.ListAllDataPlanes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var res []*DataPlane
+	for _, dp := range dataPlanes {
+		if dp.ConfigHash == nil || *dp.ConfigHash != configHash {
+			res = append(res, dp)
+		}
+	}
+	return res, nil
+}
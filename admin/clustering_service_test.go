@@ -0,0 +1,43 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusteringService(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/clustering/status":
+			_, _ = w.Write([]byte(`{"dp-1":{"config_hash":"a","hostname":"node1"}}`))
+		case "/clustering/data-planes":
+			if r.URL.Query().Get("offset") == "" {
+				_, _ = w.Write([]byte(`{"data":[{"id":"dp-1","config_hash":"a"}],"offset":"page2"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"dp-2","config_hash":"b"}],"next":null}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	status, err := client.Clustering.Status(ctx)
+	require.NoError(t, err)
+	require.Contains(t, status, "dp-1")
+	assert.Equal(t, "dp-1", *status["dp-1"].ID)
+	assert.Equal(t, "node1", *status["dp-1"].Hostname)
+
+	dataPlanes, err := client.Clustering.ListAllDataPlanes(ctx)
+	require.NoError(t, err)
+	assert.Len(t, dataPlanes, 2)
+
+	outOfSync, err := client.Clustering.OutOfSync(ctx, "a")
+	require.NoError(t, err)
+	require.Len(t, outOfSync, 1)
+	assert.Equal(t, "dp-2", *outOfSync[0].ID)
+}
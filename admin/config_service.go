@@ -0,0 +1,168 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+	"sigs.k8s.io/yaml"
+)
+
+// ConfigService handles the declarative configuration of
+// DB-less and hybrid mode Kong nodes.
+type ConfigService service
+
+// ConfigLoadResult is the outcome of a successful configuration load.
+type ConfigLoadResult struct {
+	// ConfigurationHash is the hash of the configuration
+	// currently loaded by the node.
+	ConfigurationHash string
+}
+
+// ConfigError is returned when Kong rejects a declarative configuration.
+type ConfigError struct {
+	HTTPCode int `json:"-"`
+
+	// ErrorCode is Kong's internal error code.
+	ErrorCode int                    `json:"code"`
+	Name      string                 `json:"name"`
+	Message   string                 `json:"message"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+
+	// FlattenedErrors is populated only if errors were
+	// requested in flattened form.
+	FlattenedErrors []FlattenedError `json:"flattened_errors,omitempty"`
+}
+
+// FlattenedError holds all validation errors of a single entity.
+type FlattenedError struct {
+	EntityType string                 `json:"entity_type"`
+	EntityName *string                `json:"entity_name,omitempty"`
+	EntityID   *string                `json:"entity_id,omitempty"`
+	EntityTags []string               `json:"entity_tags,omitempty"`
+	Entity     map[string]interface{} `json:"entity,omitempty"`
+	Errors     []FlattenedFieldError  `json:"errors,omitempty"`
+}
+
+// FlattenedFieldError is a single validation error of an entity.
+type FlattenedFieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+	Type    string `json:"type"`
+}
+
+func (e *ConfigError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "HTTP status %d (message: %q)", e.HTTPCode, e.Message)
+	for _, fe := range e.FlattenedErrors {
+		name := fe.EntityType
+		if fe.EntityName != nil {
+			name += " " + *fe.EntityName
+		} else if fe.EntityID != nil {
+			name += " " + *fe.EntityID
+		}
+		for _, err := range fe.Errors {
+			if err.Field != "" {
+				fmt.Fprintf(&b, "\n\t%s: %s: %s", name, err.Field, err.Message)
+			} else {
+				fmt.Fprintf(&b, "\n\t%s: %s", name, err.Message)
+			}
+		}
+	}
+	return b.String()
+}
+
+// Code returns the HTTP status code for the error.
+func (e *ConfigError) Code() int {
+	return e.HTTPCode
+}
+
+// LoadDeclarativeConfig sends config to the /config endpoint, replacing
+// the whole configuration of the node.
+// config can be a string, []byte, io.Reader or any value that is
+// serialized to JSON.
+// If flattenErrors is true, validation errors are reported per entity
+// in the returned *ConfigError.
+func (s *ConfigService) LoadDeclarativeConfig(ctx context.Context,
+	config interface{}, flattenErrors bool,
+) (*ConfigLoadResult, error) {
+	if config == nil {
+		return nil, fmt.Errorf("cannot load a nil configuration")
+	}
+	type loadConfigParams struct {
+		FlattenErrors int `url:"flatten_errors,omitempty"`
+	}
+	var params loadConfigParams
+	if flattenErrors {
+		params.FlattenErrors = 1
+	}
+
+	req, err := s.client.NewRootRequest(http.MethodPost, "/config", params, config)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.doRaw(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("posting configuration to /config: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("reading /config response body: %w", err)
+		}
+		configErr := &ConfigError{HTTPCode: resp.StatusCode}
+		if err := json.Unmarshal(body, configErr); err != nil {
+			return nil, kong.NewAPIErrorWithRaw(resp.StatusCode, messageFromBody(body), body)
+		}
+		return nil, configErr
+	}
+
+	var status struct {
+		ConfigurationHash string `json:"configuration_hash"`
+	}
+	req, err = s.client.NewRootRequest(http.MethodGet, "/status", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.client.Do(ctx, req, &status); err != nil {
+		return nil, fmt.Errorf("fetching configuration hash: %w", err)
+	}
+	return &ConfigLoadResult{ConfigurationHash: status.ConfigurationHash}, nil
+}
+
+// ExportRaw fetches the declarative configuration currently loaded
+// by the node and returns it in YAML format.
+func (s *ConfigService) ExportRaw(ctx context.Context) ([]byte, error) {
+	req, err := s.client.NewRootRequest(http.MethodGet, "/config", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var export struct {
+		Config string `json:"config"`
+	}
+	if _, err := s.client.Do(ctx, req, &export); err != nil {
+		return nil, fmt.Errorf("fetching configuration from /config: %w", err)
+	}
+	return []byte(export.Config), nil
+}
+
+// Export fetches the declarative configuration currently loaded
+// by the node and unmarshals it into v.
+// v is usually a *file.Content, so that it can be compared with
+// the configuration read from state files.
+func (s *ConfigService) Export(ctx context.Context, v interface{}) error {
+	raw, err := s.ExportRaw(ctx)
+	if err != nil {
+		return err
+	}
+	if err := yaml.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("unmarshaling configuration: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	kongClient, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+	client, err := NewClient(kongClient)
+	require.NoError(t, err)
+	return client
+}
+
+func TestConfigServiceLoadDeclarativeConfig(t *testing.T) {
+	var body string
+	var flatten string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config":
+			assert.Equal(t, http.MethodPost, r.Method)
+			b, _ := io.ReadAll(r.Body)
+			body = string(b)
+			flatten = r.URL.Query().Get("flatten_errors")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		case "/status":
+			_, _ = w.Write([]byte(`{"configuration_hash":"abc123"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	res, err := client.Config.LoadDeclarativeConfig(context.Background(),
+		map[string]interface{}{"_format_version": "3.0"}, true)
+	require.NoError(t, err)
+	assert.Equal(t, "abc123", res.ConfigurationHash)
+	assert.JSONEq(t, `{"_format_version":"3.0"}`, body)
+	assert.Equal(t, "1", flatten)
+
+	_, err = client.Config.LoadDeclarativeConfig(context.Background(), nil, false)
+	assert.Error(t, err)
+}
+
+func TestConfigServiceLoadDeclarativeConfigErrors(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{
+			"code": 14,
+			"name": "invalid declarative configuration",
+			"message": "declarative config is invalid: {}",
+			"flattened_errors": [{
+				"entity_type": "service",
+				"entity_name": "svc1",
+				"errors": [{"field": "host", "message": "required field missing", "type": "field"}]
+			}]
+		}`))
+	}))
+
+	_, err := client.Config.LoadDeclarativeConfig(context.Background(), "{}", true)
+	require.Error(t, err)
+	var configErr *ConfigError
+	require.True(t, errors.As(err, &configErr))
+	assert.Equal(t, http.StatusBadRequest, configErr.Code())
+	assert.Equal(t, 14, configErr.ErrorCode)
+	require.Len(t, configErr.FlattenedErrors, 1)
+	assert.Equal(t, "svc1", *configErr.FlattenedErrors[0].EntityName)
+	assert.Contains(t, err.Error(), "service svc1: host: required field missing")
+}
+
+func TestConfigServiceExport(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/config", r.URL.Path)
+		assert.Equal(t, http.MethodGet, r.Method)
+		_, _ = w.Write([]byte(`{"config":"_format_version: \"3.0\"\nservices:\n- name: svc1\n  host: example.com\n"}`))
+	}))
+
+	raw, err := client.Config.ExportRaw(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, string(raw), "name: svc1")
+
+	var content struct {
+		FormatVersion string `json:"_format_version"`
+		Services      []struct {
+			Name string `json:"name"`
+			Host string `json:"host"`
+		} `json:"services"`
+	}
+	require.NoError(t, client.Config.Export(context.Background(), &content))
+	assert.Equal(t, "3.0", content.FormatVersion)
+	require.Len(t, content.Services, 1)
+	assert.Equal(t, "example.com", content.Services[0].Host)
+}
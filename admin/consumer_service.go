@@ -0,0 +1,127 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// ConsumerCredentials holds all credentials associated to a Consumer.
+type ConsumerCredentials struct {
+	KeyAuths   []*kong.KeyAuth
+	BasicAuths []*kong.BasicAuth
+	HMACAuths  []*kong.HMACAuth
+	JWTAuths   []*kong.JWTAuth
+	ACLGroups  []*kong.ACLGroup
+	Oauth2s    []*kong.Oauth2Credential
+	MTLSAuths  []*kong.MTLSAuth
+}
+
+// Count returns the total number of credentials.
+func (c *ConsumerCredentials) Count() int {
+	return len(c.KeyAuths) + len(c.BasicAuths) + len(c.HMACAuths) +
+		len(c.JWTAuths) + len(c.ACLGroups) + len(c.Oauth2s) + len(c.MTLSAuths)
+}
+
+// GetByCustomID fetches the Consumer with customID.
+// A *kong.APIError with a 404 code is returned if there is none.
+func (s *ConsumerService) GetByCustomID(ctx context.Context,
+	customID string,
+) (*kong.Consumer, error) {
+	if customID == "" {
+		return nil, fmt.Errorf("customID cannot be empty")
+	}
+	qs := struct {
+		CustomID string `url:"custom_id"`
+	}{CustomID: customID}
+	var list struct {
+		Data []*kong.Consumer `json:"data"`
+	}
+	if err := s.client.Send(ctx, http.MethodGet, "/consumers", qs, nil, &list); err != nil {
+		return nil, err
+	}
+	if len(list.Data) == 0 {
+		return nil, kong.NewAPIError(http.StatusNotFound, "Not found")
+	}
+	return list.Data[0], nil
+}
+
+// ListAllPlugins fetches all plugins configured for a Consumer.
+func (s *ConsumerService) ListAllPlugins(ctx context.Context,
+	consumerUsernameOrID string,
+) ([]*kong.Plugin, error) {
+	if consumerUsernameOrID == "" {
+		return nil, fmt.Errorf("consumerUsernameOrID cannot be empty")
+	}
+	return NewEntityService[kong.Plugin](s.client, "/consumers/{consumer}/plugins").
+		With(consumerUsernameOrID).ListAll(ctx)
+}
+
+// ListAllConsumerGroups fetches all ConsumerGroups a Consumer is a member of.
+func (s *ConsumerService) ListAllConsumerGroups(ctx context.Context,
+	consumerUsernameOrID string,
+) ([]*kong.ConsumerGroup, error) {
+	if consumerUsernameOrID == "" {
+		return nil, fmt.Errorf("consumerUsernameOrID cannot be empty")
+	}
+	return NewEntityService[kong.ConsumerGroup](s.client, "/consumers/{consumer}/consumer_groups").
+		With(consumerUsernameOrID).ListAll(ctx)
+}
+
+// listCredentials fetches all credentials of a Consumer at the nested
+// endpoint of an authentication plugin.
+// If the plugin is not installed on the node, no credentials are returned.
+func listCredentials[T any](ctx context.Context, c *Client,
+	consumerID, endpoint string,
+) ([]*T, error) {
+	res, err := NewEntityService[T](c, "/consumers/{consumer}/"+endpoint).
+		With(consumerID).ListAll(ctx)
+	if kong.IsNotFoundErr(err) {
+		return nil, nil
+	}
+	return res, err
+}
+
+// ListCredentials fetches all credentials associated to a Consumer,
+// across all authentication plugins installed on the node.
+func (s *ConsumerService) ListCredentials(ctx context.Context,
+	consumerUsernameOrID string,
+) (*ConsumerCredentials, error) {
+	if consumerUsernameOrID == "" {
+		return nil, fmt.Errorf("consumerUsernameOrID cannot be empty")
+	}
+	// resolve the consumer first so that a missing consumer is
+	// not mistaken for a missing plugin
+	consumer, err := NewEntityService[kong.Consumer](s.client, "/consumers").
+		Get(ctx, consumerUsernameOrID)
+	if err != nil {
+		return nil, err
+	}
+	id := *consumer.ID
+
+	var creds ConsumerCredentials
+	if creds.KeyAuths, err = listCredentials[kong.KeyAuth](ctx, s.client, id, "key-auth"); err != nil {
+		return nil, err
+	}
+	if creds.BasicAuths, err = listCredentials[kong.BasicAuth](ctx, s.client, id, "basic-auth"); err != nil {
+		return nil, err
+	}
+	if creds.HMACAuths, err = listCredentials[kong.HMACAuth](ctx, s.client, id, "hmac-auth"); err != nil {
+		return nil, err
+	}
+	if creds.JWTAuths, err = listCredentials[kong.JWTAuth](ctx, s.client, id, "jwt"); err != nil {
+		return nil, err
+	}
+	if creds.ACLGroups, err = listCredentials[kong.ACLGroup](ctx, s.client, id, "acls"); err != nil {
+		return nil, err
+	}
+	if creds.Oauth2s, err = listCredentials[kong.Oauth2Credential](ctx, s.client, id, "oauth2"); err != nil {
+		return nil, err
+	}
+	if creds.MTLSAuths, err = listCredentials[kong.MTLSAuth](ctx, s.client, id, "mtls-auth"); err != nil {
+		return nil, err
+	}
+	return &creds, nil
+}
@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerServiceGetByCustomID(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/consumers", r.URL.Path)
+		if r.URL.Query().Get("custom_id") == "user-42" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"c1","username":"alice","custom_id":"user-42"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	ctx := context.Background()
+
+	consumer, err := client.Consumers.GetByCustomID(ctx, "user-42")
+	require.NoError(t, err)
+	assert.Equal(t, "c1", *consumer.ID)
+
+	_, err = client.Consumers.GetByCustomID(ctx, "missing")
+	assert.True(t, kong.IsNotFoundErr(err))
+
+	_, err = client.Consumers.GetByCustomID(ctx, "")
+	assert.Error(t, err)
+}
+
+func TestConsumerServiceNestedListing(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/consumers/alice", "/consumers/c1":
+			_, _ = w.Write([]byte(`{"id":"c1","username":"alice"}`))
+		case "/consumers/alice/plugins":
+			_, _ = w.Write([]byte(`{"data":[{"id":"p1","name":"rate-limiting"}]}`))
+		case "/consumers/alice/consumer_groups":
+			_, _ = w.Write([]byte(`{"data":[{"id":"g1","name":"gold"}]}`))
+		case "/consumers/c1/key-auth":
+			_, _ = w.Write([]byte(`{"data":[{"id":"k1","key":"secret"},{"id":"k2","key":"other"}]}`))
+		case "/consumers/c1/acls":
+			_, _ = w.Write([]byte(`{"data":[{"id":"a1","group":"admins"}]}`))
+		case "/consumers/c1/basic-auth", "/consumers/c1/hmac-auth", "/consumers/c1/jwt",
+			"/consumers/c1/oauth2":
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		default:
+			// mtls-auth is not installed
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		}
+	}))
+	ctx := context.Background()
+
+	plugins, err := client.Consumers.ListAllPlugins(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "rate-limiting", *plugins[0].Name)
+
+	groups, err := client.Consumers.ListAllConsumerGroups(ctx, "alice")
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "gold", *groups[0].Name)
+
+	creds, err := client.Consumers.ListCredentials(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 3, creds.Count())
+	assert.Len(t, creds.KeyAuths, 2)
+	assert.Len(t, creds.ACLGroups, 1)
+	assert.Empty(t, creds.MTLSAuths)
+
+	_, err = client.Consumers.ListCredentials(ctx, "bob")
+	assert.True(t, kong.IsNotFoundErr(err))
+}
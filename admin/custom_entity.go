@@ -0,0 +1,45 @@
+package admin
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/kong/go-kong/kong/custom"
+)
+
+var customPathParamRegex = regexp.MustCompile(`\$\{(\w+)\}`)
+
+// CustomEntities returns an untyped EntityService for entities served at
+// pathTemplate, such as the Admin API endpoints registered by
+// custom plugins, e.g. `/my-plugin/records`.
+// Use NewEntityService instead when a Go type for the entity exists.
+func (c *Client) CustomEntities(pathTemplate string) *EntityService[custom.Object] {
+	return NewEntityService[custom.Object](c, pathTemplate)
+}
+
+// NewEntityServiceFromDefinition returns an EntityService for entities
+// described by a go-kong custom entity definition.
+// Parameters in the `${param}` form in definition.CRUDPath become path
+// parameters of the service, to be bound with With.
+func NewEntityServiceFromDefinition[T any](client *Client,
+	definition custom.EntityCRUDDefinition,
+) *EntityService[T] {
+	path := customPathParamRegex.ReplaceAllString(definition.CRUDPath, "{$1}")
+	return NewEntityService[T](client, path)
+}
+
+// Send sends a request to an arbitrary endpoint of the workspace
+// configured in the underlying go-kong Client and decodes the response
+// into v, which can be nil.
+// It is meant for non-CRUD endpoints, like actions exposed by
+// custom plugins.
+func (c *Client) Send(ctx context.Context, method, endpoint string,
+	qs interface{}, body interface{}, v interface{},
+) error {
+	req, err := c.NewRequest(method, endpoint, qs, body)
+	if err != nil {
+		return err
+	}
+	_, err = c.Do(ctx, req, v)
+	return err
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/kong/go-kong/kong/custom"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCustomEntities(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.Method+" "+r.URL.Path)
+		switch r.URL.Path {
+		case "/my-plugin/records":
+			_, _ = w.Write([]byte(`{"data":[{"id":"r1","value":42}]}`))
+		case "/my-plugin/records/r1":
+			_, _ = w.Write([]byte(`{"id":"r1","value":42}`))
+		case "/my-plugin/purge":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"message":"nothing to purge"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	records, err := client.CustomEntities("/my-plugin/records").ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.Equal(t, float64(42), (*records[0])["value"])
+
+	record, err := client.CustomEntities("/my-plugin/records").Get(ctx, "r1")
+	require.NoError(t, err)
+	assert.Equal(t, "r1", (*record)["id"])
+
+	err = client.Send(ctx, http.MethodPost, "/my-plugin/purge", nil, nil, nil)
+	require.Error(t, err)
+	var apiErr *kong.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code())
+
+	assert.Equal(t, []string{
+		"GET /my-plugin/records",
+		"GET /my-plugin/records/r1",
+		"POST /my-plugin/purge",
+	}, paths)
+}
+
+func TestNewEntityServiceFromDefinition(t *testing.T) {
+	client, err := NewClient(&kong.Client{})
+	require.NoError(t, err)
+	s := NewEntityServiceFromDefinition[custom.Object](client, custom.EntityCRUDDefinition{
+		Name:       "my-creds",
+		CRUDPath:   "/consumers/${consumer_id}/my-creds",
+		PrimaryKey: "id",
+	})
+	assert.Equal(t, "/consumers/{consumer_id}/my-creds", s.Path())
+	assert.Equal(t, "/consumers/c1/my-creds", s.With("c1").Path())
+}
@@ -0,0 +1,118 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// identityFields are never cleared by ClearUnsetFields.
+var identityFields = map[string]bool{"id": true, "name": true, "username": true}
+
+// FillEntityDefaults sets the fields of entity which are not set to
+// their default value, from the schema of entityType, e.g. "services".
+// Plugins are filled from the schema of their own name.
+// entity must be a pointer to a go-kong entity, e.g. *kong.Service.
+func (s *SchemaService) FillEntityDefaults(ctx context.Context, entityType string,
+	entity interface{},
+) error {
+	if plugin, ok := entity.(*kong.Plugin); ok {
+		if plugin.Name == nil {
+			return fmt.Errorf("plugin name is required to fill its defaults")
+		}
+		schema, err := s.GetPluginSchema(ctx, *plugin.Name)
+		if err != nil {
+			return fmt.Errorf("fetching schema of plugin %s: %w", *plugin.Name, err)
+		}
+		return kong.FillPluginsDefaults(plugin, schema)
+	}
+	schema, err := s.Get(ctx, entityType)
+	if err != nil {
+		return fmt.Errorf("fetching schema of %s: %w", entityType, err)
+	}
+	return kong.FillEntityDefaults(entity, schema)
+}
+
+// ClearUnsetFields clears the fields of entity which hold their default
+// value, from the schema of entityType, as if they had not been set.
+// Nested records, such as the configuration of plugins, are cleared
+// field by field. Identity fields, like name, are kept.
+// It is the reverse of FillEntityDefaults, to export entities without
+// defaults which would churn when Kong changes them.
+func (s *SchemaService) ClearUnsetFields(ctx context.Context, entityType string,
+	entity interface{},
+) error {
+	value := reflect.ValueOf(entity)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("entity must be a non-nil pointer, got %T", entity)
+	}
+	defaults := reflect.New(value.Elem().Type()).Interface()
+	if plugin, ok := entity.(*kong.Plugin); ok {
+		defaults.(*kong.Plugin).Name = plugin.Name
+	}
+	if err := s.FillEntityDefaults(ctx, entityType, defaults); err != nil {
+		return err
+	}
+
+	fields, err := toJSONMap(entity)
+	if err != nil {
+		return err
+	}
+	defaultFields, err := toJSONMap(defaults)
+	if err != nil {
+		return err
+	}
+	clearDefaults(fields, defaultFields, true)
+
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	cleared := reflect.New(value.Elem().Type())
+	if err := json.Unmarshal(b, cleared.Interface()); err != nil {
+		return err
+	}
+	value.Elem().Set(cleared.Elem())
+	return nil
+}
+
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// clearDefaults deletes the fields of fields equal to their value in
+// defaults, recursing into records.
+func clearDefaults(fields, defaults map[string]interface{}, topLevel bool) {
+	for name, value := range fields {
+		if topLevel && identityFields[name] {
+			continue
+		}
+		def, ok := defaults[name]
+		if !ok {
+			continue
+		}
+		record, isRecord := value.(map[string]interface{})
+		defRecord, isDefRecord := def.(map[string]interface{})
+		if isRecord && isDefRecord {
+			clearDefaults(record, defRecord, false)
+			if len(record) == 0 {
+				delete(fields, name)
+			}
+			continue
+		}
+		if reflect.DeepEqual(value, def) {
+			delete(fields, name)
+		}
+	}
+}
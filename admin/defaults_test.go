@@ -0,0 +1,85 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	serviceSchema = `{"fields":[
+		{"id":{"type":"string","uuid":true,"auto":true}},
+		{"name":{"type":"string"}},
+		{"retries":{"type":"integer","default":5}},
+		{"protocol":{"type":"string","default":"http"}},
+		{"host":{"type":"string","required":true}},
+		{"port":{"type":"integer","default":80}},
+		{"connect_timeout":{"type":"integer","default":60000}}
+	]}`
+	rateLimitingSchema = `{"fields":[
+		{"protocols":{"type":"set","default":["grpc","grpcs","http","https"]}},
+		{"config":{"type":"record","fields":[
+			{"minute":{"type":"number"}},
+			{"policy":{"type":"string","default":"local"}},
+			{"fault_tolerant":{"type":"boolean","default":true}}
+		]}}
+	]}`
+)
+
+func newSchemaTestClient(t *testing.T) *Client {
+	return newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/services":
+			_, _ = w.Write([]byte(serviceSchema))
+		case "/schemas/plugins/rate-limiting":
+			_, _ = w.Write([]byte(rateLimitingSchema))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestFillAndClearServiceDefaults(t *testing.T) {
+	client := newSchemaTestClient(t)
+	ctx := context.Background()
+
+	svc := &kong.Service{
+		Name:    kong.String("svc"),
+		Host:    kong.String("example.com"),
+		Retries: kong.Int(3),
+	}
+	original := svc.DeepCopy()
+	require.NoError(t, client.Schemas.FillEntityDefaults(ctx, "services", svc))
+	assert.Equal(t, 3, *svc.Retries)
+	assert.Equal(t, "http", *svc.Protocol)
+	assert.Equal(t, 80, *svc.Port)
+	assert.Equal(t, 60000, *svc.ConnectTimeout)
+
+	require.NoError(t, client.Schemas.ClearUnsetFields(ctx, "services", svc))
+	assert.Equal(t, original, svc)
+}
+
+func TestFillAndClearPluginDefaults(t *testing.T) {
+	client := newSchemaTestClient(t)
+	ctx := context.Background()
+
+	plugin := &kong.Plugin{
+		Name:   kong.String("rate-limiting"),
+		Config: kong.Configuration{"minute": 10, "policy": "redis"},
+	}
+	require.NoError(t, client.Schemas.FillEntityDefaults(ctx, "plugins", plugin))
+	assert.Equal(t, true, plugin.Config["fault_tolerant"])
+	assert.Len(t, plugin.Protocols, 4)
+
+	require.NoError(t, client.Schemas.ClearUnsetFields(ctx, "plugins", plugin))
+	assert.Equal(t, &kong.Plugin{
+		Name:   kong.String("rate-limiting"),
+		Config: kong.Configuration{"minute": float64(10), "policy": "redis"},
+	}, plugin)
+
+	assert.Error(t, client.Schemas.ClearUnsetFields(ctx, "services", kong.Service{}))
+}
@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// DegraphqlRouteService handles the routes of the DeGraphQL plugin,
+// which map REST endpoints of a Service to GraphQL queries
+// (Kong Enterprise only).
+// They are nested under the Service exposing the GraphQL API.
+type DegraphqlRouteService service
+
+func (s *DegraphqlRouteService) entities(serviceNameOrID string) *EntityService[kong.DegraphqlRoute] {
+	return NewEntityService[kong.DegraphqlRoute](s.client, "/services/{service}/degraphql/routes").
+		With(serviceNameOrID)
+}
+
+// Create creates a DeGraphQL route for a Service.
+func (s *DegraphqlRouteService) Create(ctx context.Context,
+	serviceNameOrID string, route *kong.DegraphqlRoute,
+) (*kong.DegraphqlRoute, error) {
+	if serviceNameOrID == "" {
+		return nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	if route == nil {
+		return nil, fmt.Errorf("cannot create a nil DeGraphQL route")
+	}
+	return s.entities(serviceNameOrID).Create(ctx, route)
+}
+
+// Get fetches a DeGraphQL route of a Service by its ID.
+func (s *DegraphqlRouteService) Get(ctx context.Context,
+	serviceNameOrID, id string,
+) (*kong.DegraphqlRoute, error) {
+	if serviceNameOrID == "" {
+		return nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return s.entities(serviceNameOrID).Get(ctx, id)
+}
+
+// Delete deletes a DeGraphQL route of a Service by its ID.
+func (s *DegraphqlRouteService) Delete(ctx context.Context,
+	serviceNameOrID, id string,
+) error {
+	if serviceNameOrID == "" {
+		return fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return s.entities(serviceNameOrID).Delete(ctx, id)
+}
+
+// List fetches a page of the DeGraphQL routes of a Service.
+// opt can be used to control pagination.
+func (s *DegraphqlRouteService) List(ctx context.Context,
+	serviceNameOrID string, opt *kong.ListOpt,
+) ([]*kong.DegraphqlRoute, *kong.ListOpt, error) {
+	if serviceNameOrID == "" {
+		return nil, nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return s.entities(serviceNameOrID).List(ctx, opt)
+}
+
+// ListAll fetches all the DeGraphQL routes of a Service.
+func (s *DegraphqlRouteService) ListAll(ctx context.Context,
+	serviceNameOrID string,
+) ([]*kong.DegraphqlRoute, error) {
+	if serviceNameOrID == "" {
+		return nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return s.entities(serviceNameOrID).ListAll(ctx)
+}
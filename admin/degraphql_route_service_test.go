@@ -0,0 +1,60 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDegraphqlRouteService(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/services/graphql/degraphql/routes" && r.Method == http.MethodPost:
+			var route kong.DegraphqlRoute
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&route))
+			route.ID = kong.String("d1")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(route)
+		case r.URL.Path == "/services/graphql/degraphql/routes":
+			_, _ = w.Write([]byte(`{"data":[{"id":"d1","uri":"/users","query":"query { users { id } }"}]}`))
+		case r.URL.Path == "/services/graphql/degraphql/routes/d1" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	created, err := client.DegraphqlRoutes.Create(ctx, "graphql", &kong.DegraphqlRoute{
+		URI:   kong.String("/users"),
+		Query: kong.String("query { users { id } }"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "d1", *created.ID)
+
+	routes, err := client.DegraphqlRoutes.ListAll(ctx, "graphql")
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+	assert.Equal(t, "/users", *routes[0].URI)
+
+	require.NoError(t, client.DegraphqlRoutes.Delete(ctx, "graphql", "d1"))
+
+	_, err = client.DegraphqlRoutes.Get(ctx, "graphql", "missing")
+	assert.True(t, kong.IsNotFoundErr(err))
+	_, err = client.DegraphqlRoutes.ListAll(ctx, "")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"POST /services/graphql/degraphql/routes",
+		"GET /services/graphql/degraphql/routes",
+		"DELETE /services/graphql/degraphql/routes/d1",
+		"GET /services/graphql/degraphql/routes/missing",
+	}, requests)
+}
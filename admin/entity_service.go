@@ -0,0 +1,180 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+)
+
+var pathParamRegex = regexp.MustCompile(`{[^{}/]+}`)
+
+// EntityService handles CRUD operations on an arbitrary entity of type
+// T, such as an entity not yet supported by go-kong or a DAO of a
+// custom plugin.
+//
+// Entities are served under a collection path, which may contain
+// parameters in braces, e.g. `/consumers/{consumer}/my-credentials`.
+// Parameters are bound with With before sending requests.
+type EntityService[T any] struct {
+	client *Client
+	path   string
+}
+
+// NewEntityService returns an EntityService for entities of type T
+// served at pathTemplate.
+func NewEntityService[T any](client *Client, pathTemplate string) *EntityService[T] {
+	return &EntityService[T]{
+		client: client,
+		path:   "/" + strings.Trim(pathTemplate, "/"),
+	}
+}
+
+// With returns a copy of the service where the parameters of the
+// path are replaced, in order, by values.
+func (s *EntityService[T]) With(values ...string) *EntityService[T] {
+	i := 0
+	path := pathParamRegex.ReplaceAllStringFunc(s.path, func(param string) string {
+		if i >= len(values) {
+			return param
+		}
+		value := url.PathEscape(values[i])
+		i++
+		return value
+	})
+	return &EntityService[T]{client: s.client, path: path}
+}
+
+// Path returns the collection path of the service.
+func (s *EntityService[T]) Path() string {
+	return s.path
+}
+
+func (s *EntityService[T]) endpoint(nameOrID string) (string, error) {
+	if param := pathParamRegex.FindString(s.path); param != "" {
+		return "", fmt.Errorf("path parameter %s of %s is not set", param, s.path)
+	}
+	if nameOrID == "" {
+		return s.path, nil
+	}
+	return s.path + "/" + url.PathEscape(nameOrID), nil
+}
+
+func (s *EntityService[T]) do(ctx context.Context, method, nameOrID string,
+	body interface{},
+) (*T, error) {
+	endpoint, err := s.endpoint(nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest(method, endpoint, nil, body)
+	if err != nil {
+		return nil, err
+	}
+	if method == http.MethodDelete {
+		_, err = s.client.Do(ctx, req, nil)
+		return nil, err
+	}
+	var entity T
+	if _, err = s.client.Do(ctx, req, &entity); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
+
+// Create creates an entity in Kong.
+func (s *EntityService[T]) Create(ctx context.Context, entity *T) (*T, error) {
+	if entity == nil {
+		return nil, fmt.Errorf("cannot create a nil entity")
+	}
+	return s.do(ctx, http.MethodPost, "", entity)
+}
+
+// Get fetches an entity by its name or ID.
+func (s *EntityService[T]) Get(ctx context.Context, nameOrID string) (*T, error) {
+	if nameOrID == "" {
+		return nil, fmt.Errorf("nameOrID cannot be empty for Get operation")
+	}
+	return s.do(ctx, http.MethodGet, nameOrID, nil)
+}
+
+// Update updates the fields of an existing entity which are set in entity.
+func (s *EntityService[T]) Update(ctx context.Context, nameOrID string, entity *T) (*T, error) {
+	if nameOrID == "" {
+		return nil, fmt.Errorf("nameOrID cannot be empty for Update operation")
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("cannot update a nil entity")
+	}
+	return s.do(ctx, http.MethodPatch, nameOrID, entity)
+}
+
+// Upsert creates or replaces the entity identified by nameOrID.
+func (s *EntityService[T]) Upsert(ctx context.Context, nameOrID string, entity *T) (*T, error) {
+	if nameOrID == "" {
+		return nil, fmt.Errorf("nameOrID cannot be empty for Upsert operation")
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("cannot upsert a nil entity")
+	}
+	return s.do(ctx, http.MethodPut, nameOrID, entity)
+}
+
+// Delete deletes an entity by its name or ID.
+func (s *EntityService[T]) Delete(ctx context.Context, nameOrID string) error {
+	if nameOrID == "" {
+		return fmt.Errorf("nameOrID cannot be empty for Delete operation")
+	}
+	_, err := s.do(ctx, http.MethodDelete, nameOrID, nil)
+	return err
+}
+
+// List fetches a page of entities.
+// opt can be used to control pagination and filter by tags.
+func (s *EntityService[T]) List(ctx context.Context,
+	opt *kong.ListOpt,
+) ([]*T, *kong.ListOpt, error) {
+	endpoint, err := s.endpoint("")
+	if err != nil {
+		return nil, nil, err
+	}
+	data, next, err := s.client.list(ctx, endpoint, opt)
+	if err != nil {
+		return nil, nil, err
+	}
+	entities := make([]*T, 0, len(data))
+	for _, object := range data {
+		var entity T
+		if err := json.Unmarshal(object, &entity); err != nil {
+			return nil, nil, err
+		}
+		entities = append(entities, &entity)
+	}
+	return entities, next, nil
+}
+
+// ListAll fetches all entities.
+func (s *EntityService[T]) ListAll(ctx context.Context) ([]*T, error) {
+	var entities, data []*T
+	var err error
+	opt := &kong.ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		entities = append(entities, data...)
+	}
+	return entities, nil
+}
+
+// Stream streams all entities page by page.
+func (s *EntityService[T]) Stream(ctx context.Context, opt *kong.ListOpt) <-chan Page[*T] {
+	return Stream(ctx, s.List, opt)
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCredential struct {
+	ID     *string `json:"id,omitempty"`
+	Secret *string `json:"secret,omitempty"`
+}
+
+func TestEntityService(t *testing.T) {
+	type call struct {
+		method string
+		path   string
+		body   string
+	}
+	var calls []call
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body json.RawMessage
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		calls = append(calls, call{r.Method, r.URL.EscapedPath(), string(body)})
+		switch {
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && r.URL.Path == "/consumers/c 1/my-creds":
+			if r.URL.Query().Get("offset") == "" {
+				_, _ = w.Write([]byte(`{"data":[{"id":"1"}],"offset":"next"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data":[{"id":"2"}]}`))
+		case r.Method == http.MethodGet:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id":"1","secret":"s"}`))
+		}
+	}))
+	ctx := context.Background()
+	creds := NewEntityService[testCredential](client, "consumers/{consumer}/my-creds/")
+
+	_, err := creds.Get(ctx, "1")
+	assert.EqualError(t, err, "path parameter {consumer} of /consumers/{consumer}/my-creds is not set")
+	assert.Empty(t, calls)
+
+	consumerCreds := creds.With("c 1")
+	assert.Equal(t, "/consumers/c%201/my-creds", consumerCreds.Path())
+
+	cred, err := consumerCreds.Create(ctx, &testCredential{Secret: kong.String("s")})
+	require.NoError(t, err)
+	assert.Equal(t, "1", *cred.ID)
+
+	_, err = consumerCreds.Upsert(ctx, "1", &testCredential{Secret: kong.String("s")})
+	require.NoError(t, err)
+	_, err = consumerCreds.Update(ctx, "1", &testCredential{Secret: kong.String("t")})
+	require.NoError(t, err)
+
+	_, err = consumerCreds.Get(ctx, "2")
+	assert.True(t, kong.IsNotFoundErr(err))
+
+	all, err := consumerCreds.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "2", *all[1].ID)
+
+	require.NoError(t, consumerCreds.Delete(ctx, "1"))
+
+	assert.Equal(t, []call{
+		{http.MethodPost, "/consumers/c%201/my-creds", `{"secret":"s"}`},
+		{http.MethodPut, "/consumers/c%201/my-creds/1", `{"secret":"s"}`},
+		{http.MethodPatch, "/consumers/c%201/my-creds/1", `{"secret":"t"}`},
+		{http.MethodGet, "/consumers/c%201/my-creds/2", ""},
+		{http.MethodGet, "/consumers/c%201/my-creds", ""},
+		{http.MethodGet, "/consumers/c%201/my-creds", ""},
+		{http.MethodDelete, "/consumers/c%201/my-creds/1", ""},
+	}, calls)
+}
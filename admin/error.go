@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+func hasError(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode <= 399 {
+		return nil
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read error body: %w", err)
+	}
+	return kong.NewAPIErrorWithRaw(res.StatusCode, messageFromBody(body), body)
+}
+
+func messageFromBody(b []byte) string {
+	s := struct {
+		Message string
+	}{}
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Sprintf("<failed to parse response body: %v>", err)
+	}
+
+	return s.Message
+}
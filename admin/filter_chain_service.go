@@ -0,0 +1,197 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// Filter is a WebAssembly filter of a FilterChain.
+type Filter struct {
+	Name    *string          `json:"name,omitempty" yaml:"name,omitempty"`
+	Config  *json.RawMessage `json:"config,omitempty" yaml:"config,omitempty"`
+	Enabled *bool            `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+}
+
+// FilterChain represents a chain of WebAssembly filters attached
+// to a Service or a Route, available in Kong 3.4 and above.
+type FilterChain struct {
+	ID        *string       `json:"id,omitempty" yaml:"id,omitempty"`
+	Name      *string       `json:"name,omitempty" yaml:"name,omitempty"`
+	Enabled   *bool         `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Route     *kong.Route   `json:"route,omitempty" yaml:"route,omitempty"`
+	Service   *kong.Service `json:"service,omitempty" yaml:"service,omitempty"`
+	Filters   []*Filter     `json:"filters,omitempty" yaml:"filters,omitempty"`
+	CreatedAt *int          `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt *int          `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	Tags      []*string     `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// FriendlyName returns the endpoint key name or ID.
+func (f *FilterChain) FriendlyName() string {
+	if f.Name != nil {
+		return *f.Name
+	}
+	if f.ID != nil {
+		return *f.ID
+	}
+	return ""
+}
+
+// FilterChainService handles FilterChains in Kong.
+type FilterChainService service
+
+func (s *FilterChainService) entities() *EntityService[FilterChain] {
+	return NewEntityService[FilterChain](s.client, "/filter-chains")
+}
+
+func (s *FilterChainService) serviceEntities(serviceNameOrID string) *EntityService[FilterChain] {
+	return NewEntityService[FilterChain](s.client, "/services/{service}/filter-chains").
+		With(serviceNameOrID)
+}
+
+func (s *FilterChainService) routeEntities(routeNameOrID string) *EntityService[FilterChain] {
+	return NewEntityService[FilterChain](s.client, "/routes/{route}/filter-chains").
+		With(routeNameOrID)
+}
+
+// Create creates a FilterChain in Kong.
+// Either Service or Route must be set on filterChain.
+func (s *FilterChainService) Create(ctx context.Context,
+	filterChain *FilterChain,
+) (*FilterChain, error) {
+	if filterChain == nil {
+		return nil, fmt.Errorf("cannot create a nil filter chain")
+	}
+	if filterChain.ID != nil {
+		return s.entities().Upsert(ctx, *filterChain.ID, filterChain)
+	}
+	return s.entities().Create(ctx, filterChain)
+}
+
+// CreateForService creates a FilterChain attached to a Service.
+func (s *FilterChainService) CreateForService(ctx context.Context,
+	serviceNameOrID string, filterChain *FilterChain,
+) (*FilterChain, error) {
+	if serviceNameOrID == "" {
+		return nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return s.serviceEntities(serviceNameOrID).Create(ctx, filterChain)
+}
+
+// CreateForRoute creates a FilterChain attached to a Route.
+func (s *FilterChainService) CreateForRoute(ctx context.Context,
+	routeNameOrID string, filterChain *FilterChain,
+) (*FilterChain, error) {
+	if routeNameOrID == "" {
+		return nil, fmt.Errorf("routeNameOrID cannot be empty")
+	}
+	return s.routeEntities(routeNameOrID).Create(ctx, filterChain)
+}
+
+// Get fetches a FilterChain by its name or ID.
+func (s *FilterChainService) Get(ctx context.Context,
+	nameOrID string,
+) (*FilterChain, error) {
+	return s.entities().Get(ctx, nameOrID)
+}
+
+// Update updates the fields of a FilterChain which are set in filterChain.
+// filterChain.ID or filterChain.Name identifies the FilterChain.
+func (s *FilterChainService) Update(ctx context.Context,
+	filterChain *FilterChain,
+) (*FilterChain, error) {
+	if filterChain == nil {
+		return nil, fmt.Errorf("cannot update a nil filter chain")
+	}
+	return s.entities().Update(ctx, filterChain.FriendlyName(), filterChain)
+}
+
+// Delete deletes a FilterChain by its name or ID.
+func (s *FilterChainService) Delete(ctx context.Context, nameOrID string) error {
+	return s.entities().Delete(ctx, nameOrID)
+}
+
+// List fetches a page of FilterChains.
+// opt can be used to control pagination.
+func (s *FilterChainService) List(ctx context.Context,
+	opt *kong.ListOpt,
+) ([]*FilterChain, *kong.ListOpt, error) {
+	return s.entities().List(ctx, opt)
+}
+
+// ListAll fetches all FilterChains.
+func (s *FilterChainService) ListAll(ctx context.Context) ([]*FilterChain, error) {
+	return s.entities().ListAll(ctx)
+}
+
+// ListAllForService fetches all FilterChains attached to a Service.
+func (s *FilterChainService) ListAllForService(ctx context.Context,
+	serviceNameOrID string,
+) ([]*FilterChain, error) {
+	if serviceNameOrID == "" {
+		return nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return s.serviceEntities(serviceNameOrID).ListAll(ctx)
+}
+
+// ListAllForRoute fetches all FilterChains attached to a Route.
+func (s *FilterChainService) ListAllForRoute(ctx context.Context,
+	routeNameOrID string,
+) ([]*FilterChain, error) {
+	if routeNameOrID == "" {
+		return nil, fmt.Errorf("routeNameOrID cannot be empty")
+	}
+	return s.routeEntities(routeNameOrID).ListAll(ctx)
+}
+
+// SetFilters replaces the filters of a FilterChain.
+// Filters are executed in the order of filters.
+func (s *FilterChainService) SetFilters(ctx context.Context,
+	nameOrID string, filters []*Filter,
+) (*FilterChain, error) {
+	if len(filters) == 0 {
+		return nil, fmt.Errorf("a filter chain requires at least one filter")
+	}
+	return s.entities().Update(ctx, nameOrID, &FilterChain{Filters: filters})
+}
+
+// ReorderFilters changes the execution order of the filters of a
+// FilterChain to match names, which must list every filter of the chain
+// exactly once. Chains running the same filter more than once cannot be
+// reordered by name, use SetFilters instead.
+func (s *FilterChainService) ReorderFilters(ctx context.Context,
+	nameOrID string, names []string,
+) (*FilterChain, error) {
+	filterChain, err := s.Get(ctx, nameOrID)
+	if err != nil {
+		return nil, err
+	}
+	if len(names) != len(filterChain.Filters) {
+		return nil, fmt.Errorf("filter chain %s has %d filters, got %d names",
+			nameOrID, len(filterChain.Filters), len(names))
+	}
+	byName := make(map[string]*Filter, len(filterChain.Filters))
+	for _, filter := range filterChain.Filters {
+		if filter.Name == nil {
+			continue
+		}
+		if _, ok := byName[*filter.Name]; ok {
+			return nil, fmt.Errorf("filter chain %s runs filter %s more than once, "+
+				"set its filters in the new order instead", nameOrID, *filter.Name)
+		}
+		byName[*filter.Name] = filter
+	}
+	filters := make([]*Filter, 0, len(names))
+	for _, name := range names {
+		filter, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("filter %s not found in filter chain %s", name, nameOrID)
+		}
+		delete(byName, name)
+		filters = append(filters, filter)
+	}
+	return s.SetFilters(ctx, nameOrID, filters)
+}
@@ -0,0 +1,104 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterChainService(t *testing.T) {
+	stored := &FilterChain{
+		ID:      kong.String("fc1"),
+		Name:    kong.String("chain"),
+		Service: &kong.Service{ID: kong.String("svc1")},
+		Filters: []*Filter{
+			{Name: kong.String("a")},
+			{Name: kong.String("b")},
+			{Name: kong.String("c")},
+		},
+	}
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/services/svc1/filter-chains" && r.Method == http.MethodPost:
+			var fc FilterChain
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&fc))
+			fc.ID = kong.String("fc2")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(fc)
+		case r.URL.Path == "/routes/r1/filter-chains":
+			_, _ = w.Write([]byte(`{"data":[{"id":"fc3","route":{"id":"r1"}}]}`))
+		case r.URL.Path == "/filter-chains/chain" && r.Method == http.MethodGet:
+			_ = json.NewEncoder(w).Encode(stored)
+		case r.URL.Path == "/filter-chains/chain" && r.Method == http.MethodPatch:
+			var fc FilterChain
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&fc))
+			stored.Filters = fc.Filters
+			_ = json.NewEncoder(w).Encode(stored)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	created, err := client.FilterChains.CreateForService(ctx, "svc1", &FilterChain{
+		Filters: []*Filter{{Name: kong.String("a")}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "fc2", *created.ID)
+
+	chains, err := client.FilterChains.ListAllForRoute(ctx, "r1")
+	require.NoError(t, err)
+	require.Len(t, chains, 1)
+	assert.Equal(t, "r1", *chains[0].Route.ID)
+
+	reordered, err := client.FilterChains.ReorderFilters(ctx, "chain", []string{"c", "a", "b"})
+	require.NoError(t, err)
+	var names []string
+	for _, f := range reordered.Filters {
+		names = append(names, *f.Name)
+	}
+	assert.Equal(t, []string{"c", "a", "b"}, names)
+
+	_, err = client.FilterChains.ReorderFilters(ctx, "chain", []string{"c", "a", "d"})
+	assert.EqualError(t, err, "filter d not found in filter chain chain")
+	_, err = client.FilterChains.ReorderFilters(ctx, "chain", []string{"c"})
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"POST /services/svc1/filter-chains",
+		"GET /routes/r1/filter-chains",
+		"GET /filter-chains/chain",
+		"PATCH /filter-chains/chain",
+		"GET /filter-chains/chain",
+		"GET /filter-chains/chain",
+	}, requests)
+}
+
+func TestFilterChainServiceReorderDuplicateFilters(t *testing.T) {
+	stored := &FilterChain{
+		ID:   kong.String("fc1"),
+		Name: kong.String("chain"),
+		Filters: []*Filter{
+			{Name: kong.String("a")},
+			{Name: kong.String("b")},
+			{Name: kong.String("a"), Enabled: kong.Bool(false)},
+		},
+	}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		_ = json.NewEncoder(w).Encode(stored)
+	}))
+
+	_, err := client.FilterChains.ReorderFilters(context.Background(), "chain", []string{"b", "a", "a"})
+	assert.EqualError(t, err, "filter chain chain runs filter a more than once, "+
+		"set its filters in the new order instead")
+}
@@ -0,0 +1,54 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// GraphqlCostDecorationService handles the cost decorations of the
+// GraphQL Rate Limiting Advanced plugin, which set the cost of the
+// types and fields of GraphQL queries (Kong Enterprise only).
+type GraphqlCostDecorationService service
+
+func (s *GraphqlCostDecorationService) entities() *EntityService[kong.GraphqlRateLimitingCostDecoration] {
+	return NewEntityService[kong.GraphqlRateLimitingCostDecoration](s.client,
+		"/graphql-rate-limiting-advanced/costs")
+}
+
+// Create creates a cost decoration.
+func (s *GraphqlCostDecorationService) Create(ctx context.Context,
+	decoration *kong.GraphqlRateLimitingCostDecoration,
+) (*kong.GraphqlRateLimitingCostDecoration, error) {
+	if decoration == nil {
+		return nil, fmt.Errorf("cannot create a nil cost decoration")
+	}
+	return s.entities().Create(ctx, decoration)
+}
+
+// Get fetches a cost decoration by its ID.
+func (s *GraphqlCostDecorationService) Get(ctx context.Context,
+	id string,
+) (*kong.GraphqlRateLimitingCostDecoration, error) {
+	return s.entities().Get(ctx, id)
+}
+
+// Delete deletes a cost decoration by its ID.
+func (s *GraphqlCostDecorationService) Delete(ctx context.Context, id string) error {
+	return s.entities().Delete(ctx, id)
+}
+
+// List fetches a page of cost decorations.
+// opt can be used to control pagination.
+func (s *GraphqlCostDecorationService) List(ctx context.Context,
+	opt *kong.ListOpt,
+) ([]*kong.GraphqlRateLimitingCostDecoration, *kong.ListOpt, error) {
+	return s.entities().List(ctx, opt)
+}
+
+// ListAll fetches all cost decorations.
+func (s *GraphqlCostDecorationService) ListAll(ctx context.Context,
+) ([]*kong.GraphqlRateLimitingCostDecoration, error) {
+	return s.entities().ListAll(ctx)
+}
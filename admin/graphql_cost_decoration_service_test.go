@@ -0,0 +1,63 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphqlCostDecorationService(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/graphql-rate-limiting-advanced/costs" && r.Method == http.MethodPost:
+			var decoration kong.GraphqlRateLimitingCostDecoration
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&decoration))
+			decoration.ID = kong.String("c1")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(decoration)
+		case r.URL.Path == "/graphql-rate-limiting-advanced/costs":
+			_, _ = w.Write([]byte(`{"data":[{"id":"c1","type_path":"Query.users","mul_constant":2}]}`))
+		case r.URL.Path == "/graphql-rate-limiting-advanced/costs/c1" && r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":"c1","type_path":"Query.users","mul_constant":2}`))
+		case r.URL.Path == "/graphql-rate-limiting-advanced/costs/c1" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	created, err := client.GraphqlCostDecorations.Create(ctx, &kong.GraphqlRateLimitingCostDecoration{
+		TypePath:    kong.String("Query.users"),
+		MulConstant: kong.Float64(2),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "c1", *created.ID)
+
+	decorations, err := client.GraphqlCostDecorations.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, decorations, 1)
+	assert.Equal(t, "Query.users", *decorations[0].TypePath)
+
+	decoration, err := client.GraphqlCostDecorations.Get(ctx, "c1")
+	require.NoError(t, err)
+	assert.Equal(t, 2.0, *decoration.MulConstant)
+
+	require.NoError(t, client.GraphqlCostDecorations.Delete(ctx, "c1"))
+	_, err = client.GraphqlCostDecorations.Create(ctx, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"POST /graphql-rate-limiting-advanced/costs",
+		"GET /graphql-rate-limiting-advanced/costs",
+		"GET /graphql-rate-limiting-advanced/costs/c1",
+		"DELETE /graphql-rate-limiting-advanced/costs/c1",
+	}, requests)
+}
@@ -0,0 +1,121 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// Page is a page of entities sent by Stream.
+// If Err is set, it is the last page and holds no entities.
+type Page[T any] struct {
+	Items []T
+	Err   error
+}
+
+// ListFunc fetches a single page of entities.
+// It matches the signature of the List method of go-kong services.
+type ListFunc[T any] func(ctx context.Context, opt *kong.ListOpt) ([]T, *kong.ListOpt, error)
+
+// Stream fetches all entities with list, page by page, and sends each
+// page on the returned channel. The next page is only fetched once the
+// previous one has been received, so at most one page is held in memory.
+// The channel is closed when all pages have been sent, when list fails,
+// in which case the error is sent as the last page, or when ctx is done.
+// Callers that stop reading early must cancel ctx.
+func Stream[T any](ctx context.Context, list ListFunc[T], opt *kong.ListOpt) <-chan Page[T] {
+	pages := make(chan Page[T])
+	if opt == nil {
+		opt = &kong.ListOpt{Size: pageSize}
+	}
+	go func() {
+		defer close(pages)
+		for opt != nil {
+			items, next, err := list(ctx, opt)
+			page := Page[T]{Items: items, Err: err}
+			if err != nil {
+				page.Items = nil
+			}
+			select {
+			case pages <- page:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+			opt = next
+		}
+	}()
+	return pages
+}
+
+// StreamServices streams all services page by page.
+func (c *Client) StreamServices(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.Service] {
+	return Stream(ctx, NewEntityService[kong.Service](c, "/services").List, opt)
+}
+
+// StreamRoutes streams all routes page by page.
+func (c *Client) StreamRoutes(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.Route] {
+	return Stream(ctx, NewEntityService[kong.Route](c, "/routes").List, opt)
+}
+
+// StreamPlugins streams all plugins page by page.
+func (c *Client) StreamPlugins(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.Plugin] {
+	return Stream(ctx, NewEntityService[kong.Plugin](c, "/plugins").List, opt)
+}
+
+// StreamConsumers streams all consumers page by page.
+func (c *Client) StreamConsumers(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.Consumer] {
+	return Stream(ctx, NewEntityService[kong.Consumer](c, "/consumers").List, opt)
+}
+
+// StreamConsumerGroups streams all consumer groups page by page.
+func (c *Client) StreamConsumerGroups(ctx context.Context,
+	opt *kong.ListOpt,
+) <-chan Page[*kong.ConsumerGroup] {
+	return Stream(ctx, NewEntityService[kong.ConsumerGroup](c, "/consumer_groups").List, opt)
+}
+
+// StreamUpstreams streams all upstreams page by page.
+func (c *Client) StreamUpstreams(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.Upstream] {
+	return Stream(ctx, NewEntityService[kong.Upstream](c, "/upstreams").List, opt)
+}
+
+// StreamTargets streams all targets of upstreamNameOrID page by page.
+func (c *Client) StreamTargets(ctx context.Context, upstreamNameOrID string,
+	opt *kong.ListOpt,
+) <-chan Page[*kong.Target] {
+	return Stream(ctx, NewEntityService[kong.Target](c, "/upstreams/{upstream}/targets").
+		With(upstreamNameOrID).List, opt)
+}
+
+// StreamCertificates streams all certificates page by page.
+func (c *Client) StreamCertificates(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.Certificate] {
+	return Stream(ctx, NewEntityService[kong.Certificate](c, "/certificates").List, opt)
+}
+
+// StreamSNIs streams all SNIs page by page.
+func (c *Client) StreamSNIs(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.SNI] {
+	return Stream(ctx, NewEntityService[kong.SNI](c, "/snis").List, opt)
+}
+
+// StreamKeyAuths streams all key-auth credentials page by page.
+func (c *Client) StreamKeyAuths(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.KeyAuth] {
+	return Stream(ctx, NewEntityService[kong.KeyAuth](c, "/key-auths").List, opt)
+}
+
+// StreamBasicAuths streams all basic-auth credentials page by page.
+func (c *Client) StreamBasicAuths(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.BasicAuth] {
+	return Stream(ctx, NewEntityService[kong.BasicAuth](c, "/basic-auths").List, opt)
+}
+
+// StreamACLs streams all ACL groups page by page.
+func (c *Client) StreamACLs(ctx context.Context, opt *kong.ListOpt) <-chan Page[*kong.ACLGroup] {
+	return Stream(ctx, NewEntityService[kong.ACLGroup](c, "/acls").List, opt)
+}
+
+// StreamDataPlanes streams all data planes page by page.
+func (c *Client) StreamDataPlanes(ctx context.Context, opt *kong.ListOpt) <-chan Page[*DataPlane] {
+	return Stream(ctx, c.Clustering.ListDataPlanes, opt)
+}
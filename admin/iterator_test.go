@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamConsumers(t *testing.T) {
+	var requests int32
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, "/consumers", r.URL.Path)
+		switch r.URL.Query().Get("offset") {
+		case "":
+			_, _ = w.Write([]byte(`{"data":[{"username":"c1"},{"username":"c2"}],"offset":"p2"}`))
+		case "p2":
+			_, _ = w.Write([]byte(`{"data":[{"username":"c3"}],"offset":"p3"}`))
+		default:
+			_, _ = w.Write([]byte(`{"data":[{"username":"c4"}]}`))
+		}
+	}))
+
+	var usernames []string
+	var pages int
+	for page := range client.StreamConsumers(context.Background(), &kong.ListOpt{Size: 2}) {
+		require.NoError(t, page.Err)
+		pages++
+		for _, c := range page.Items {
+			usernames = append(usernames, *c.Username)
+		}
+	}
+	assert.Equal(t, 3, pages)
+	assert.Equal(t, []string{"c1", "c2", "c3", "c4"}, usernames)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestStreamCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	list := func(_ context.Context, opt *kong.ListOpt) ([]int, *kong.ListOpt, error) {
+		calls++
+		return []int{calls}, &kong.ListOpt{Offset: fmt.Sprint(calls)}, nil
+	}
+	pages := Stream(ctx, list, nil)
+	page := <-pages
+	assert.Equal(t, []int{1}, page.Items)
+	cancel()
+	for range pages { //nolint:revive
+	}
+	assert.LessOrEqual(t, calls, 2)
+}
+
+func TestStreamError(t *testing.T) {
+	list := func(_ context.Context, opt *kong.ListOpt) ([]int, *kong.ListOpt, error) {
+		if opt.Offset == "" {
+			return []int{1}, &kong.ListOpt{Offset: "next"}, nil
+		}
+		return []int{2}, nil, errors.New("boom")
+	}
+	var got []Page[int]
+	for page := range Stream(context.Background(), list, nil) {
+		got = append(got, page)
+	}
+	require.Len(t, got, 2)
+	assert.Equal(t, []int{1}, got[0].Items)
+	assert.EqualError(t, got[1].Err, "boom")
+	assert.Nil(t, got[1].Items)
+}
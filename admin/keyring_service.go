@@ -0,0 +1,132 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Keyring describes the keys of the keyring of a cluster.
+type Keyring struct {
+	// Active is the ID of the key used to encrypt new data.
+	Active *string `json:"active,omitempty"`
+	// IDs of all keys, which can decrypt existing data.
+	IDs []string `json:"ids,omitempty"`
+}
+
+// KeyringKey is a key of the keyring.
+type KeyringKey struct {
+	ID  *string `json:"id,omitempty"`
+	Key *string `json:"key,omitempty"`
+}
+
+// KeyringService handles the keyring used to encrypt sensitive fields
+// at rest (Kong Enterprise only).
+// The keyring must be enabled in cluster mode to be managed through
+// the Admin API.
+type KeyringService service
+
+func (s *KeyringService) send(ctx context.Context, method, endpoint string,
+	body interface{}, v interface{},
+) error {
+	req, err := s.client.NewRootRequest(method, endpoint, nil, body)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Do(ctx, req, v)
+	return err
+}
+
+// Get fetches the IDs of the keys of the keyring and the active one.
+func (s *KeyringService) Get(ctx context.Context) (*Keyring, error) {
+	var keyring Keyring
+	if err := s.send(ctx, http.MethodGet, "/keyring", nil, &keyring); err != nil {
+		return nil, err
+	}
+	return &keyring, nil
+}
+
+// Active fetches the ID of the active key.
+func (s *KeyringService) Active(ctx context.Context) (string, error) {
+	var key KeyringKey
+	if err := s.send(ctx, http.MethodGet, "/keyring/active", nil, &key); err != nil {
+		return "", err
+	}
+	if key.ID == nil {
+		return "", nil
+	}
+	return *key.ID, nil
+}
+
+// Export exports the keyring, encrypted with the public key configured
+// with keyring_public_key.
+// The returned envelope can be given to Import on another node.
+func (s *KeyringService) Export(ctx context.Context) (string, error) {
+	var export struct {
+		Data string `json:"data"`
+	}
+	if err := s.send(ctx, http.MethodPost, "/keyring/export", nil, &export); err != nil {
+		return "", err
+	}
+	return export.Data, nil
+}
+
+// Import imports a keyring envelope created by Export.
+func (s *KeyringService) Import(ctx context.Context, envelope string) error {
+	if envelope == "" {
+		return fmt.Errorf("envelope cannot be empty")
+	}
+	body := struct {
+		Data string `json:"data"`
+	}{Data: envelope}
+	return s.send(ctx, http.MethodPost, "/keyring/import", body, nil)
+}
+
+// Generate generates a new key and adds it to the keyring.
+// The key is not activated.
+func (s *KeyringService) Generate(ctx context.Context) (*KeyringKey, error) {
+	var key KeyringKey
+	if err := s.send(ctx, http.MethodPost, "/keyring/generate", nil, &key); err != nil {
+		return nil, err
+	}
+	return &key, nil
+}
+
+// Activate makes the key identified by id the active key.
+func (s *KeyringService) Activate(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+	body := struct {
+		Key string `json:"key"`
+	}{Key: id}
+	return s.send(ctx, http.MethodPost, "/keyring/activate", body, nil)
+}
+
+// Remove removes the key identified by id from the keyring.
+// Data encrypted with this key can no longer be decrypted.
+func (s *KeyringService) Remove(ctx context.Context, id string) error {
+	if id == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+	body := struct {
+		Key string `json:"key"`
+	}{Key: id}
+	return s.send(ctx, http.MethodPost, "/keyring/remove", body, nil)
+}
+
+// Rotate generates a new key and activates it.
+// Previous keys are kept, so that existing data can still be decrypted.
+func (s *KeyringService) Rotate(ctx context.Context) (*KeyringKey, error) {
+	key, err := s.Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("generating key: %w", err)
+	}
+	if key.ID == nil {
+		return nil, fmt.Errorf("generating key: no ID returned")
+	}
+	if err := s.Activate(ctx, *key.ID); err != nil {
+		return nil, fmt.Errorf("activating key %s: %w", *key.ID, err)
+	}
+	return key, nil
+}
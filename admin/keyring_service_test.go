@@ -0,0 +1,72 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyringService(t *testing.T) {
+	var requests []string
+	keyring := Keyring{Active: kong.String("k1"), IDs: []string{"k1"}}
+	server := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+body["key"]+body["data"])
+		switch r.URL.Path {
+		case "/keyring":
+			_ = json.NewEncoder(w).Encode(keyring)
+		case "/keyring/active":
+			_ = json.NewEncoder(w).Encode(KeyringKey{ID: keyring.Active})
+		case "/keyring/export":
+			_, _ = w.Write([]byte(`{"data":"envelope"}`))
+		case "/keyring/import":
+			w.WriteHeader(http.StatusCreated)
+		case "/keyring/generate":
+			keyring.IDs = append(keyring.IDs, "k2")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"k2","key":"secret"}`))
+		case "/keyring/activate":
+			keyring.Active = kong.String(body["key"])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"not found"}`))
+		}
+	})
+	client := newTestClient(t, server)
+	ctx := context.Background()
+
+	envelope, err := client.Keyring.Export(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "envelope", envelope)
+	require.NoError(t, client.Keyring.Import(ctx, envelope))
+
+	key, err := client.Keyring.Rotate(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", *key.ID)
+
+	active, err := client.Keyring.Active(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "k2", active)
+	k, err := client.Keyring.Get(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"k1", "k2"}, k.IDs)
+
+	assert.Error(t, client.Keyring.Remove(ctx, "k1"))
+	assert.Error(t, client.Keyring.Activate(ctx, ""))
+
+	assert.Equal(t, []string{
+		"POST /keyring/export ",
+		"POST /keyring/import envelope",
+		"POST /keyring/generate ",
+		"POST /keyring/activate k2",
+		"GET /keyring/active ",
+		"GET /keyring ",
+		"POST /keyring/remove k1",
+	}, requests)
+}
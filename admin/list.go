@@ -0,0 +1,69 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+const pageSize = 1000
+
+// listQS is used to construct the query string for list endpoints.
+type listQS struct {
+	Size   int    `url:"size,omitempty"`
+	Offset string `url:"offset,omitempty"`
+	Tags   string `url:"tags,omitempty"`
+}
+
+func newListQS(opt *kong.ListOpt) listQS {
+	var q listQS
+	if opt == nil {
+		return q
+	}
+	q.Size = opt.Size
+	q.Offset = opt.Offset
+	sep := "/"
+	if opt.MatchAllTags {
+		sep = ","
+	}
+	for i, tag := range opt.Tags {
+		if i > 0 {
+			q.Tags += sep
+		}
+		q.Tags += *tag
+	}
+	return q
+}
+
+// list fetches a page of entities from endpoint.
+// opt can be used to control pagination.
+func (c *Client) list(ctx context.Context,
+	endpoint string, opt *kong.ListOpt,
+) ([]json.RawMessage, *kong.ListOpt, error) {
+	req, err := c.NewRequest(http.MethodGet, endpoint, newListQS(opt), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	var list struct {
+		Data []json.RawMessage `json:"data"`
+		Next *string           `json:"offset"`
+	}
+	_, err = c.Do(ctx, req, &list)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// convenient for end user to use this opt till it's nil
+	var next *kong.ListOpt
+	if list.Next != nil && *list.Next != "" {
+		next = &kong.ListOpt{Offset: *list.Next}
+		if opt != nil {
+			next.Size = opt.Size
+			next.Tags = opt.Tags
+			next.MatchAllTags = opt.MatchAllTags
+		}
+	}
+	return list.Data, next, nil
+}
@@ -0,0 +1,304 @@
+package admin
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Sample is a single sample of a Prometheus metric.
+type Sample struct {
+	Name   string
+	Labels map[string]string
+	Value  float64
+}
+
+// Metrics holds the samples exposed by a Kong node
+// through the Prometheus plugin.
+type Metrics struct {
+	Samples []Sample
+}
+
+// LatencyHistogram is the latency distribution of requests
+// proxied by Kong for a Service and Route.
+type LatencyHistogram struct {
+	Service string
+	Route   string
+	// Count is the number of observed requests.
+	Count float64
+	// Sum is the total latency of observed requests, in milliseconds.
+	Sum float64
+	// Buckets maps the upper bound of each bucket, in milliseconds,
+	// to the cumulative number of requests in it.
+	Buckets map[float64]float64
+}
+
+// Mean returns the average latency in milliseconds.
+func (h *LatencyHistogram) Mean() float64 {
+	if h.Count == 0 {
+		return 0
+	}
+	return h.Sum / h.Count
+}
+
+// Bandwidth is the number of bytes transferred for a Service and Route.
+type Bandwidth struct {
+	Service string
+	Route   string
+	// Direction is either "ingress" or "egress".
+	Direction string
+	Bytes     float64
+}
+
+// TargetHealth is the health state of an address of an Upstream's Target.
+type TargetHealth struct {
+	Upstream string
+	Target   string
+	Address  string
+	// State is one of "healthy", "unhealthy", "healthchecks_off"
+	// or "dns_error".
+	State string
+}
+
+// Latency kinds exposed by the Prometheus plugin.
+const (
+	// RequestLatency is the total latency of requests.
+	RequestLatency = "request"
+	// UpstreamLatency is the time spent waiting for upstream services.
+	UpstreamLatency = "upstream"
+	// KongLatency is the time spent running Kong plugins.
+	KongLatency = "kong"
+)
+
+// Metrics fetches and parses the Prometheus metrics of the Kong node.
+// The client may point at the Admin API or the Status API of the node;
+// the Prometheus plugin must be enabled.
+func (c *Client) Metrics(ctx context.Context) (*Metrics, error) {
+	req, err := c.NewRootRequest(http.MethodGet, "/metrics", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if _, err := c.Do(ctx, req, &buf); err != nil {
+		return nil, err
+	}
+	return ParseMetrics(&buf)
+}
+
+// ParseMetrics parses metrics in the Prometheus text exposition format.
+func ParseMetrics(r io.Reader) (*Metrics, error) {
+	var metrics Metrics
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		sample, err := parseSample(line)
+		if err != nil {
+			return nil, fmt.Errorf("parsing metrics: line %d: %w", lineNumber, err)
+		}
+		metrics.Samples = append(metrics.Samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading metrics: %w", err)
+	}
+	return &metrics, nil
+}
+
+func parseSample(line string) (Sample, error) {
+	sample := Sample{Labels: map[string]string{}}
+	nameEnd := strings.IndexAny(line, "{ \t")
+	if nameEnd <= 0 {
+		return sample, fmt.Errorf("invalid sample %q", line)
+	}
+	sample.Name = line[:nameEnd]
+	rest := line[nameEnd:]
+	if strings.HasPrefix(rest, "{") {
+		var err error
+		rest, err = parseLabels(rest[1:], sample.Labels)
+		if err != nil {
+			return sample, err
+		}
+	}
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return sample, fmt.Errorf("missing value for %s", sample.Name)
+	}
+	value, err := parseValue(fields[0])
+	if err != nil {
+		return sample, fmt.Errorf("invalid value for %s: %w", sample.Name, err)
+	}
+	sample.Value = value
+	return sample, nil
+}
+
+// parseLabels parses labels up to the closing brace into labels,
+// and returns the remainder of s.
+func parseLabels(s string, labels map[string]string) (string, error) {
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if strings.HasPrefix(s, "}") {
+			return s[1:], nil
+		}
+		eq := strings.Index(s, "=")
+		if eq <= 0 || len(s) < eq+2 || s[eq+1] != '"' {
+			return "", fmt.Errorf("invalid labels")
+		}
+		name := strings.TrimSpace(s[:eq])
+		var value strings.Builder
+		i := eq + 2
+		for ; i < len(s) && s[i] != '"'; i++ {
+			if s[i] == '\\' && i+1 < len(s) {
+				i++
+				switch s[i] {
+				case 'n':
+					value.WriteByte('\n')
+				default:
+					value.WriteByte(s[i])
+				}
+				continue
+			}
+			value.WriteByte(s[i])
+		}
+		if i >= len(s) {
+			return "", fmt.Errorf("unterminated value for label %s", name)
+		}
+		labels[name] = value.String()
+		s = s[i+1:]
+	}
+}
+
+func parseValue(s string) (float64, error) {
+	switch s {
+	case "+Inf":
+		return math.Inf(1), nil
+	case "-Inf":
+		return math.Inf(-1), nil
+	case "NaN":
+		return math.NaN(), nil
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func hasLabels(sample Sample, labels map[string]string) bool {
+	for k, v := range labels {
+		if sample.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// Find returns the samples of the metric name which have all labels.
+func (m *Metrics) Find(name string, labels map[string]string) []Sample {
+	var res []Sample
+	for _, sample := range m.Samples {
+		if sample.Name == name && hasLabels(sample, labels) {
+			res = append(res, sample)
+		}
+	}
+	return res
+}
+
+// Value returns the value of the first sample of the metric name
+// which has all labels.
+func (m *Metrics) Value(name string, labels map[string]string) (float64, bool) {
+	samples := m.Find(name, labels)
+	if len(samples) == 0 {
+		return 0, false
+	}
+	return samples[0].Value, true
+}
+
+// Latencies returns the latency histograms of kind, one of
+// RequestLatency, UpstreamLatency or KongLatency, per Service and Route.
+// Per-service metrics must be enabled in the Prometheus plugin.
+func (m *Metrics) Latencies(kind string) []*LatencyHistogram {
+	prefix := fmt.Sprintf("kong_%s_latency_ms", kind)
+	type key struct{ service, route string }
+	histograms := map[key]*LatencyHistogram{}
+	get := func(sample Sample) *LatencyHistogram {
+		k := key{sample.Labels["service"], sample.Labels["route"]}
+		h, ok := histograms[k]
+		if !ok {
+			h = &LatencyHistogram{Service: k.service, Route: k.route, Buckets: map[float64]float64{}}
+			histograms[k] = h
+		}
+		return h
+	}
+	for _, sample := range m.Samples {
+		switch sample.Name {
+		case prefix + "_bucket":
+			le, err := parseValue(sample.Labels["le"])
+			if err != nil {
+				continue
+			}
+			get(sample).Buckets[le] = sample.Value
+		case prefix + "_count":
+			get(sample).Count = sample.Value
+		case prefix + "_sum":
+			get(sample).Sum = sample.Value
+		}
+	}
+	res := make([]*LatencyHistogram, 0, len(histograms))
+	for _, h := range histograms {
+		res = append(res, h)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Service != res[j].Service {
+			return res[i].Service < res[j].Service
+		}
+		return res[i].Route < res[j].Route
+	})
+	return res
+}
+
+// Bandwidth returns the bandwidth consumed per Service,
+// Route and direction.
+func (m *Metrics) Bandwidth() []Bandwidth {
+	var res []Bandwidth
+	for _, sample := range m.Samples {
+		// kong_bandwidth is the name used by Kong 2.x
+		if sample.Name != "kong_bandwidth_bytes" && sample.Name != "kong_bandwidth" {
+			continue
+		}
+		res = append(res, Bandwidth{
+			Service:   sample.Labels["service"],
+			Route:     sample.Labels["route"],
+			Direction: sample.Labels["direction"],
+			Bytes:     sample.Value,
+		})
+	}
+	return res
+}
+
+// TargetHealth returns the current health state of every address
+// of the Targets of all Upstreams.
+// Upstream health metrics must be enabled in the Prometheus plugin.
+func (m *Metrics) TargetHealth() []TargetHealth {
+	var res []TargetHealth
+	for _, sample := range m.Find("kong_upstream_target_health", nil) {
+		// every state is exposed, the current one has the value 1
+		if sample.Value != 1 {
+			continue
+		}
+		res = append(res, TargetHealth{
+			Upstream: sample.Labels["upstream"],
+			Target:   sample.Labels["target"],
+			Address:  sample.Labels["address"],
+			State:    sample.Labels["state"],
+		})
+	}
+	return res
+}
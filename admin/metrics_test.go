@@ -0,0 +1,89 @@
+package admin
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testMetrics = `# HELP kong_bandwidth_bytes Total bandwidth (ingress/egress) throughput in bytes
+# TYPE kong_bandwidth_bytes counter
+kong_bandwidth_bytes{service="svc1",route="r1",direction="egress",consumer=""} 1024
+kong_bandwidth_bytes{service="svc1",route="r1",direction="ingress",consumer=""} 256
+# TYPE kong_request_latency_ms histogram
+kong_request_latency_ms_bucket{service="svc1",route="r1",workspace="default",le="25"} 2
+kong_request_latency_ms_bucket{service="svc1",route="r1",workspace="default",le="+Inf"} 3
+kong_request_latency_ms_count{service="svc1",route="r1",workspace="default"} 3
+kong_request_latency_ms_sum{service="svc1",route="r1",workspace="default"} 90
+# TYPE kong_upstream_target_health gauge
+kong_upstream_target_health{upstream="up1",target="a:80",address="10.0.0.1:80",state="healthchecks_off",subsystem="http"} 0
+kong_upstream_target_health{upstream="up1",target="a:80",address="10.0.0.1:80",state="healthy",subsystem="http"} 1
+kong_upstream_target_health{upstream="up1",target="a:80",address="10.0.0.1:80",state="unhealthy",subsystem="http"} 0
+kong_nginx_connections_total{node_id="n1",subsystem="http",state="active"} 5
+kong_memory_lua_shared_dict_bytes{node_id="n1",shared_dict="kong",kong_subsystem="http"} 4.096e+04
+kong_node_info{node_id="n1",version="3.4.0",label="with \"quotes\", commas\\"} 1
+`
+
+func TestParseMetrics(t *testing.T) {
+	metrics, err := ParseMetrics(strings.NewReader(testMetrics))
+	require.NoError(t, err)
+	require.Len(t, metrics.Samples, 12)
+
+	value, ok := metrics.Value("kong_nginx_connections_total", map[string]string{"state": "active"})
+	assert.True(t, ok)
+	assert.Equal(t, float64(5), value)
+
+	value, ok = metrics.Value("kong_memory_lua_shared_dict_bytes", nil)
+	assert.True(t, ok)
+	assert.Equal(t, float64(40960), value)
+
+	info := metrics.Find("kong_node_info", nil)
+	require.Len(t, info, 1)
+	assert.Equal(t, `with "quotes", commas\`, info[0].Labels["label"])
+
+	_, ok = metrics.Value("kong_missing", nil)
+	assert.False(t, ok)
+
+	_, err = ParseMetrics(strings.NewReader(`kong_broken{label="unterminated} 1`))
+	assert.Error(t, err)
+	_, err = ParseMetrics(strings.NewReader(`kong_broken abc`))
+	assert.Error(t, err)
+}
+
+func TestMetricsTypedHelpers(t *testing.T) {
+	metrics, err := ParseMetrics(strings.NewReader(testMetrics))
+	require.NoError(t, err)
+
+	latencies := metrics.Latencies(RequestLatency)
+	require.Len(t, latencies, 1)
+	assert.Equal(t, "svc1", latencies[0].Service)
+	assert.Equal(t, float64(3), latencies[0].Count)
+	assert.Equal(t, float64(30), latencies[0].Mean())
+	assert.Equal(t, float64(2), latencies[0].Buckets[25])
+	assert.Equal(t, float64(3), latencies[0].Buckets[math.Inf(1)])
+	assert.Empty(t, metrics.Latencies(UpstreamLatency))
+
+	assert.Equal(t, []Bandwidth{
+		{Service: "svc1", Route: "r1", Direction: "egress", Bytes: 1024},
+		{Service: "svc1", Route: "r1", Direction: "ingress", Bytes: 256},
+	}, metrics.Bandwidth())
+
+	assert.Equal(t, []TargetHealth{
+		{Upstream: "up1", Target: "a:80", Address: "10.0.0.1:80", State: "healthy"},
+	}, metrics.TargetHealth())
+}
+
+func TestClientMetrics(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/metrics", r.URL.Path)
+		_, _ = w.Write([]byte(testMetrics))
+	}))
+	metrics, err := client.Metrics(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, metrics.Samples, 12)
+}
@@ -0,0 +1,156 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// Partial represents a reusable configuration block, such as the
+// Redis settings shared by several plugins, available in Kong 3.10
+// and above.
+type Partial struct {
+	ID        *string                `json:"id,omitempty" yaml:"id,omitempty"`
+	Name      *string                `json:"name,omitempty" yaml:"name,omitempty"`
+	Type      *string                `json:"type,omitempty" yaml:"type,omitempty"`
+	Config    map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+	CreatedAt *int                   `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+	UpdatedAt *int                   `json:"updated_at,omitempty" yaml:"updated_at,omitempty"`
+	Tags      []*string              `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// FriendlyName returns the endpoint key name or ID.
+func (p *Partial) FriendlyName() string {
+	if p.Name != nil {
+		return *p.Name
+	}
+	if p.ID != nil {
+		return *p.ID
+	}
+	return ""
+}
+
+// PartialLink links a Partial to a Plugin.
+// Path is the field of the plugin configuration filled by the
+// Partial; it defaults to the one expected for the Partial's type.
+type PartialLink struct {
+	ID   *string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name *string `json:"name,omitempty" yaml:"name,omitempty"`
+	Path *string `json:"path,omitempty" yaml:"path,omitempty"`
+}
+
+// PartialService handles Partials in Kong.
+type PartialService service
+
+func (s *PartialService) entities() *EntityService[Partial] {
+	return NewEntityService[Partial](s.client, "/partials")
+}
+
+// Create creates a Partial in Kong.
+// If an ID is specified, it is used to create the Partial,
+// otherwise an ID is auto-generated.
+func (s *PartialService) Create(ctx context.Context, partial *Partial) (*Partial, error) {
+	if partial == nil {
+		return nil, fmt.Errorf("cannot create a nil partial")
+	}
+	if partial.ID != nil {
+		return s.entities().Upsert(ctx, *partial.ID, partial)
+	}
+	return s.entities().Create(ctx, partial)
+}
+
+// CreateOrUpdate creates or replaces a Partial identified by its ID
+// or, if not set, its name.
+func (s *PartialService) CreateOrUpdate(ctx context.Context,
+	partial *Partial,
+) (*Partial, error) {
+	if partial == nil {
+		return nil, fmt.Errorf("cannot create or update a nil partial")
+	}
+	return createOrUpdate(ctx, s.client, "/partials",
+		upsertKey(partial.ID, partial.Name), partial)
+}
+
+// Get fetches a Partial by its name or ID.
+func (s *PartialService) Get(ctx context.Context, nameOrID string) (*Partial, error) {
+	return s.entities().Get(ctx, nameOrID)
+}
+
+// Update updates the fields of a Partial which are set in partial.
+// partial.ID or partial.Name identifies the Partial.
+func (s *PartialService) Update(ctx context.Context, partial *Partial) (*Partial, error) {
+	if partial == nil {
+		return nil, fmt.Errorf("cannot update a nil partial")
+	}
+	key := upsertKey(partial.ID, partial.Name)
+	if key == "" {
+		return nil, fmt.Errorf("ID or name cannot be empty for Update operation")
+	}
+	return s.entities().Update(ctx, key, partial)
+}
+
+// Delete deletes a Partial by its name or ID.
+// Kong refuses to delete a Partial which is still linked to plugins.
+func (s *PartialService) Delete(ctx context.Context, nameOrID string) error {
+	return s.entities().Delete(ctx, nameOrID)
+}
+
+// List fetches a page of Partials.
+// opt can be used to control pagination.
+func (s *PartialService) List(ctx context.Context,
+	opt *kong.ListOpt,
+) ([]*Partial, *kong.ListOpt, error) {
+	return s.entities().List(ctx, opt)
+}
+
+// ListAll fetches all Partials.
+func (s *PartialService) ListAll(ctx context.Context) ([]*Partial, error) {
+	return s.entities().ListAll(ctx)
+}
+
+// ListAllLinkedPlugins fetches all plugins linked to a Partial.
+func (s *PartialService) ListAllLinkedPlugins(ctx context.Context,
+	nameOrID string,
+) ([]*kong.Plugin, error) {
+	if nameOrID == "" {
+		return nil, fmt.Errorf("nameOrID cannot be empty")
+	}
+	return NewEntityService[kong.Plugin](s.client, "/partials/{partial}/links").
+		With(nameOrID).ListAll(ctx)
+}
+
+// LinkToPlugin sets the Partials linked to a plugin, replacing
+// any previous links. An empty links unlinks all Partials.
+func (s *PartialService) LinkToPlugin(ctx context.Context,
+	pluginID string, links []*PartialLink,
+) error {
+	if pluginID == "" {
+		return fmt.Errorf("pluginID cannot be empty")
+	}
+	if links == nil {
+		links = []*PartialLink{}
+	}
+	body := struct {
+		Partials []*PartialLink `json:"partials"`
+	}{Partials: links}
+	return s.client.Send(ctx, http.MethodPatch, "/plugins/"+url.PathEscape(pluginID), nil, body, nil)
+}
+
+// GetPluginLinks fetches the Partials linked to a plugin.
+func (s *PartialService) GetPluginLinks(ctx context.Context,
+	pluginID string,
+) ([]*PartialLink, error) {
+	if pluginID == "" {
+		return nil, fmt.Errorf("pluginID cannot be empty")
+	}
+	var plugin struct {
+		Partials []*PartialLink `json:"partials"`
+	}
+	if err := s.client.Send(ctx, http.MethodGet, "/plugins/"+url.PathEscape(pluginID), nil, nil, &plugin); err != nil {
+		return nil, err
+	}
+	return plugin.Partials, nil
+}
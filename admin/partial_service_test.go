@@ -0,0 +1,71 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialService(t *testing.T) {
+	var (
+		requests    []string
+		pluginPatch string
+	)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch {
+		case r.URL.Path == "/partials" && r.Method == http.MethodPost:
+			var p Partial
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+			p.ID = kong.String("p1")
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(p)
+		case r.URL.Path == "/partials/redis/links":
+			_, _ = w.Write([]byte(`{"data":[{"id":"plugin1","name":"rate-limiting-advanced"}]}`))
+		case r.URL.Path == "/plugins/plugin1" && r.Method == http.MethodPatch:
+			var body json.RawMessage
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			pluginPatch = string(body)
+			_, _ = w.Write([]byte(`{}`))
+		case r.URL.Path == "/plugins/plugin1":
+			_, _ = w.Write([]byte(`{"id":"plugin1","partials":[{"id":"p1","name":"redis","path":"config.redis"}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	partial, err := client.Partials.Create(ctx, &Partial{
+		Name:   kong.String("redis"),
+		Type:   kong.String("redis-ee"),
+		Config: map[string]interface{}{"host": "redis.example.com"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "p1", *partial.ID)
+
+	plugins, err := client.Partials.ListAllLinkedPlugins(ctx, "redis")
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "rate-limiting-advanced", *plugins[0].Name)
+
+	require.NoError(t, client.Partials.LinkToPlugin(ctx, "plugin1", []*PartialLink{
+		{ID: kong.String("p1")},
+	}))
+	assert.JSONEq(t, `{"partials":[{"id":"p1"}]}`, pluginPatch)
+
+	require.NoError(t, client.Partials.LinkToPlugin(ctx, "plugin1", nil))
+	assert.JSONEq(t, `{"partials":[]}`, pluginPatch)
+
+	links, err := client.Partials.GetPluginLinks(ctx, "plugin1")
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	assert.Equal(t, "config.redis", *links[0].Path)
+
+	_, err = client.Partials.Get(ctx, "missing")
+	assert.True(t, kong.IsNotFoundErr(err))
+}
@@ -0,0 +1,103 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// Paths of the Dev Portal files holding API specs and documents.
+const (
+	PortalSpecsPath   = "specs"
+	PortalContentPath = "content"
+)
+
+// PortalFile is a file of the Dev Portal of a workspace, such as an API
+// spec or a markdown page (Kong Enterprise only).
+type PortalFile struct {
+	ID        *string `json:"id,omitempty" yaml:"id,omitempty"`
+	Path      *string `json:"path,omitempty" yaml:"path,omitempty"`
+	Contents  *string `json:"contents,omitempty" yaml:"contents,omitempty"`
+	Checksum  *string `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	CreatedAt *int    `json:"created_at,omitempty" yaml:"created_at,omitempty"`
+}
+
+// PortalService handles the files of the Dev Portal of a workspace.
+// The Dev Portal must be enabled in the workspace.
+//
+// Files of the Dev Portal have no draft state: a file is published
+// as soon as it is uploaded, and unpublishing a file deletes it.
+type PortalService service
+
+func (s *PortalService) files() *EntityService[PortalFile] {
+	return NewEntityService[PortalFile](s.client, "/files")
+}
+
+// Upload creates or replaces the file at filePath, which publishes it.
+func (s *PortalService) Upload(ctx context.Context,
+	filePath string, contents string,
+) (*PortalFile, error) {
+	if filePath == "" {
+		return nil, fmt.Errorf("filePath cannot be empty")
+	}
+	return s.files().Upsert(ctx, filePath, &PortalFile{
+		Path:     kong.String(filePath),
+		Contents: kong.String(contents),
+	})
+}
+
+// UploadSpec creates or replaces the OpenAPI spec named name,
+// under the specs directory of the Dev Portal.
+func (s *PortalService) UploadSpec(ctx context.Context,
+	name string, spec string,
+) (*PortalFile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+	return s.Upload(ctx, path.Join(PortalSpecsPath, name), spec)
+}
+
+// UploadDocument creates or replaces the markdown page named name,
+// under the content directory of the Dev Portal.
+// The .md extension is added to name if it has none.
+func (s *PortalService) UploadDocument(ctx context.Context,
+	name string, markdown string,
+) (*PortalFile, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name cannot be empty")
+	}
+	if path.Ext(name) == "" {
+		name += ".md"
+	}
+	return s.Upload(ctx, path.Join(PortalContentPath, name), markdown)
+}
+
+// Get fetches a file by its path or ID.
+func (s *PortalService) Get(ctx context.Context, pathOrID string) (*PortalFile, error) {
+	return s.files().Get(ctx, pathOrID)
+}
+
+// Unpublish removes a file from the Dev Portal, by its path or ID.
+func (s *PortalService) Unpublish(ctx context.Context, pathOrID string) error {
+	return s.files().Delete(ctx, pathOrID)
+}
+
+// ListAll fetches all files of the Dev Portal.
+// If dir is not empty, only the files under dir are returned.
+func (s *PortalService) ListAll(ctx context.Context, dir string) ([]*PortalFile, error) {
+	files, err := s.files().ListAll(ctx)
+	if err != nil || dir == "" {
+		return files, err
+	}
+	prefix := strings.TrimSuffix(dir, "/") + "/"
+	var res []*PortalFile
+	for _, f := range files {
+		if f.Path != nil && strings.HasPrefix(*f.Path, prefix) {
+			res = append(res, f)
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,61 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortalService(t *testing.T) {
+	var requests []string
+	files := map[string]PortalFile{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.EscapedPath())
+		switch {
+		case r.Method == http.MethodPut:
+			var f PortalFile
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&f))
+			files[*f.Path] = f
+			_ = json.NewEncoder(w).Encode(f)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/files":
+			var data []PortalFile
+			for _, f := range files {
+				data = append(data, f)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	spec, err := client.Portal.UploadSpec(ctx, "petstore.yaml", "openapi: 3.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, "specs/petstore.yaml", *spec.Path)
+	doc, err := client.Portal.UploadDocument(ctx, "guides/getting-started", "# Hello")
+	require.NoError(t, err)
+	assert.Equal(t, "content/guides/getting-started.md", *doc.Path)
+	assert.Equal(t, "# Hello", *doc.Contents)
+
+	specs, err := client.Portal.ListAll(ctx, PortalSpecsPath)
+	require.NoError(t, err)
+	require.Len(t, specs, 1)
+	assert.Equal(t, "specs/petstore.yaml", *specs[0].Path)
+
+	require.NoError(t, client.Portal.Unpublish(ctx, "specs/petstore.yaml"))
+	_, err = client.Portal.UploadSpec(ctx, "", "")
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"PUT /files/specs%2Fpetstore.yaml",
+		"PUT /files/content%2Fguides%2Fgetting-started.md",
+		"GET /files",
+		"DELETE /files/specs%2Fpetstore.yaml",
+	}, requests)
+}
@@ -0,0 +1,246 @@
+package admin
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// AdminTokenHeader is the header carrying the RBAC token of requests.
+const AdminTokenHeader = "Kong-Admin-Token"
+
+// AdminToken holds the RBAC token sent to Kong by a client. The token can
+// be swapped while requests are in flight.
+// Its Middleware must be installed on the client, e.g. by registering it
+// in the utils.MiddlewareChain of the client's configuration.
+type AdminToken struct {
+	mu    sync.RWMutex
+	token string
+}
+
+// NewAdminToken returns an AdminToken holding token.
+func NewAdminToken(token string) *AdminToken {
+	return &AdminToken{token: token}
+}
+
+// Get returns the current token.
+func (t *AdminToken) Get() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.token
+}
+
+// Set replaces the token sent with the following requests.
+func (t *AdminToken) Set(token string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.token = token
+}
+
+type probeTokenKey struct{}
+
+// tokenProbe overrides the token of a request, and records whether the
+// Middleware handled it.
+type tokenProbe struct {
+	token string
+	seen  bool
+}
+
+// Middleware sets the RBAC token header of requests, overriding any
+// token set in the static headers of the client.
+func (t *AdminToken) Middleware() func(http.RoundTripper) http.RoundTripper {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			token := t.Get()
+			if probe, ok := req.Context().Value(probeTokenKey{}).(*tokenProbe); ok {
+				probe.seen = true
+				if probe.token != "" {
+					token = probe.token
+				}
+			}
+			if token != "" {
+				req = req.Clone(req.Context())
+				req.Header.Set(AdminTokenHeader, token)
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// TokenRotation configures the rotation of an RBAC token.
+// RBAC users hold a single token, so the new token is given to a new
+// user with the same roles, which replaces the current one.
+type TokenRotation struct {
+	// User is the name or ID of the RBAC user the client authenticates as.
+	User string
+	// NewUser is the name of the RBAC user created with the new token.
+	NewUser string
+	// Token is the new token. A random token is generated if empty.
+	Token string
+	// ProbeEndpoint is requested with the new token to verify that it
+	// works before swapping it. It defaults to `/`.
+	ProbeEndpoint string
+	// Workspaces are the other workspaces in which User holds roles.
+	// The roles of User in the workspace of the client are always given
+	// to NewUser; roles in workspaces not listed here are lost when User
+	// is deleted.
+	Workspaces []string
+}
+
+// RotateRBACToken rotates the RBAC token of the client without downtime:
+// it creates a new user with the roles of the current one, probes Kong
+// with the new token, swaps token to it, and deletes the current user,
+// which revokes the old token.
+// If any step fails, changes are undone, so that the client keeps using
+// the old token and the old user keeps existing.
+// The Middleware of token must be installed on the client: without it,
+// the new token can't be probed, and RotateRBACToken fails before making
+// any change.
+// The new user is returned, with its token.
+func (c *Client) RotateRBACToken(ctx context.Context, token *AdminToken,
+	opt TokenRotation,
+) (*kong.RBACUser, error) {
+	if token == nil {
+		return nil, fmt.Errorf("token cannot be nil")
+	}
+	if opt.User == "" || opt.NewUser == "" {
+		return nil, fmt.Errorf("user and new user cannot be empty")
+	}
+	if opt.User == opt.NewUser {
+		return nil, fmt.Errorf("new user must differ from the current one")
+	}
+	newToken := opt.Token
+	if newToken == "" {
+		b := make([]byte, 32)
+		if _, err := rand.Read(b); err != nil {
+			return nil, fmt.Errorf("generating token: %w", err)
+		}
+		newToken = base64.RawURLEncoding.EncodeToString(b)
+	}
+	probeEndpoint := opt.ProbeEndpoint
+	if probeEndpoint == "" {
+		probeEndpoint = "/"
+	}
+	users := NewEntityService[kong.RBACUser](c, "/rbac/users")
+
+	// without the Middleware, probing would succeed with the current
+	// token, and deleting the current user would lock the client out
+	if err := c.probeToken(ctx, probeEndpoint, ""); err != nil && !errors.Is(err, errProbeFailed) {
+		return nil, err
+	}
+	roles := make(map[string][]string, len(opt.Workspaces)+1)
+	workspaces := append([]string{""}, opt.Workspaces...)
+	for _, workspace := range workspaces {
+		names, err := c.workspaceClient(workspace).rbacUserRoles(ctx, opt.User)
+		if err != nil {
+			return nil, fmt.Errorf("listing roles of RBAC user %s: %w", opt.User, err)
+		}
+		roles[workspace] = names
+	}
+	user, err := users.Create(ctx, &kong.RBACUser{
+		Name:      kong.String(opt.NewUser),
+		UserToken: kong.String(newToken),
+		Enabled:   kong.Bool(true),
+		Comment:   kong.String("rotated from " + opt.User),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("creating RBAC user %s: %w", opt.NewUser, err)
+	}
+	undo := func(cause error) error {
+		if err := users.Delete(ctx, opt.NewUser); err != nil {
+			return fmt.Errorf("%w (deleting RBAC user %s: %v)", cause, opt.NewUser, err)
+		}
+		return cause
+	}
+	for _, workspace := range workspaces {
+		err := c.workspaceClient(workspace).addRBACUserRoles(ctx, opt.NewUser, roles[workspace])
+		if err != nil {
+			return nil, undo(fmt.Errorf("adding roles to RBAC user %s: %w", opt.NewUser, err))
+		}
+	}
+
+	if err := c.probeToken(ctx, probeEndpoint, newToken); err != nil {
+		return nil, undo(err)
+	}
+
+	oldToken := token.Get()
+	token.Set(newToken)
+	if err := users.Delete(ctx, opt.User); err != nil {
+		token.Set(oldToken)
+		return nil, undo(fmt.Errorf("deleting RBAC user %s: %w", opt.User, err))
+	}
+	user.UserToken = kong.String(newToken)
+	return user, nil
+}
+
+var errProbeFailed = errors.New("probing new token")
+
+// probeToken requests endpoint with token, or the current token if
+// empty. It fails if the request didn't go through the Middleware of
+// an AdminToken, or with errProbeFailed if Kong rejected it.
+func (c *Client) probeToken(ctx context.Context, endpoint, token string) error {
+	probe := &tokenProbe{token: token}
+	err := c.Send(context.WithValue(ctx, probeTokenKey{}, probe),
+		http.MethodGet, endpoint, nil, nil, nil)
+	if !probe.seen {
+		if err != nil {
+			return fmt.Errorf("checking the token middleware: %w", err)
+		}
+		return fmt.Errorf("the Middleware of the AdminToken is not installed on the client")
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errProbeFailed, err)
+	}
+	return nil
+}
+
+// workspaceClient returns c, or a clone of c targeting workspace
+// if not empty.
+func (c *Client) workspaceClient(workspace string) *Client {
+	if workspace == "" {
+		return c
+	}
+	return c.Clone(CloneOptions{Workspace: &workspace})
+}
+
+// rbacUserRoles returns the names of the roles of an RBAC user.
+func (c *Client) rbacUserRoles(ctx context.Context, user string) ([]string, error) {
+	var res struct {
+		Roles []*kong.RBACRole `json:"roles"`
+	}
+	endpoint := fmt.Sprintf("/rbac/users/%s/roles", url.PathEscape(user))
+	if err := c.Send(ctx, http.MethodGet, endpoint, nil, nil, &res); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(res.Roles))
+	for _, role := range res.Roles {
+		if role != nil && role.Name != nil {
+			names = append(names, *role.Name)
+		}
+	}
+	return names, nil
+}
+
+// addRBACUserRoles gives roles to an RBAC user.
+func (c *Client) addRBACUserRoles(ctx context.Context, user string, roles []string) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	endpoint := fmt.Sprintf("/rbac/users/%s/roles", url.PathEscape(user))
+	body := map[string]string{"roles": strings.Join(roles, ",")}
+	return c.Send(ctx, http.MethodPost, endpoint, nil, body, nil)
+}
@@ -0,0 +1,155 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeRBAC is an Admin API authenticating requests with RBAC users.
+type fakeRBAC struct {
+	mu          sync.Mutex
+	tokens      map[string]string // user name to token
+	failDelete  bool
+	rejectProbe bool
+	addedRoles  []string // paths and roles of POST .../roles
+}
+
+func (f *fakeRBAC) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	authenticated := false
+	for _, token := range f.tokens {
+		if token == r.Header.Get(AdminTokenHeader) {
+			authenticated = true
+		}
+	}
+	if !authenticated || (r.URL.Path == "/" && f.rejectProbe) {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"message":"Invalid credentials"}`))
+		return
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/rbac/users/ci/roles":
+		_, _ = w.Write([]byte(`{"roles":[{"name":"admin"}]}`))
+	case r.Method == http.MethodGet && r.URL.Path == "/ws2/rbac/users/ci/roles":
+		_, _ = w.Write([]byte(`{"roles":[{"name":"read-only"},{"name":"ops"}]}`))
+	case r.Method == http.MethodPost && r.URL.Path == "/rbac/users":
+		var user kong.RBACUser
+		_ = json.NewDecoder(r.Body).Decode(&user)
+		f.tokens[*user.Name] = *user.UserToken
+		user.ID = kong.String("id-" + *user.Name)
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(user)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/rbac/users/ci-2/roles"):
+		var body struct {
+			Roles string `json:"roles"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.addedRoles = append(f.addedRoles, r.URL.Path+" "+body.Roles)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"roles":[{"name":"admin"}]}`))
+	case r.Method == http.MethodDelete && !(f.failDelete && r.URL.Path == "/rbac/users/ci"):
+		delete(f.tokens, r.URL.Path[len("/rbac/users/"):])
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodGet && r.URL.Path == "/":
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func newRBACTestClient(t *testing.T, f *fakeRBAC, token *AdminToken) *Client {
+	t.Helper()
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	httpClient := &http.Client{Transport: token.Middleware()(http.DefaultTransport)}
+	kongClient, err := kong.NewClient(kong.String(server.URL), httpClient)
+	require.NoError(t, err)
+	client, err := NewClient(kongClient)
+	require.NoError(t, err)
+	return client
+}
+
+func TestRotateRBACToken(t *testing.T) {
+	ctx := context.Background()
+	opt := TokenRotation{User: "ci", NewUser: "ci-2"}
+
+	t.Run("rotates the token", func(t *testing.T) {
+		f := &fakeRBAC{tokens: map[string]string{"ci": "old"}}
+		token := NewAdminToken("old")
+		client := newRBACTestClient(t, f, token)
+
+		user, err := client.RotateRBACToken(ctx, token, opt)
+		require.NoError(t, err)
+		assert.Equal(t, "ci-2", *user.Name)
+		assert.NotEmpty(t, *user.UserToken)
+		assert.Equal(t, *user.UserToken, token.Get())
+		assert.Equal(t, map[string]string{"ci-2": token.Get()}, f.tokens)
+		assert.Equal(t, []string{"/rbac/users/ci-2/roles admin"}, f.addedRoles)
+	})
+
+	t.Run("copies roles of other workspaces", func(t *testing.T) {
+		f := &fakeRBAC{tokens: map[string]string{"ci": "old"}}
+		token := NewAdminToken("old")
+		client := newRBACTestClient(t, f, token)
+
+		opt := opt
+		opt.Workspaces = []string{"ws2"}
+		_, err := client.RotateRBACToken(ctx, token, opt)
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"/rbac/users/ci-2/roles admin",
+			"/ws2/rbac/users/ci-2/roles read-only,ops",
+		}, f.addedRoles)
+	})
+
+	t.Run("fails without the token middleware", func(t *testing.T) {
+		f := &fakeRBAC{tokens: map[string]string{"ci": "old"}}
+		token := NewAdminToken("old")
+		server := httptest.NewServer(f)
+		defer server.Close()
+		// the old token is sent by the static headers of the client
+		kongClient, err := kong.NewClient(kong.String(server.URL), kong.HTTPClientWithHeaders(
+			nil, http.Header{AdminTokenHeader: []string{"old"}}))
+		require.NoError(t, err)
+		client, err := NewClient(kongClient)
+		require.NoError(t, err)
+
+		_, err = client.RotateRBACToken(ctx, token, opt)
+		assert.ErrorContains(t, err, "Middleware of the AdminToken is not installed")
+		assert.Equal(t, map[string]string{"ci": "old"}, f.tokens)
+	})
+
+	t.Run("undoes the rotation if the probe fails", func(t *testing.T) {
+		f := &fakeRBAC{tokens: map[string]string{"ci": "old"}, rejectProbe: true}
+		token := NewAdminToken("old")
+		client := newRBACTestClient(t, f, token)
+
+		_, err := client.RotateRBACToken(ctx, token, opt)
+		assert.ErrorContains(t, err, "probing new token")
+		assert.Equal(t, "old", token.Get())
+		assert.Equal(t, map[string]string{"ci": "old"}, f.tokens)
+	})
+
+	t.Run("undoes the rotation if the old user cannot be deleted", func(t *testing.T) {
+		f := &fakeRBAC{tokens: map[string]string{"ci": "old"}, failDelete: true}
+		token := NewAdminToken("old")
+		client := newRBACTestClient(t, f, token)
+
+		opt := opt
+		opt.Token = "new"
+		_, err := client.RotateRBACToken(ctx, token, opt)
+		assert.ErrorContains(t, err, "deleting RBAC user ci")
+		assert.Equal(t, "old", token.Get())
+		assert.Equal(t, map[string]string{"ci": "old"}, f.tokens)
+	})
+}
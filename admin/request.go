@@ -0,0 +1,117 @@
+package admin
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/kong/deck/utils/httpbody"
+)
+
+// Logger receives the requests sent by a Client.
+// It is satisfied by *slog.Logger.
+type Logger interface {
+	Debug(msg string, args ...any)
+}
+
+// RequestOptions customizes the requests sent with a context.
+type RequestOptions struct {
+	// Headers are set on the requests, replacing headers of the same name.
+	Headers http.Header
+	// Query parameters are added to the query string of the requests.
+	Query url.Values
+}
+
+type requestOptionsKey struct{}
+
+// WithRequestOptions returns a copy of ctx carrying opts, which apply to
+// the requests sent with it by the methods of Client and its services.
+// Options already carried by ctx are kept, unless overridden by opts.
+func WithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	merged := requestOptions(ctx)
+	merged.Headers = merged.Headers.Clone()
+	if merged.Headers == nil {
+		merged.Headers = http.Header{}
+	}
+	for name, values := range opts.Headers {
+		merged.Headers[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	query := url.Values{}
+	for name, values := range merged.Query {
+		query[name] = values
+	}
+	for name, values := range opts.Query {
+		query[name] = append([]string(nil), values...)
+	}
+	merged.Query = query
+	return context.WithValue(ctx, requestOptionsKey{}, merged)
+}
+
+func requestOptions(ctx context.Context) RequestOptions {
+	opts, _ := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	return opts
+}
+
+// bufferBody makes the body of req replayable, by reading it in memory
+// if it can't be recreated with GetBody. Without it, redirects and
+// retries of the request would send an empty body.
+func bufferBody(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody || req.GetBody != nil {
+		return nil
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return fmt.Errorf("reading request body: %w", err)
+	}
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return nil
+}
+
+// doRaw sends req with the underlying go-kong Client, with a fresh body
+// and the headers and query parameters of the Client and ctx.
+func (c *Client) doRaw(ctx context.Context, req *http.Request) (*http.Response, error) {
+	req, err := httpbody.Rewind(req)
+	if err != nil {
+		return nil, err
+	}
+	if ctx == nil {
+		ctx = req.Context()
+	}
+	opts := requestOptions(ctx)
+	if len(c.headers) > 0 || len(opts.Headers) > 0 || len(opts.Query) > 0 {
+		req = req.Clone(ctx)
+		for _, headers := range []http.Header{c.headers, opts.Headers} {
+			for name, values := range headers {
+				req.Header[name] = values
+			}
+		}
+		if len(opts.Query) > 0 {
+			query := req.URL.Query()
+			for name, values := range opts.Query {
+				query[name] = append(query[name], values...)
+			}
+			req.URL.RawQuery = query.Encode()
+		}
+	}
+
+	start := time.Now()
+	resp, err := c.kong.DoRAW(ctx, req)
+	if c.logger != nil {
+		args := []any{"method", req.Method, "url", req.URL.String(), "duration", time.Since(start)}
+		if err != nil {
+			c.logger.Debug("request failed", append(args, "error", err.Error())...)
+		} else {
+			c.logger.Debug("request", append(args, "status", resp.StatusCode)...)
+		}
+	}
+	return resp, err
+}
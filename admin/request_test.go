@@ -0,0 +1,146 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDoResendsBody(t *testing.T) {
+	var bodies []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.URL.Path+" "+string(b))
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusTemporaryRedirect)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	ctx := context.Background()
+
+	// a reader without GetBody support
+	body := io.MultiReader(strings.NewReader(`{"name":"foo"}`))
+	req, err := client.NewRootRequest(http.MethodPost, "/old", nil, body)
+	require.NoError(t, err)
+	_, err = client.Do(ctx, req, nil)
+	require.NoError(t, err)
+	// the request can be sent again
+	_, err = client.Do(ctx, req, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`/old {"name":"foo"}`, `/new {"name":"foo"}`,
+		`/old {"name":"foo"}`, `/new {"name":"foo"}`,
+	}, bodies)
+}
+
+type recordingLogger struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+func (l *recordingLogger) Debug(msg string, args ...any) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.messages = append(l.messages, fmt.Sprint(append([]any{msg}, args...)...))
+}
+
+func TestClientClone(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s?%s token=%s",
+			r.URL.Path, r.URL.RawQuery, r.Header.Get("Kong-Admin-Token")))
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	ctx := context.Background()
+
+	logger := &recordingLogger{}
+	tenant := client.Clone(CloneOptions{
+		Workspace: kong.String("tenant1"),
+		Headers:   http.Header{"kong-admin-token": {"tenant1-token"}},
+		Logger:    logger,
+	})
+	require.NoError(t, tenant.Send(ctx, http.MethodGet, "/services/foo", nil, nil, nil))
+	require.NoError(t, client.Send(ctx, http.MethodGet, "/services/foo", nil, nil, nil))
+
+	// clones of clones keep the overrides they don't replace
+	defaultWorkspace := tenant.Clone(CloneOptions{Workspace: kong.String("")})
+	require.NoError(t, defaultWorkspace.Send(ctx, http.MethodGet, "/services/foo", nil, nil, nil))
+
+	// per-call options
+	callCtx := WithRequestOptions(ctx, RequestOptions{
+		Headers: http.Header{"Kong-Admin-Token": {"call-token"}},
+		Query:   url.Values{"tags": {"a"}},
+	})
+	callCtx = WithRequestOptions(callCtx, RequestOptions{Query: url.Values{"size": {"10"}}})
+	require.NoError(t, tenant.Send(callCtx, http.MethodGet, "/services", nil, nil, nil))
+
+	assert.Equal(t, []string{
+		"/tenant1/services/foo? token=tenant1-token",
+		"/services/foo? token=",
+		"/services/foo? token=tenant1-token",
+		"/tenant1/services?size=10&tags=a token=call-token",
+	}, requests)
+	assert.Len(t, logger.messages, 3)
+	assert.Contains(t, logger.messages[0], "/tenant1/services/foo")
+}
+
+func TestClientCloneHigherLevelMethods(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, fmt.Sprintf("%s %s token=%s",
+			r.Method, r.URL.Path, r.Header.Get("Kong-Admin-Token")))
+		if r.Method == http.MethodGet && r.URL.Path == "/tenant1/consumers" {
+			_, _ = w.Write([]byte(`{"data":[{"id":"c1","custom_id":"user-42"}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	tenant := client.Clone(CloneOptions{
+		Workspace: kong.String("tenant1"),
+		Headers:   http.Header{"Kong-Admin-Token": {"tenant1-token"}},
+	})
+	ctx := WithRequestOptions(context.Background(), RequestOptions{
+		Headers: http.Header{"Kong-Admin-Token": {"call-token"}},
+	})
+
+	_, err := tenant.Consumers.GetByCustomID(ctx, "user-42")
+	require.NoError(t, err)
+	_, err = tenant.Services.ListAllPlugins(context.Background(), "svc1")
+	require.NoError(t, err)
+	_, err = tenant.Services.ListAllRoutes(context.Background(), "svc1")
+	require.NoError(t, err)
+	_, err = tenant.Routes.ListAllPlugins(context.Background(), "r1")
+	require.NoError(t, err)
+	_, err = tenant.Upstreams.Health(context.Background(), "u1")
+	require.NoError(t, err)
+	for page := range tenant.StreamServices(context.Background(), nil) {
+		require.NoError(t, page.Err)
+	}
+	_, err = tenant.Services.CreateBatch(context.Background(), []*kong.Service{
+		{ID: kong.String("s1"), Host: kong.String("example.com")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, tenant.Plugins.DeleteBatch(context.Background(), []string{"p1"}))
+
+	assert.Equal(t, []string{
+		"GET /tenant1/consumers token=call-token",
+		"GET /tenant1/services/svc1/plugins token=tenant1-token",
+		"GET /tenant1/services/svc1/routes token=tenant1-token",
+		"GET /tenant1/routes/r1/plugins token=tenant1-token",
+		"GET /tenant1/upstreams/u1/health token=tenant1-token",
+		"GET /tenant1/services token=tenant1-token",
+		"PUT /tenant1/services/s1 token=tenant1-token",
+		"DELETE /tenant1/plugins/p1 token=tenant1-token",
+	}, requests)
+}
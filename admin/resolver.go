@@ -0,0 +1,231 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kong/go-kong/kong"
+)
+
+var uuidRegexp = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ReferenceError is returned when a reference to an entity can't be
+// resolved to a single entity.
+type ReferenceError struct {
+	// Kind is the type of the referenced entity, e.g. "service".
+	Kind string
+	// Reference is the name, or other identifier, used in the reference.
+	Reference string
+	// Candidates are the IDs of the entities matching an ambiguous
+	// reference. It is empty if no entity matches.
+	Candidates []string
+}
+
+func (e *ReferenceError) Error() string {
+	if len(e.Candidates) > 1 {
+		return fmt.Sprintf("ambiguous reference to %s '%s': matches entities %s",
+			e.Kind, e.Reference, strings.Join(e.Candidates, ", "))
+	}
+	return fmt.Sprintf("%s '%s' not found", e.Kind, e.Reference)
+}
+
+// NotFound reports whether no entity matches the reference.
+func (e *ReferenceError) NotFound() bool {
+	return len(e.Candidates) == 0
+}
+
+// Resolver resolves references to entities by name into references by
+// ID, as expected by the Admin API for the relations of entities, such
+// as the Service of a Route or the Consumer of a Plugin.
+// Resolved IDs are cached for the lifetime of the Resolver.
+// It is safe for concurrent use.
+type Resolver struct {
+	client *Client
+
+	mu  sync.Mutex
+	ids map[string]string
+}
+
+// NewResolver returns a Resolver for the entities of the workspace
+// of the Client.
+func (c *Client) NewResolver() *Resolver {
+	return &Resolver{client: c, ids: map[string]string{}}
+}
+
+// ServiceID returns the ID of the Service with the name or ID nameOrID.
+func (r *Resolver) ServiceID(ctx context.Context, nameOrID string) (string, error) {
+	return r.resolve(ctx, "service", "/services", nameOrID, "")
+}
+
+// RouteID returns the ID of the Route with the name or ID nameOrID.
+func (r *Resolver) RouteID(ctx context.Context, nameOrID string) (string, error) {
+	return r.resolve(ctx, "route", "/routes", nameOrID, "")
+}
+
+// UpstreamID returns the ID of the Upstream with the name or ID nameOrID.
+func (r *Resolver) UpstreamID(ctx context.Context, nameOrID string) (string, error) {
+	return r.resolve(ctx, "upstream", "/upstreams", nameOrID, "")
+}
+
+// ConsumerGroupID returns the ID of the Consumer Group with the name
+// or ID nameOrID.
+func (r *Resolver) ConsumerGroupID(ctx context.Context, nameOrID string) (string, error) {
+	return r.resolve(ctx, "consumer group", "/consumer_groups", nameOrID, "")
+}
+
+// ConsumerID returns the ID of the Consumer with the username, custom ID
+// or ID ref. The reference is ambiguous if the username of a Consumer is
+// the custom ID of another.
+func (r *Resolver) ConsumerID(ctx context.Context, ref string) (string, error) {
+	return r.resolve(ctx, "consumer", "/consumers", ref, "custom_id")
+}
+
+// resolve returns the ID of the entity of kind at endpoint, identified by
+// ref, or by the field altField if set.
+func (r *Resolver) resolve(ctx context.Context, kind, endpoint, ref, altField string) (string, error) {
+	if ref == "" {
+		return "", fmt.Errorf("empty reference to %s", kind)
+	}
+	cacheKey := kind + "\x00" + ref
+	r.mu.Lock()
+	id, ok := r.ids[cacheKey]
+	r.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	candidates := map[string]struct{}{}
+	var entity struct {
+		ID string `json:"id"`
+	}
+	err := r.client.Send(ctx, http.MethodGet, endpoint+"/"+url.PathEscape(ref), nil, nil, &entity)
+	switch {
+	case err == nil:
+		candidates[entity.ID] = struct{}{}
+	case !kong.IsNotFoundErr(err):
+		return "", fmt.Errorf("resolving %s '%s': %w", kind, ref, err)
+	}
+	if altField != "" {
+		var list struct {
+			Data []struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		qs := url.Values{altField: {ref}}
+		err := r.client.Send(ctx, http.MethodGet, endpoint+"?"+qs.Encode(), nil, nil, &list)
+		if err != nil {
+			return "", fmt.Errorf("resolving %s '%s': %w", kind, ref, err)
+		}
+		for _, e := range list.Data {
+			candidates[e.ID] = struct{}{}
+		}
+	}
+	ids := make([]string, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+	if len(ids) != 1 {
+		sort.Strings(ids)
+		return "", &ReferenceError{Kind: kind, Reference: ref, Candidates: ids}
+	}
+	id = ids[0]
+	r.mu.Lock()
+	r.ids[cacheKey] = id
+	r.mu.Unlock()
+	return id, nil
+}
+
+// resolveRef sets the ID of a reference holding a name, or an ID which
+// may be a name, with resolve, and clears its name.
+func resolveRef(ctx context.Context, id, name **string,
+	resolve func(context.Context, string) (string, error),
+) error {
+	ref := ""
+	switch {
+	case *id != nil && **id != "":
+		if uuidRegexp.MatchString(**id) {
+			return nil
+		}
+		ref = **id
+	case name != nil && *name != nil && **name != "":
+		ref = **name
+	default:
+		return nil
+	}
+	resolved, err := resolve(ctx, ref)
+	if err != nil {
+		return err
+	}
+	*id = kong.String(resolved)
+	if name != nil {
+		*name = nil
+	}
+	return nil
+}
+
+// ResolveRoute replaces the reference to the Service of route by name
+// with a reference by ID.
+func (r *Resolver) ResolveRoute(ctx context.Context, route *kong.Route) error {
+	if route.Service == nil {
+		return nil
+	}
+	if err := resolveRef(ctx, &route.Service.ID, &route.Service.Name, r.ServiceID); err != nil {
+		return fmt.Errorf("route %s: %w", route.FriendlyName(), err)
+	}
+	return nil
+}
+
+// ResolvePlugin replaces the references to the Service, Route and
+// Consumer of plugin by name with references by ID.
+func (r *Resolver) ResolvePlugin(ctx context.Context, plugin *kong.Plugin) error {
+	var err error
+	if plugin.Service != nil {
+		err = resolveRef(ctx, &plugin.Service.ID, &plugin.Service.Name, r.ServiceID)
+	}
+	if err == nil && plugin.Route != nil {
+		err = resolveRef(ctx, &plugin.Route.ID, &plugin.Route.Name, r.RouteID)
+	}
+	if err == nil && plugin.Consumer != nil {
+		err = resolveRef(ctx, &plugin.Consumer.ID, &plugin.Consumer.Username, r.ConsumerID)
+	}
+	if err != nil {
+		return fmt.Errorf("plugin %s: %w", plugin.FriendlyName(), err)
+	}
+	return nil
+}
+
+// ResolveTarget replaces the reference to the Upstream of target by name
+// with a reference by ID.
+func (r *Resolver) ResolveTarget(ctx context.Context, target *kong.Target) error {
+	if target.Upstream == nil {
+		return nil
+	}
+	if err := resolveRef(ctx, &target.Upstream.ID, &target.Upstream.Name, r.UpstreamID); err != nil {
+		return fmt.Errorf("target %s: %w", target.FriendlyName(), err)
+	}
+	return nil
+}
+
+// ResolveConsumerGroupPlugin replaces the reference to the Consumer Group
+// of plugin by name with a reference by ID.
+// Plugins scoped to a Consumer Group are ConsumerGroupPlugins, as
+// kong.Plugin has no consumer_group field.
+func (r *Resolver) ResolveConsumerGroupPlugin(ctx context.Context,
+	plugin *kong.ConsumerGroupPlugin,
+) error {
+	if plugin.ConsumerGroup == nil {
+		return nil
+	}
+	err := resolveRef(ctx, &plugin.ConsumerGroup.ID, &plugin.ConsumerGroup.Name, r.ConsumerGroupID)
+	if err != nil {
+		return fmt.Errorf("consumer group plugin %s: %w", upsertKey(plugin.ID, plugin.Name), err)
+	}
+	return nil
+}
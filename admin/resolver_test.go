@@ -0,0 +1,96 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	svcID   = "5f1c2a3e-1111-4e5b-9c1d-000000000001"
+	aliceID = "5f1c2a3e-1111-4e5b-9c1d-000000000002"
+	bobID   = "5f1c2a3e-1111-4e5b-9c1d-000000000003"
+)
+
+func TestResolver(t *testing.T) {
+	requests := 0
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		switch r.URL.Path + "?" + r.URL.RawQuery {
+		case "/services/svc?":
+			_, _ = w.Write([]byte(`{"id":"` + svcID + `","name":"svc"}`))
+		case "/consumers/alice?":
+			_, _ = w.Write([]byte(`{"id":"` + aliceID + `","username":"alice"}`))
+		case "/consumers?custom_id=alice":
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		case "/consumers?custom_id=bob":
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + bobID + `","custom_id":"bob"}]}`))
+		case "/consumers/carol?":
+			_, _ = w.Write([]byte(`{"id":"` + aliceID + `","username":"carol"}`))
+		case "/consumers?custom_id=carol":
+			_, _ = w.Write([]byte(`{"data":[{"id":"` + bobID + `","custom_id":"carol"}]}`))
+		case "/consumers?custom_id=dave":
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		case "/consumer_groups/gold?":
+			_, _ = w.Write([]byte(`{"id":"` + bobID + `","name":"gold"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		}
+	}))
+	ctx := context.Background()
+	resolver := client.NewResolver()
+
+	route := &kong.Route{Name: kong.String("r1"), Service: &kong.Service{Name: kong.String("svc")}}
+	require.NoError(t, resolver.ResolveRoute(ctx, route))
+	assert.Equal(t, &kong.Service{ID: kong.String(svcID)}, route.Service)
+
+	// IDs are cached
+	seen := requests
+	plugin := &kong.Plugin{
+		Name:     kong.String("key-auth"),
+		Service:  &kong.Service{ID: kong.String("svc")},
+		Consumer: &kong.Consumer{Username: kong.String("bob")},
+	}
+	require.NoError(t, resolver.ResolvePlugin(ctx, plugin))
+	assert.Equal(t, svcID, *plugin.Service.ID)
+	assert.Equal(t, &kong.Consumer{ID: kong.String(bobID)}, plugin.Consumer)
+	assert.Equal(t, seen+2, requests)
+
+	// references by UUID are left as is
+	seen = requests
+	target := &kong.Target{Target: kong.String("10.0.0.1:80"), Upstream: &kong.Upstream{ID: kong.String(svcID)}}
+	require.NoError(t, resolver.ResolveTarget(ctx, target))
+	assert.Equal(t, svcID, *target.Upstream.ID)
+	assert.Equal(t, seen, requests)
+
+	groupPlugin := &kong.ConsumerGroupPlugin{
+		Name:          kong.String("rate-limiting-advanced"),
+		ConsumerGroup: &kong.ConsumerGroup{Name: kong.String("gold")},
+	}
+	require.NoError(t, resolver.ResolveConsumerGroupPlugin(ctx, groupPlugin))
+	assert.Equal(t, &kong.ConsumerGroup{ID: kong.String(bobID)}, groupPlugin.ConsumerGroup)
+
+	id, err := resolver.ConsumerID(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, aliceID, id)
+
+	_, err = resolver.ConsumerID(ctx, "carol")
+	var refErr *ReferenceError
+	require.True(t, errors.As(err, &refErr))
+	assert.False(t, refErr.NotFound())
+	assert.EqualError(t, err, "ambiguous reference to consumer 'carol': matches entities "+aliceID+", "+bobID)
+
+	err = resolver.ResolvePlugin(ctx, &kong.Plugin{
+		Name:     kong.String("acl"),
+		Consumer: &kong.Consumer{ID: kong.String("dave")},
+	})
+	require.True(t, errors.As(err, &refErr))
+	assert.True(t, refErr.NotFound())
+	assert.EqualError(t, err, "plugin acl: consumer 'dave' not found")
+}
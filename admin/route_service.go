@@ -0,0 +1,19 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// ListAllPlugins fetches all plugins configured for a Route.
+func (s *RouteService) ListAllPlugins(ctx context.Context,
+	routeNameOrID string,
+) ([]*kong.Plugin, error) {
+	if routeNameOrID == "" {
+		return nil, fmt.Errorf("routeNameOrID cannot be empty")
+	}
+	return NewEntityService[kong.Plugin](s.client, "/routes/{route}/plugins").
+		With(routeNameOrID).ListAll(ctx)
+}
@@ -0,0 +1,113 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// SchemaService handles entity and plugin schemas in Kong.
+type SchemaService service
+
+// Get fetches the schema of an entity, e.g. "services" or "routes".
+func (s *SchemaService) Get(ctx context.Context, entity string) (kong.Schema, error) {
+	if entity == "" {
+		return nil, fmt.Errorf("entity cannot be empty for Get operation")
+	}
+	return s.get(ctx, fmt.Sprintf("/schemas/%s", entity))
+}
+
+// GetPluginSchema fetches the schema of the plugin named name.
+func (s *SchemaService) GetPluginSchema(ctx context.Context, name string) (kong.Schema, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name cannot be empty for GetPluginSchema operation")
+	}
+	return s.get(ctx, fmt.Sprintf("/schemas/plugins/%s", name))
+}
+
+func (s *SchemaService) get(ctx context.Context, endpoint string) (kong.Schema, error) {
+	req, err := s.client.NewRequest(http.MethodGet, endpoint, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var schema kong.Schema
+	_, err = s.client.Do(ctx, req, &schema)
+	if err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// ValidationResult holds the result of a server-side schema validation.
+type ValidationResult struct {
+	Valid   bool
+	Message string
+	// Fields maps invalid fields to their error messages.
+	Fields map[string]interface{}
+}
+
+// Validate validates obj against the schema of entity using Kong,
+// without creating it.
+// An error is returned only if the validation could not be performed,
+// an invalid entity is reported in the ValidationResult.
+func (s *SchemaService) Validate(ctx context.Context, entity string,
+	obj interface{},
+) (*ValidationResult, error) {
+	if entity == "" {
+		return nil, fmt.Errorf("entity cannot be empty for Validate operation")
+	}
+	return s.validate(ctx, fmt.Sprintf("/schemas/%s/validate", entity), obj)
+}
+
+// ValidatePlugin validates the configuration of plugin using Kong,
+// without creating it.
+func (s *SchemaService) ValidatePlugin(ctx context.Context,
+	plugin *kong.Plugin,
+) (*ValidationResult, error) {
+	if plugin == nil {
+		return nil, fmt.Errorf("cannot validate a nil plugin")
+	}
+	return s.validate(ctx, "/schemas/plugins/validate", plugin)
+}
+
+func (s *SchemaService) validate(ctx context.Context, endpoint string,
+	obj interface{},
+) (*ValidationResult, error) {
+	if obj == nil {
+		return nil, fmt.Errorf("cannot validate a nil entity")
+	}
+	req, err := s.client.NewRequest(http.MethodPost, endpoint, nil, obj)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.doRaw(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading validation response body: %w", err)
+	}
+	var result struct {
+		Message string                 `json:"message"`
+		Fields  map[string]interface{} `json:"fields"`
+	}
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		_ = json.Unmarshal(body, &result)
+		return &ValidationResult{Valid: true, Message: result.Message}, nil
+	case resp.StatusCode == http.StatusBadRequest:
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, kong.NewAPIErrorWithRaw(resp.StatusCode, messageFromBody(body), body)
+		}
+		return &ValidationResult{Message: result.Message, Fields: result.Fields}, nil
+	default:
+		return nil, kong.NewAPIErrorWithRaw(resp.StatusCode, messageFromBody(body), body)
+	}
+}
@@ -0,0 +1,65 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaServiceGet(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/schemas/services":
+			_, _ = w.Write([]byte(`{"fields":[{"host":{"type":"string"}}]}`))
+		case "/schemas/plugins/key-auth":
+			_, _ = w.Write([]byte(`{"fields":[{"config":{"type":"record"}}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"Not found"}`))
+		}
+	}))
+
+	schema, err := client.Schemas.Get(context.Background(), "services")
+	require.NoError(t, err)
+	assert.Contains(t, schema, "fields")
+
+	schema, err = client.Schemas.GetPluginSchema(context.Background(), "key-auth")
+	require.NoError(t, err)
+	assert.Contains(t, schema, "fields")
+
+	_, err = client.Schemas.GetPluginSchema(context.Background(), "foo")
+	assert.True(t, kong.IsNotFoundErr(err))
+}
+
+func TestSchemaServiceValidate(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var plugin kong.Plugin
+		_ = json.NewDecoder(r.Body).Decode(&plugin)
+		if plugin.Name != nil && *plugin.Name == "key-auth" {
+			_, _ = w.Write([]byte(`{"message":"schema validation successful"}`))
+			return
+		}
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"message":"schema violation (name: plugin 'foo' not enabled)",` +
+			`"fields":{"name":"plugin 'foo' not enabled"}}`))
+	}))
+
+	res, err := client.Schemas.ValidatePlugin(context.Background(),
+		&kong.Plugin{Name: kong.String("key-auth")})
+	require.NoError(t, err)
+	assert.True(t, res.Valid)
+
+	res, err = client.Schemas.Validate(context.Background(), "plugins",
+		&kong.Plugin{Name: kong.String("foo")})
+	require.NoError(t, err)
+	assert.False(t, res.Valid)
+	assert.Equal(t, "plugin 'foo' not enabled", res.Fields["name"])
+
+	_, err = client.Schemas.ValidatePlugin(context.Background(), nil)
+	assert.Error(t, err)
+}
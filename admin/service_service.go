@@ -0,0 +1,48 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// ListRoutes fetches a page of Routes of a Service.
+// opt can be used to control pagination and filter by tags.
+func (s *ServiceService) ListRoutes(ctx context.Context,
+	serviceNameOrID string, opt *kong.ListOpt,
+) ([]*kong.Route, *kong.ListOpt, error) {
+	if serviceNameOrID == "" {
+		return nil, nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return NewEntityService[kong.Route](s.client, "/services/{service}/routes").
+		With(serviceNameOrID).List(ctx, opt)
+}
+
+// ListAllRoutes fetches all Routes of a Service.
+func (s *ServiceService) ListAllRoutes(ctx context.Context,
+	serviceNameOrID string,
+) ([]*kong.Route, error) {
+	var routes []*kong.Route
+	opt := &kong.ListOpt{Size: pageSize}
+	for opt != nil {
+		data, next, err := s.ListRoutes(ctx, serviceNameOrID, opt)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, data...)
+		opt = next
+	}
+	return routes, nil
+}
+
+// ListAllPlugins fetches all plugins configured for a Service.
+func (s *ServiceService) ListAllPlugins(ctx context.Context,
+	serviceNameOrID string,
+) ([]*kong.Plugin, error) {
+	if serviceNameOrID == "" {
+		return nil, fmt.Errorf("serviceNameOrID cannot be empty")
+	}
+	return NewEntityService[kong.Plugin](s.client, "/services/{service}/plugins").
+		With(serviceNameOrID).ListAll(ctx)
+}
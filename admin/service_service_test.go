@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceAndRouteRelations(t *testing.T) {
+	server := kongtest.NewServer()
+	t.Cleanup(server.Close)
+	kongClient, err := server.KongClient()
+	require.NoError(t, err)
+	client, err := NewClient(kongClient)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for _, name := range []string{"svc1", "svc2"} {
+		_, err := kongClient.Services.Create(ctx, &kong.Service{Name: kong.String(name), Host: kong.String("example.com")})
+		require.NoError(t, err)
+	}
+	svc1 := &kong.Service{Name: kong.String("svc1")}
+	for _, name := range []string{"r1", "r2", "r3"} {
+		_, err := kongClient.Routes.CreateInService(ctx, kong.String("svc1"), &kong.Route{Name: kong.String(name)})
+		require.NoError(t, err)
+	}
+	_, err = kongClient.Routes.CreateInService(ctx, kong.String("svc2"), &kong.Route{Name: kong.String("other")})
+	require.NoError(t, err)
+	_, err = kongClient.Plugins.Create(ctx, &kong.Plugin{Name: kong.String("cors"), Service: svc1})
+	require.NoError(t, err)
+	r1, err := kongClient.Routes.Get(ctx, kong.String("r1"))
+	require.NoError(t, err)
+	_, err = kongClient.Plugins.Create(ctx, &kong.Plugin{
+		Name:  kong.String("key-auth"),
+		Route: &kong.Route{ID: r1.ID},
+	})
+	require.NoError(t, err)
+
+	routes, next, err := client.Services.ListRoutes(ctx, "svc1", &kong.ListOpt{Size: 2})
+	require.NoError(t, err)
+	assert.Len(t, routes, 2)
+	require.NotNil(t, next)
+	routes, next, err = client.Services.ListRoutes(ctx, "svc1", next)
+	require.NoError(t, err)
+	assert.Len(t, routes, 1)
+	assert.Nil(t, next)
+
+	routes, err = client.Services.ListAllRoutes(ctx, "svc1")
+	require.NoError(t, err)
+	assert.Len(t, routes, 3)
+
+	plugins, err := client.Services.ListAllPlugins(ctx, "svc1")
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "cors", *plugins[0].Name)
+
+	plugins, err = client.Routes.ListAllPlugins(ctx, "r1")
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.Equal(t, "key-auth", *plugins[0].Name)
+
+	plugins, err = client.Routes.ListAllPlugins(ctx, "r2")
+	require.NoError(t, err)
+	assert.Empty(t, plugins)
+
+	_, err = client.Services.ListAllRoutes(ctx, "missing")
+	assert.True(t, kong.IsNotFoundErr(err))
+	_, err = client.Routes.ListAllPlugins(ctx, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,92 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// DefaultStatusAddress is the default address of the Status API.
+const DefaultStatusAddress = "http://localhost:8100"
+
+// DefaultReadyInterval is the interval used by WaitReady when the one
+// given is not positive.
+const DefaultReadyInterval = time.Second
+
+// StatusClient talks to the Status API of a Kong node, which exposes the
+// status, readiness and metrics of the node without authentication.
+// Unlike the Admin API, it is available on data plane nodes.
+type StatusClient struct {
+	client *Client
+}
+
+// NewStatusClient returns a StatusClient for the Status API at address.
+// If httpClient is nil, a client with default timeouts is used.
+func NewStatusClient(address string, httpClient *http.Client) (*StatusClient, error) {
+	if address == "" {
+		address = DefaultStatusAddress
+	}
+	kongClient, err := kong.NewClient(kong.String(address), httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("creating client for Kong's Status API: %w", err)
+	}
+	client, err := NewClient(kongClient)
+	if err != nil {
+		return nil, err
+	}
+	return &StatusClient{client: client}, nil
+}
+
+// Status fetches the status of the node.
+func (c *StatusClient) Status(ctx context.Context) (*Status, error) {
+	return c.client.Status.Get(ctx)
+}
+
+// Ready checks whether the node is ready to proxy traffic.
+// Unready nodes are reported through the result, not as an error.
+func (c *StatusClient) Ready(ctx context.Context) (*Readiness, error) {
+	return c.client.Status.Ready(ctx)
+}
+
+// Metrics fetches the Prometheus metrics of the node.
+// The Prometheus plugin must be enabled.
+func (c *StatusClient) Metrics(ctx context.Context) (*Metrics, error) {
+	return c.client.Metrics(ctx)
+}
+
+// WaitReady polls the readiness of the node every interval until it is
+// ready or ctx is done, e.g. to gate the rollout of data planes.
+// Errors reaching the node are retried, since a starting node may not
+// listen yet; the last one is returned if ctx is done first.
+// DefaultReadyInterval is used if interval is not positive.
+func (c *StatusClient) WaitReady(ctx context.Context, interval time.Duration) error {
+	if interval <= 0 {
+		interval = DefaultReadyInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	var last error
+	for {
+		readiness, err := c.Ready(ctx)
+		switch {
+		case err != nil:
+			// keep the reason of the previous check over
+			// the cancellation of this one
+			if ctx.Err() == nil || last == nil {
+				last = err
+			}
+		case readiness.Ready:
+			return nil
+		default:
+			last = fmt.Errorf("node not ready: %s", readiness.Message)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for node to be ready: %w: %v", ctx.Err(), last)
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusClient(t *testing.T) {
+	var readyChecks int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Empty(t, r.Header.Get("Kong-Admin-Token"))
+		switch r.URL.Path {
+		case "/status":
+			_, _ = w.Write([]byte(`{"configuration_hash":"abc","server":{"connections_active":3}}`))
+		case "/status/ready":
+			if atomic.AddInt32(&readyChecks, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"message":"no configuration available"}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"message":"ready"}`))
+		case "/metrics":
+			_, _ = w.Write([]byte("kong_nginx_connections_total{state=\"active\"} 3\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewStatusClient(server.URL, server.Client())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	status, err := client.Status(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", status.ConfigurationHash)
+
+	readiness, err := client.Ready(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, &Readiness{Message: "no configuration available"}, readiness)
+
+	require.NoError(t, client.WaitReady(ctx, time.Millisecond))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&readyChecks))
+
+	metrics, err := client.Metrics(ctx)
+	require.NoError(t, err)
+	value, ok := metrics.Value("kong_nginx_connections_total", map[string]string{"state": "active"})
+	assert.True(t, ok)
+	assert.Equal(t, 3.0, value)
+}
+
+func TestStatusClientWaitReadyTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"message":"failed to connect to database"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewStatusClient(server.URL, server.Client())
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = client.WaitReady(ctx, time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "failed to connect to database")
+}
+
+func TestStatusClientWaitReadyDefaultInterval(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"message":"ready"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewStatusClient(server.URL, server.Client())
+	require.NoError(t, err)
+	assert.NoError(t, client.WaitReady(context.Background(), 0))
+}
@@ -0,0 +1,102 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// emptyConfigurationHash is the configuration hash reported by
+// DB-less and data plane nodes which have not loaded any configuration.
+const emptyConfigurationHash = "00000000000000000000000000000000"
+
+// SharedDictStats holds the memory usage of a lua_shared_dict.
+type SharedDictStats struct {
+	AllocatedSlabs string `json:"allocated_slabs"`
+	Capacity       string `json:"capacity"`
+}
+
+// WorkerLuaVMStats holds the memory usage of the Lua VM of a worker.
+type WorkerLuaVMStats struct {
+	HTTPAllocatedGC string `json:"http_allocated_gc"`
+	PID             int    `json:"pid"`
+}
+
+// Status represents the status of a Kong node, including
+// its memory usage and the configuration it runs.
+type Status struct {
+	kong.Status
+	Memory struct {
+		LuaSharedDicts map[string]SharedDictStats `json:"lua_shared_dicts"`
+		WorkersLuaVMs  []WorkerLuaVMStats         `json:"workers_lua_vms"`
+	} `json:"memory"`
+}
+
+// Readiness is the result of a readiness check of a Kong node.
+type Readiness struct {
+	// Ready is true if the node is ready to proxy traffic.
+	Ready bool
+	// Message explains why the node is not ready.
+	Message string
+}
+
+// StatusService handles the status of Kong nodes.
+type StatusService service
+
+// Get fetches the status of the Kong node.
+func (s *StatusService) Get(ctx context.Context) (*Status, error) {
+	req, err := s.client.NewRootRequest(http.MethodGet, "/status", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var status Status
+	if _, err := s.client.Do(ctx, req, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// Ready checks whether the Kong node is ready to proxy traffic,
+// following the semantics of /status/ready: a node with a database
+// is ready once it can reach it, a DB-less or data plane node once
+// it has loaded a configuration.
+// Unready nodes are reported through the result, not as an error.
+func (s *StatusService) Ready(ctx context.Context) (*Readiness, error) {
+	req, err := s.client.NewRootRequest(http.MethodGet, "/status/ready", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var body struct {
+		Message string `json:"message"`
+	}
+	_, err = s.client.Do(ctx, req, &body)
+	if err == nil {
+		return &Readiness{Ready: true, Message: body.Message}, nil
+	}
+	var apiErr *kong.APIError
+	if errors.As(err, &apiErr) && apiErr.Code() == http.StatusServiceUnavailable {
+		return &Readiness{Message: messageFromBody(apiErr.Raw())}, nil
+	}
+	if !kong.IsNotFoundErr(err) {
+		return nil, err
+	}
+	// Kong < 3.3 has no readiness endpoint: derive it from /status.
+	status, err := s.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return readinessFromStatus(status), nil
+}
+
+func readinessFromStatus(status *Status) *Readiness {
+	switch {
+	case status.ConfigurationHash == emptyConfigurationHash:
+		return &Readiness{Message: "no configuration available"}
+	case status.ConfigurationHash == "" && !status.Database.Reachable:
+		return &Readiness{Message: "failed to connect to database"}
+	default:
+		return &Readiness{Ready: true}
+	}
+}
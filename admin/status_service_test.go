@@ -0,0 +1,78 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusServiceGet(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/status", r.URL.Path)
+		_, _ = w.Write([]byte(`{
+			"configuration_hash": "abc",
+			"database": {"reachable": true},
+			"server": {"connections_active": 3, "total_requests": 42},
+			"memory": {
+				"lua_shared_dicts": {"kong": {"allocated_slabs": "0.04 MiB", "capacity": "5.00 MiB"}},
+				"workers_lua_vms": [{"http_allocated_gc": "50.36 MiB", "pid": 1234}]
+			}
+		}`))
+	}))
+
+	status, err := client.Status.Get(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "abc", status.ConfigurationHash)
+	assert.True(t, status.Database.Reachable)
+	assert.Equal(t, 42, status.Server.TotalRequests)
+	assert.Equal(t, "5.00 MiB", status.Memory.LuaSharedDicts["kong"].Capacity)
+	require.Len(t, status.Memory.WorkersLuaVMs, 1)
+	assert.Equal(t, 1234, status.Memory.WorkersLuaVMs[0].PID)
+}
+
+func TestStatusServiceReady(t *testing.T) {
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected Readiness
+	}{
+		{
+			name: "ready",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"message":"ready"}`))
+			},
+			expected: Readiness{Ready: true, Message: "ready"},
+		},
+		{
+			name: "not ready",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = w.Write([]byte(`{"message":"no configuration available (empty configuration present)"}`))
+			},
+			expected: Readiness{Message: "no configuration available (empty configuration present)"},
+		},
+		{
+			name: "fallback on status for old versions",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/status/ready" {
+					w.WriteHeader(http.StatusNotFound)
+					_, _ = w.Write([]byte(`{"message":"Not found"}`))
+					return
+				}
+				_, _ = w.Write([]byte(`{"configuration_hash":"00000000000000000000000000000000"}`))
+			},
+			expected: Readiness{Message: "no configuration available"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, tt.handler)
+			readiness, err := client.Status.Ready(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, *readiness)
+		})
+	}
+}
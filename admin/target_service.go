@@ -0,0 +1,73 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// TargetService handles health state of Targets in Kong.
+type TargetService service
+
+// MarkHealthy marks a target of an Upstream as healthy
+// across the Kong cluster.
+func (s *TargetService) MarkHealthy(ctx context.Context,
+	upstreamNameOrID, targetOrID string,
+) error {
+	return s.setHealth(ctx, upstreamNameOrID, targetOrID, "", "healthy")
+}
+
+// MarkUnhealthy marks a target of an Upstream as unhealthy
+// across the Kong cluster, draining traffic from it.
+func (s *TargetService) MarkUnhealthy(ctx context.Context,
+	upstreamNameOrID, targetOrID string,
+) error {
+	return s.setHealth(ctx, upstreamNameOrID, targetOrID, "", "unhealthy")
+}
+
+// MarkAddressHealthy marks a single address resolved from
+// a target of an Upstream as healthy.
+func (s *TargetService) MarkAddressHealthy(ctx context.Context,
+	upstreamNameOrID, targetOrID, address string,
+) error {
+	if address == "" {
+		return fmt.Errorf("address cannot be empty for MarkAddressHealthy operation")
+	}
+	return s.setHealth(ctx, upstreamNameOrID, targetOrID, address, "healthy")
+}
+
+// MarkAddressUnhealthy marks a single address resolved from
+// a target of an Upstream as unhealthy.
+func (s *TargetService) MarkAddressUnhealthy(ctx context.Context,
+	upstreamNameOrID, targetOrID, address string,
+) error {
+	if address == "" {
+		return fmt.Errorf("address cannot be empty for MarkAddressUnhealthy operation")
+	}
+	return s.setHealth(ctx, upstreamNameOrID, targetOrID, address, "unhealthy")
+}
+
+func (s *TargetService) setHealth(ctx context.Context,
+	upstreamNameOrID, targetOrID, address, health string,
+) error {
+	if upstreamNameOrID == "" {
+		return fmt.Errorf("upstreamNameOrID cannot be empty")
+	}
+	if targetOrID == "" {
+		return fmt.Errorf("targetOrID cannot be empty")
+	}
+	endpoint := fmt.Sprintf("/upstreams/%v/targets/%v", upstreamNameOrID,
+		url.PathEscape(targetOrID))
+	if address != "" {
+		endpoint += "/" + url.PathEscape(address)
+	}
+	endpoint += "/" + health
+
+	req, err := s.client.NewRequest(http.MethodPost, endpoint, nil, nil)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.Do(ctx, req, nil)
+	return err
+}
@@ -0,0 +1,46 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTargetServiceHealth(t *testing.T) {
+	var paths []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		paths = append(paths, r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	ctx := context.Background()
+
+	require.NoError(t, client.Targets.MarkHealthy(ctx, "up1", "10.0.0.1:80"))
+	require.NoError(t, client.Targets.MarkUnhealthy(ctx, "up1", "10.0.0.1:80"))
+	require.NoError(t, client.Targets.MarkAddressHealthy(ctx, "up1", "example.com:80", "10.0.0.2:80"))
+	require.NoError(t, client.Targets.MarkAddressUnhealthy(ctx, "up1", "example.com:80", "10.0.0.2:80"))
+	assert.Equal(t, []string{
+		"/upstreams/up1/targets/10.0.0.1:80/healthy",
+		"/upstreams/up1/targets/10.0.0.1:80/unhealthy",
+		"/upstreams/up1/targets/example.com:80/10.0.0.2:80/healthy",
+		"/upstreams/up1/targets/example.com:80/10.0.0.2:80/unhealthy",
+	}, paths)
+
+	assert.Error(t, client.Targets.MarkHealthy(ctx, "", "10.0.0.1:80"))
+	assert.Error(t, client.Targets.MarkAddressUnhealthy(ctx, "up1", "example.com:80", ""))
+}
+
+func TestUpstreamServiceBalancerHealth(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/upstreams/up1/health", r.URL.Path)
+		assert.Equal(t, "1", r.URL.Query().Get("balancer_health"))
+		_, _ = w.Write([]byte(`{"id":"u-1","data":{"health":"HEALTHY"}}`))
+	}))
+
+	health, err := client.Upstreams.BalancerHealth(context.Background(), "up1")
+	require.NoError(t, err)
+	assert.Equal(t, "HEALTHY", *health.Data.Health)
+}
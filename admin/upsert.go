@@ -0,0 +1,240 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// upsertKey returns the endpoint key used to upsert an entity:
+// its ID if set, its alternate key otherwise.
+func upsertKey(id, alternateKey *string) string {
+	if id != nil && *id != "" {
+		return *id
+	}
+	if alternateKey != nil {
+		return *alternateKey
+	}
+	return ""
+}
+
+// createOrUpdate creates or replaces entity at collection/key with
+// a single idempotent PUT request.
+func createOrUpdate[T any](ctx context.Context, c *Client,
+	collection, key string, entity *T,
+) (*T, error) {
+	if entity == nil {
+		return nil, fmt.Errorf("cannot create or update a nil entity")
+	}
+	if key == "" {
+		return nil, fmt.Errorf("an ID or a name is required to create or update an entity in %s",
+			collection)
+	}
+	return NewEntityService[T](c, collection).Upsert(ctx, key, entity)
+}
+
+// ServiceService complements go-kong's handling of Services.
+type ServiceService service
+
+// CreateOrUpdate creates or replaces a Service identified by its ID
+// or, if not set, its name.
+func (s *ServiceService) CreateOrUpdate(ctx context.Context,
+	svc *kong.Service,
+) (*kong.Service, error) {
+	if svc == nil {
+		return nil, fmt.Errorf("cannot create or update a nil service")
+	}
+	return createOrUpdate(ctx, s.client, "/services", upsertKey(svc.ID, svc.Name), svc)
+}
+
+// RouteService complements go-kong's handling of Routes.
+type RouteService service
+
+// CreateOrUpdate creates or replaces a Route identified by its ID
+// or, if not set, its name.
+// A reference to its Service by name is sent as a reference by ID.
+func (s *RouteService) CreateOrUpdate(ctx context.Context,
+	route *kong.Route,
+) (*kong.Route, error) {
+	if route == nil {
+		return nil, fmt.Errorf("cannot create or update a nil route")
+	}
+	route = route.DeepCopy()
+	if err := s.client.NewResolver().ResolveRoute(ctx, route); err != nil {
+		return nil, err
+	}
+	return createOrUpdate(ctx, s.client, "/routes", upsertKey(route.ID, route.Name), route)
+}
+
+// ConsumerService complements go-kong's handling of Consumers.
+type ConsumerService service
+
+// CreateOrUpdate creates or replaces a Consumer identified by its ID
+// or, if not set, its username.
+func (s *ConsumerService) CreateOrUpdate(ctx context.Context,
+	consumer *kong.Consumer,
+) (*kong.Consumer, error) {
+	if consumer == nil {
+		return nil, fmt.Errorf("cannot create or update a nil consumer")
+	}
+	return createOrUpdate(ctx, s.client, "/consumers",
+		upsertKey(consumer.ID, consumer.Username), consumer)
+}
+
+// ConsumerGroupService complements go-kong's handling of ConsumerGroups.
+type ConsumerGroupService service
+
+// CreateOrUpdate creates or replaces a ConsumerGroup identified by its ID
+// or, if not set, its name.
+func (s *ConsumerGroupService) CreateOrUpdate(ctx context.Context,
+	group *kong.ConsumerGroup,
+) (*kong.ConsumerGroup, error) {
+	if group == nil {
+		return nil, fmt.Errorf("cannot create or update a nil consumer group")
+	}
+	return createOrUpdate(ctx, s.client, "/consumer_groups",
+		upsertKey(group.ID, group.Name), group)
+}
+
+// CreateOrUpdatePlugin creates or replaces a Plugin of a ConsumerGroup,
+// identified by its ID.
+// A reference to the ConsumerGroup by name is sent as a reference by ID.
+func (s *ConsumerGroupService) CreateOrUpdatePlugin(ctx context.Context,
+	plugin *kong.ConsumerGroupPlugin,
+) (*kong.ConsumerGroupPlugin, error) {
+	if plugin == nil {
+		return nil, fmt.Errorf("cannot create or update a nil consumer group plugin")
+	}
+	plugin = plugin.DeepCopy()
+	if err := s.client.NewResolver().ResolveConsumerGroupPlugin(ctx, plugin); err != nil {
+		return nil, err
+	}
+	if plugin.ConsumerGroup == nil || plugin.ConsumerGroup.ID == nil {
+		return nil, fmt.Errorf("consumer group plugin %s has no consumer group",
+			upsertKey(plugin.ID, plugin.Name))
+	}
+	collection := NewEntityService[kong.ConsumerGroupPlugin](s.client, "/consumer_groups/{group}/plugins").
+		With(*plugin.ConsumerGroup.ID).Path()
+	return createOrUpdate(ctx, s.client, collection, upsertKey(plugin.ID, nil), plugin)
+}
+
+// PluginService complements go-kong's handling of Plugins.
+type PluginService service
+
+// CreateOrUpdate creates or replaces a Plugin identified by its ID.
+// References to its Service, Route and Consumer by name are sent as
+// references by ID.
+func (s *PluginService) CreateOrUpdate(ctx context.Context,
+	plugin *kong.Plugin,
+) (*kong.Plugin, error) {
+	if plugin == nil {
+		return nil, fmt.Errorf("cannot create or update a nil plugin")
+	}
+	plugin = plugin.DeepCopy()
+	if err := s.client.NewResolver().ResolvePlugin(ctx, plugin); err != nil {
+		return nil, err
+	}
+	return createOrUpdate(ctx, s.client, "/plugins", upsertKey(plugin.ID, nil), plugin)
+}
+
+// CertificateService complements go-kong's handling of Certificates.
+type CertificateService service
+
+// CreateOrUpdate creates or replaces a Certificate identified by its ID.
+func (s *CertificateService) CreateOrUpdate(ctx context.Context,
+	certificate *kong.Certificate,
+) (*kong.Certificate, error) {
+	if certificate == nil {
+		return nil, fmt.Errorf("cannot create or update a nil certificate")
+	}
+	return createOrUpdate(ctx, s.client, "/certificates",
+		upsertKey(certificate.ID, nil), certificate)
+}
+
+// CACertificateService complements go-kong's handling of CACertificates.
+type CACertificateService service
+
+// CreateOrUpdate creates or replaces a CACertificate identified by its ID.
+func (s *CACertificateService) CreateOrUpdate(ctx context.Context,
+	certificate *kong.CACertificate,
+) (*kong.CACertificate, error) {
+	if certificate == nil {
+		return nil, fmt.Errorf("cannot create or update a nil CA certificate")
+	}
+	return createOrUpdate(ctx, s.client, "/ca_certificates",
+		upsertKey(certificate.ID, nil), certificate)
+}
+
+// SNIService complements go-kong's handling of SNIs.
+type SNIService service
+
+// CreateOrUpdate creates or replaces an SNI identified by its ID
+// or, if not set, its name.
+func (s *SNIService) CreateOrUpdate(ctx context.Context,
+	sni *kong.SNI,
+) (*kong.SNI, error) {
+	if sni == nil {
+		return nil, fmt.Errorf("cannot create or update a nil SNI")
+	}
+	return createOrUpdate(ctx, s.client, "/snis", upsertKey(sni.ID, sni.Name), sni)
+}
+
+// VaultService complements go-kong's handling of Vaults.
+type VaultService service
+
+// CreateOrUpdate creates or replaces a Vault identified by its ID
+// or, if not set, its prefix.
+func (s *VaultService) CreateOrUpdate(ctx context.Context,
+	vault *kong.Vault,
+) (*kong.Vault, error) {
+	if vault == nil {
+		return nil, fmt.Errorf("cannot create or update a nil vault")
+	}
+	return createOrUpdate(ctx, s.client, "/vaults", upsertKey(vault.ID, vault.Prefix), vault)
+}
+
+// CreateOrUpdate creates or replaces an Upstream identified by its ID
+// or, if not set, its name.
+func (s *UpstreamService) CreateOrUpdate(ctx context.Context,
+	upstream *kong.Upstream,
+) (*kong.Upstream, error) {
+	if upstream == nil {
+		return nil, fmt.Errorf("cannot create or update a nil upstream")
+	}
+	return createOrUpdate(ctx, s.client, "/upstreams",
+		upsertKey(upstream.ID, upstream.Name), upstream)
+}
+
+// CreateOrUpdate creates or replaces a Target of an Upstream.
+// The Target is identified by its ID or, if not set, its target address.
+// A reference to its Upstream by name is sent as a reference by ID.
+func (s *TargetService) CreateOrUpdate(ctx context.Context,
+	upstreamNameOrID string, target *kong.Target,
+) (*kong.Target, error) {
+	if upstreamNameOrID == "" {
+		return nil, fmt.Errorf("upstreamNameOrID cannot be empty")
+	}
+	if target == nil {
+		return nil, fmt.Errorf("cannot create or update a nil target")
+	}
+	target = target.DeepCopy()
+	if err := s.client.NewResolver().ResolveTarget(ctx, target); err != nil {
+		return nil, err
+	}
+	collection := NewEntityService[kong.Target](s.client, "/upstreams/{upstream}/targets").
+		With(upstreamNameOrID).Path()
+	return createOrUpdate(ctx, s.client, collection, upsertKey(target.ID, target.Target), target)
+}
+
+// CreateOrUpdate creates or replaces a FilterChain identified by its ID
+// or, if not set, its name.
+func (s *FilterChainService) CreateOrUpdate(ctx context.Context,
+	filterChain *FilterChain,
+) (*FilterChain, error) {
+	if filterChain == nil {
+		return nil, fmt.Errorf("cannot create or update a nil filter chain")
+	}
+	return createOrUpdate(ctx, s.client, "/filter-chains",
+		upsertKey(filterChain.ID, filterChain.Name), filterChain)
+}
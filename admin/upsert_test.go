@@ -0,0 +1,152 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateOrUpdate(t *testing.T) {
+	var requests []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	ctx := context.Background()
+
+	svc, err := client.Services.CreateOrUpdate(ctx, &kong.Service{
+		Name: kong.String("svc1"),
+		Host: kong.String("example.com"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", *svc.Host)
+
+	_, err = client.Routes.CreateOrUpdate(ctx, &kong.Route{
+		ID:   kong.String("r-id"),
+		Name: kong.String("r1"),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Consumers.CreateOrUpdate(ctx, &kong.Consumer{Username: kong.String("alice")})
+	require.NoError(t, err)
+
+	_, err = client.Targets.CreateOrUpdate(ctx, "up1", &kong.Target{Target: kong.String("10.0.0.1:80")})
+	require.NoError(t, err)
+
+	_, err = client.Plugins.CreateOrUpdate(ctx, &kong.Plugin{Name: kong.String("cors")})
+	assert.EqualError(t, err, "an ID or a name is required to create or update an entity in /plugins")
+
+	_, err = client.Vaults.CreateOrUpdate(ctx, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"PUT /services/svc1",
+		"PUT /routes/r-id",
+		"PUT /consumers/alice",
+		"PUT /upstreams/up1/targets/10.0.0.1:80",
+	}, requests)
+}
+
+func TestCreateOrUpdateSendsEntity(t *testing.T) {
+	var received kong.Upstream
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		_ = json.NewEncoder(w).Encode(received)
+	}))
+
+	_, err := client.Upstreams.CreateOrUpdate(context.Background(), &kong.Upstream{
+		Name:  kong.String("up1"),
+		Slots: kong.Int(100),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "up1", *received.Name)
+	assert.Equal(t, 100, *received.Slots)
+}
+
+func TestCreateOrUpdateResolvesReferences(t *testing.T) {
+	const (
+		upstreamID = "5f1c2a3e-1111-4e5b-9c1d-000000000004"
+		groupID    = "5f1c2a3e-1111-4e5b-9c1d-000000000005"
+	)
+	var requests []string
+	bodies := map[string]string{}
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method + " " + r.URL.Path {
+		case "GET /services/svc":
+			_, _ = w.Write([]byte(`{"id":"` + svcID + `","name":"svc"}`))
+		case "GET /upstreams/up1":
+			_, _ = w.Write([]byte(`{"id":"` + upstreamID + `","name":"up1"}`))
+		case "GET /consumer_groups/gold":
+			_, _ = w.Write([]byte(`{"id":"` + groupID + `","name":"gold"}`))
+		case "GET /consumers/alice":
+			_, _ = w.Write([]byte(`{"id":"` + aliceID + `","username":"alice"}`))
+		case "GET /consumers":
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		default:
+			if r.Method != http.MethodPut {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = w.Write([]byte(`{"message":"Not found"}`))
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			bodies[r.URL.Path] = string(body)
+			_, _ = w.Write(body)
+		}
+	}))
+	ctx := context.Background()
+
+	route := &kong.Route{Name: kong.String("r1"), Service: &kong.Service{Name: kong.String("svc")}}
+	_, err := client.Routes.CreateOrUpdate(ctx, route)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"r1","service":{"id":"`+svcID+`"}}`, bodies["/routes/r1"])
+	// the entity of the caller is left as is
+	assert.Equal(t, "svc", *route.Service.Name)
+
+	_, err = client.Plugins.CreateOrUpdate(ctx, &kong.Plugin{
+		ID:       kong.String("p1"),
+		Name:     kong.String("key-auth"),
+		Service:  &kong.Service{ID: kong.String("svc")},
+		Consumer: &kong.Consumer{Username: kong.String("alice")},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"p1","name":"key-auth","service":{"id":"`+svcID+`"},`+
+		`"consumer":{"id":"`+aliceID+`"}}`, bodies["/plugins/p1"])
+
+	_, err = client.Targets.CreateOrUpdate(ctx, "up1", &kong.Target{
+		Target:   kong.String("10.0.0.1:80"),
+		Upstream: &kong.Upstream{Name: kong.String("up1")},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"target":"10.0.0.1:80","upstream":{"id":"`+upstreamID+`"}}`,
+		bodies["/upstreams/up1/targets/10.0.0.1:80"])
+
+	_, err = client.ConsumerGroups.CreateOrUpdatePlugin(ctx, &kong.ConsumerGroupPlugin{
+		ID:            kong.String("p2"),
+		Name:          kong.String("rate-limiting-advanced"),
+		ConsumerGroup: &kong.ConsumerGroup{Name: kong.String("gold")},
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"p2","name":"rate-limiting-advanced","consumer_group":{"id":"`+groupID+`"}}`,
+		bodies["/consumer_groups/"+groupID+"/plugins/p2"])
+
+	_, err = client.ConsumerGroups.CreateOrUpdatePlugin(ctx, &kong.ConsumerGroupPlugin{
+		ID:   kong.String("p3"),
+		Name: kong.String("rate-limiting-advanced"),
+	})
+	assert.EqualError(t, err, "consumer group plugin p3 has no consumer group")
+
+	_, err = client.Routes.CreateOrUpdate(ctx, &kong.Route{
+		Name:    kong.String("r2"),
+		Service: &kong.Service{Name: kong.String("missing")},
+	})
+	assert.EqualError(t, err, "route r2: service 'missing' not found")
+	assert.NotContains(t, requests, "PUT /routes/r2")
+}
@@ -0,0 +1,56 @@
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kong/go-kong/kong"
+)
+
+// UpstreamService handles health information of Upstreams in Kong.
+type UpstreamService service
+
+// BalancerHealth represents the health of an Upstream's balancer.
+type BalancerHealth struct {
+	ID   *string `json:"id,omitempty"`
+	Data *struct {
+		Health  *string                `json:"health,omitempty"`
+		Details map[string]interface{} `json:"details,omitempty"`
+	} `json:"data,omitempty"`
+}
+
+// Health fetches the health of all targets of an Upstream.
+func (s *UpstreamService) Health(ctx context.Context,
+	upstreamNameOrID string,
+) ([]*kong.UpstreamNodeHealth, error) {
+	if upstreamNameOrID == "" {
+		return nil, fmt.Errorf("upstreamNameOrID cannot be empty for Health operation")
+	}
+	return NewEntityService[kong.UpstreamNodeHealth](s.client, "/upstreams/{upstream}/health").
+		With(upstreamNameOrID).ListAll(ctx)
+}
+
+// BalancerHealth fetches the overall health of an Upstream's balancer.
+func (s *UpstreamService) BalancerHealth(ctx context.Context,
+	upstreamNameOrID string,
+) (*BalancerHealth, error) {
+	if upstreamNameOrID == "" {
+		return nil, fmt.Errorf("upstreamNameOrID cannot be empty for BalancerHealth operation")
+	}
+	type balancerHealthParams struct {
+		BalancerHealth int `url:"balancer_health"`
+	}
+	endpoint := fmt.Sprintf("/upstreams/%v/health", upstreamNameOrID)
+	req, err := s.client.NewRequest(http.MethodGet, endpoint,
+		balancerHealthParams{BalancerHealth: 1}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var health BalancerHealth
+	_, err = s.client.Do(ctx, req, &health)
+	if err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
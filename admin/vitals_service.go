@@ -0,0 +1,197 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// VitalsInterval is the duration of the time buckets of Vitals stats.
+type VitalsInterval string
+
+// Intervals supported by Vitals.
+const (
+	VitalsSeconds VitalsInterval = "seconds"
+	VitalsMinutes VitalsInterval = "minutes"
+	VitalsHours   VitalsInterval = "hours"
+	VitalsDays    VitalsInterval = "days"
+	VitalsWeeks   VitalsInterval = "weeks"
+)
+
+// VitalsOpt selects the time buckets returned by Vitals.
+type VitalsOpt struct {
+	// Interval defaults to minutes.
+	Interval VitalsInterval
+	// Start is the time of the first bucket to return.
+	// The retention of the interval applies if it is zero.
+	Start time.Time
+}
+
+// VitalsMeta describes a series of Vitals stats.
+type VitalsMeta struct {
+	EntityType string         `json:"entity_type,omitempty"`
+	EntityID   string         `json:"entity_id,omitempty"`
+	Level      string         `json:"level,omitempty"`
+	Interval   VitalsInterval `json:"interval,omitempty"`
+	EarliestTS int64          `json:"earliest_ts,omitempty"`
+	LatestTS   int64          `json:"latest_ts,omitempty"`
+	StatLabels []string       `json:"stat_labels,omitempty"`
+}
+
+// VitalsBucket holds the values of stats over a time bucket, keyed by
+// stat label or status code (class).
+type VitalsBucket struct {
+	Time   time.Time
+	Values map[string]float64
+}
+
+// VitalsSeries is a time series of Vitals stats.
+type VitalsSeries struct {
+	Meta VitalsMeta
+	// Buckets are keyed by level, which is "cluster" for cluster-wide
+	// stats or the ID of a node, and sorted by time.
+	Buckets map[string][]VitalsBucket
+}
+
+// VitalsLevelCluster is the level of cluster-wide stats.
+const VitalsLevelCluster = "cluster"
+
+// VitalsService handles the traffic analytics collected by
+// Vitals (Kong Enterprise only).
+type VitalsService service
+
+// Cluster fetches the health and traffic stats of the whole cluster.
+func (s *VitalsService) Cluster(ctx context.Context, opt *VitalsOpt) (*VitalsSeries, error) {
+	return s.series(ctx, "/vitals/cluster", nil, opt)
+}
+
+// Nodes fetches the health and traffic stats of each node.
+func (s *VitalsService) Nodes(ctx context.Context, opt *VitalsOpt) (*VitalsSeries, error) {
+	return s.series(ctx, "/vitals/nodes", nil, opt)
+}
+
+// StatusCodeClasses fetches the number of responses per status code
+// class, like 2xx or 5xx, of the whole cluster.
+func (s *VitalsService) StatusCodeClasses(ctx context.Context, opt *VitalsOpt) (*VitalsSeries, error) {
+	return s.series(ctx, "/vitals/status_code_classes", nil, opt)
+}
+
+// StatusCodesByService fetches the number of responses per status code
+// of a Service.
+func (s *VitalsService) StatusCodesByService(ctx context.Context,
+	serviceID string, opt *VitalsOpt,
+) (*VitalsSeries, error) {
+	if serviceID == "" {
+		return nil, fmt.Errorf("serviceID cannot be empty")
+	}
+	return s.series(ctx, "/vitals/status_codes/by_service",
+		url.Values{"service_id": {serviceID}}, opt)
+}
+
+// StatusCodesByRoute fetches the number of responses per status code
+// of a Route.
+func (s *VitalsService) StatusCodesByRoute(ctx context.Context,
+	routeID string, opt *VitalsOpt,
+) (*VitalsSeries, error) {
+	if routeID == "" {
+		return nil, fmt.Errorf("routeID cannot be empty")
+	}
+	return s.series(ctx, "/vitals/status_codes/by_route",
+		url.Values{"route_id": {routeID}}, opt)
+}
+
+// StatusCodesByConsumer fetches the number of responses per status code
+// of a Consumer.
+func (s *VitalsService) StatusCodesByConsumer(ctx context.Context,
+	consumerID string, opt *VitalsOpt,
+) (*VitalsSeries, error) {
+	if consumerID == "" {
+		return nil, fmt.Errorf("consumerID cannot be empty")
+	}
+	return s.series(ctx, "/vitals/status_codes/by_consumer",
+		url.Values{"consumer_id": {consumerID}}, opt)
+}
+
+// ConsumerRequests fetches the number of requests of a Consumer.
+func (s *VitalsService) ConsumerRequests(ctx context.Context,
+	consumerID string, opt *VitalsOpt,
+) (*VitalsSeries, error) {
+	if consumerID == "" {
+		return nil, fmt.Errorf("consumerID cannot be empty")
+	}
+	return s.series(ctx, "/vitals/consumers/"+url.PathEscape(consumerID)+"/cluster", nil, opt)
+}
+
+func (s *VitalsService) series(ctx context.Context, endpoint string,
+	qs url.Values, opt *VitalsOpt,
+) (*VitalsSeries, error) {
+	if qs == nil {
+		qs = url.Values{}
+	}
+	if opt != nil {
+		if opt.Interval != "" {
+			qs.Set("interval", string(opt.Interval))
+		}
+		if !opt.Start.IsZero() {
+			qs.Set("start_ts", strconv.FormatInt(opt.Start.Unix(), 10))
+		}
+	}
+	if encoded := qs.Encode(); encoded != "" {
+		endpoint += "?" + encoded
+	}
+	var resp struct {
+		Meta  VitalsMeta                            `json:"meta"`
+		Stats map[string]map[string]json.RawMessage `json:"stats"`
+	}
+	if err := s.client.Send(ctx, http.MethodGet, endpoint, nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	series := &VitalsSeries{Meta: resp.Meta, Buckets: map[string][]VitalsBucket{}}
+	for level, buckets := range resp.Stats {
+		for ts, raw := range buckets {
+			bucket, err := parseVitalsBucket(ts, raw, resp.Meta.StatLabels)
+			if err != nil {
+				return nil, fmt.Errorf("parsing vitals stats: %w", err)
+			}
+			series.Buckets[level] = append(series.Buckets[level], bucket)
+		}
+		sort.Slice(series.Buckets[level], func(i, j int) bool {
+			return series.Buckets[level][i].Time.Before(series.Buckets[level][j].Time)
+		})
+	}
+	return series, nil
+}
+
+// parseVitalsBucket parses the stats of a time bucket, which are either
+// an array of values in the order of labels, or an object of values.
+func parseVitalsBucket(ts string, raw json.RawMessage, labels []string) (VitalsBucket, error) {
+	seconds, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return VitalsBucket{}, fmt.Errorf("invalid timestamp '%s'", ts)
+	}
+	bucket := VitalsBucket{Time: time.Unix(seconds, 0).UTC(), Values: map[string]float64{}}
+	var values []*float64
+	if err := json.Unmarshal(raw, &values); err == nil {
+		for i, v := range values {
+			if v != nil && i < len(labels) {
+				bucket.Values[labels[i]] = *v
+			}
+		}
+		return bucket, nil
+	}
+	var object map[string]*float64
+	if err := json.Unmarshal(raw, &object); err != nil {
+		return VitalsBucket{}, fmt.Errorf("stats at %s: %w", ts, err)
+	}
+	for k, v := range object {
+		if v != nil {
+			bucket.Values[k] = *v
+		}
+	}
+	return bucket, nil
+}
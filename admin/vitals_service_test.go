@@ -0,0 +1,67 @@
+package admin
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVitalsService(t *testing.T) {
+	var queries []string
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/vitals/cluster":
+			_, _ = w.Write([]byte(`{
+  "meta": {"level": "cluster", "interval": "minutes", "stat_labels": ["requests_proxy_total", "cache_datastore_hits_total"]},
+  "stats": {"cluster": {
+    "1700000060": [12, null],
+    "1700000000": [10, 3]
+  }}
+}`))
+		case "/vitals/status_codes/by_service":
+			_, _ = w.Write([]byte(`{
+  "meta": {"entity_type": "service", "entity_id": "svc1", "level": "cluster", "interval": "seconds"},
+  "stats": {"cluster": {"1700000000": {"200": 4, "404": 1}}}
+}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	ctx := context.Background()
+
+	series, err := client.Vitals.Cluster(ctx, &VitalsOpt{
+		Interval: VitalsMinutes,
+		Start:    time.Unix(1700000000, 0),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, VitalsMinutes, series.Meta.Interval)
+	buckets := series.Buckets[VitalsLevelCluster]
+	require.Len(t, buckets, 2)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), buckets[0].Time)
+	assert.Equal(t, map[string]float64{
+		"requests_proxy_total": 10, "cache_datastore_hits_total": 3,
+	}, buckets[0].Values)
+	assert.Equal(t, map[string]float64{"requests_proxy_total": 12}, buckets[1].Values)
+
+	series, err = client.Vitals.StatusCodesByService(ctx, "svc1", &VitalsOpt{Interval: VitalsSeconds})
+	require.NoError(t, err)
+	assert.Equal(t, "svc1", series.Meta.EntityID)
+	assert.Equal(t, map[string]float64{"200": 4, "404": 1},
+		series.Buckets[VitalsLevelCluster][0].Values)
+
+	_, err = client.Vitals.StatusCodesByRoute(ctx, "", nil)
+	assert.Error(t, err)
+	_, err = client.Vitals.Nodes(ctx, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{
+		"/vitals/cluster?interval=minutes&start_ts=1700000000",
+		"/vitals/status_codes/by_service?interval=seconds&service_id=svc1",
+		"/vitals/nodes?",
+	}, queries)
+}
@@ -3,15 +3,14 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"net/http"
 	"os"
-	"reflect"
 	"sort"
 
 	"github.com/blang/semver/v4"
 	"github.com/kong/deck/cprint"
 	"github.com/kong/deck/diff"
 	"github.com/kong/deck/dump"
+	"github.com/kong/deck/engine"
 	"github.com/kong/deck/file"
 	"github.com/kong/deck/state"
 	"github.com/kong/deck/utils"
@@ -21,14 +20,15 @@ import (
 
 const (
 	exitCodeDiffDetection = 2
-	defaultFormatVersion  = "1.1"
-	formatVersion30       = "3.0"
 )
 
 var (
-	dumpConfig   dump.Config
-	assumeYes    bool
-	noMaskValues bool
+	dumpConfig        dump.Config
+	assumeYes         bool
+	noMaskValues      bool
+	noMask            bool
+	unsupportedFields string
+	lastAppliedFile   string
 )
 
 type mode int
@@ -83,11 +83,77 @@ func getWorkspaceName(workspaceFlag string, targetContent *file.Content) string
 func syncMain(ctx context.Context, filenames []string, dry bool, parallelism,
 	delay int, workspace string,
 ) error {
-	// read target file
-	targetContent, err := file.GetContentFromFiles(filenames)
+	totalOps, err := syncFiles(ctx, filenames, dry, parallelism, delay, workspace)
 	if err != nil {
 		return err
 	}
+	if dry && diffCmdNonZeroExitCode && totalOps > 0 {
+		os.Exit(exitCodeDiffDetection)
+	}
+	return nil
+}
+
+// syncAllWorkspaces syncs each workspace with the state files holding
+// its _workspace field. Files without _workspace are synced to the
+// default workspace. Missing workspaces are created.
+func syncAllWorkspaces(ctx context.Context, filenames []string, dry bool,
+	parallelism, delay int,
+) error {
+	if inKonnectMode(nil) {
+		return validateKonnectWorkspaceFlags("", true)
+	}
+	filesByWorkspace, err := file.GroupFilesByWorkspace(filenames)
+	if err != nil {
+		return err
+	}
+	workspaces := make([]string, 0, len(filesByWorkspace))
+	for workspace := range filesByWorkspace {
+		workspaces = append(workspaces, workspace)
+	}
+	sort.Strings(workspaces)
+
+	// select tags are read from each set of state files
+	selectorTags := dumpConfig.SelectorTags
+	totalOps := 0
+	for _, workspace := range workspaces {
+		dumpConfig.SelectorTags = selectorTags
+		name := workspace
+		if name == "" {
+			name = "default"
+		}
+		cprint.UpdatePrintf("Workspace %s:\n", name)
+		ops, err := syncFiles(ctx, filesByWorkspace[workspace], dry, parallelism, delay, workspace)
+		if err != nil {
+			return fmt.Errorf("workspace %s: %w", name, err)
+		}
+		totalOps += ops
+	}
+	if dry && diffCmdNonZeroExitCode && totalOps > 0 {
+		os.Exit(exitCodeDiffDetection)
+	}
+	return nil
+}
+
+// syncFiles syncs Kong with filenames, or diffs them if dry is set,
+// and returns the number of operations.
+func syncFiles(ctx context.Context, filenames []string, dry bool, parallelism,
+	delay int, workspace string,
+) (int, error) {
+	unsupportedFieldsMode, err := utils.ParseUnsupportedFieldsMode(unsupportedFields)
+	if err != nil {
+		return 0, err
+	}
+
+	// read target file
+	var targetContent *file.Content
+	if syncCmdPlan != nil {
+		targetContent = syncCmdPlan.Target
+	} else {
+		targetContent, err = file.GetContentFromFiles(filenames)
+	}
+	if err != nil {
+		return 0, err
+	}
 	if dumpConfig.SkipConsumers {
 		targetContent.Consumers = []file.FConsumer{}
 		targetContent.ConsumerGroups = []file.FConsumerGroupObject{}
@@ -105,18 +171,18 @@ func syncMain(ctx context.Context, filenames []string, dry bool, parallelism,
 	mode := getMode(targetContent)
 	if mode == modeKonnect {
 		if targetContent.Workspace != "" {
-			return fmt.Errorf("_workspace set in config file.\n"+
+			return 0, fmt.Errorf("_workspace set in config file.\n"+
 				"Workspaces are not supported in Konnect. "+
 				"Please remove '_workspace: %s' from your "+
 				"configuration and try again", targetContent.Workspace)
 		}
-		if workspace != "" {
-			return fmt.Errorf("--workspace flag is not supported when running against Konnect")
+		if err := validateKonnectWorkspaceFlags(workspace, false); err != nil {
+			return 0, err
 		}
 		if targetContent.Konnect != nil {
 			if konnectRuntimeGroup != "" &&
 				targetContent.Konnect.RuntimeGroupName != konnectRuntimeGroup {
-				return fmt.Errorf("warning: runtime group '%v' specified via "+
+				return 0, fmt.Errorf("warning: runtime group '%v' specified via "+
 					"--konnect-runtime-group flag is "+
 					"different from '%v' found in state file(s)",
 					konnectRuntimeGroup, targetContent.Konnect.RuntimeGroupName)
@@ -125,14 +191,14 @@ func syncMain(ctx context.Context, filenames []string, dry bool, parallelism,
 		}
 		kongClient, err = GetKongClientForKonnectMode(ctx, &konnectConfig)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		dumpConfig.KonnectRuntimeGroup = konnectRuntimeGroup
 	}
 
 	rootClient, err := utils.GetKongClient(rootConfig)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// prepare to read the current state from Kong
@@ -146,197 +212,239 @@ func syncMain(ctx context.Context, filenames []string, dry bool, parallelism,
 	if mode == modeKonnect {
 		kongVersion, err = fetchKonnectKongVersion(ctx, kongClient)
 		if err != nil {
-			return fmt.Errorf("reading Konnect Kong version: %w", err)
+			return 0, fmt.Errorf("reading Konnect Kong version: %w", err)
 		}
 	} else {
 		kongVersion, err = fetchKongVersion(ctx, wsConfig)
 		if err != nil {
-			return fmt.Errorf("reading Kong version: %w", err)
+			return 0, fmt.Errorf("reading Kong version: %w", err)
 		}
 	}
 	parsedKongVersion, err = utils.ParseKongVersion(kongVersion)
 	if err != nil {
-		return fmt.Errorf("parsing Kong version: %w", err)
+		return 0, fmt.Errorf("parsing Kong version: %w", err)
 	}
 
-	if parsedKongVersion.GTE(utils.Kong300Version) &&
-		targetContent.FormatVersion != formatVersion30 {
-		formatVersion := targetContent.FormatVersion
-		if formatVersion == "" {
-			formatVersion = defaultFormatVersion
-		}
-		return fmt.Errorf(
-			"cannot apply '%s' config format version to Kong version 3.0 or above.\n"+
-				utils.UpgradeMessage, formatVersion)
+	if err := engine.CheckFormatVersion(targetContent, parsedKongVersion); err != nil {
+		return 0, err
 	}
 
 	// TODO: instead of guessing the cobra command here, move the sendAnalytics
 	// call to the RunE function. That is not trivial because it requires the
 	// workspace name and kong client to be present on that level.
-	_ = sendAnalytics(cmd, kongVersion, mode)
+	if !skipSyncAnalytics {
+		_ = sendAnalytics(cmd, kongVersion, mode)
+	}
 
 	workspaceExists, err := workspaceExists(ctx, rootConfig, workspaceName)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	if kongClient == nil {
 		kongClient, err = utils.GetKongClient(wsConfig)
 		if err != nil {
-			return err
+			return 0, err
 		}
 	}
 
 	dumpConfig.SelectorTags, err = determineSelectorTag(*targetContent, dumpConfig)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
 	// read the current state
-	var currentState *state.KongState
+	var currentState, snapshot *state.KongState
+	rawCurrentState := &utils.KongRawState{}
 	if workspaceExists {
-		currentState, err = fetchCurrentState(ctx, kongClient, dumpConfig)
+		rawCurrentState, err = dump.Get(ctx, kongClient, dumpConfig)
+		if err != nil {
+			return 0, err
+		}
+		currentState, err = state.Get(rawCurrentState)
 		if err != nil {
-			return err
+			return 0, err
+		}
+		// the snapshot is not updated while the changes are applied,
+		// unlike the current state
+		if !dry {
+			if snapshot, err = state.Get(rawCurrentState); err != nil {
+				return 0, err
+			}
 		}
 	} else {
 		// inject empty state
 		currentState, err = state.NewKongState()
 		if err != nil {
-			return err
+			return 0, err
+		}
+		if !dry {
+			if snapshot, err = state.NewKongState(); err != nil {
+				return 0, err
+			}
 		}
 
 		cprint.CreatePrintln("creating workspace", wsConfig.Workspace)
 		if !dry {
 			_, err = rootClient.Workspaces.Create(ctx, &kong.Workspace{Name: &wsConfig.Workspace})
 			if err != nil {
-				return err
+				return 0, err
 			}
 		}
 	}
 
 	// read the target state
+	var declaredFields file.DeclaredFields
+	if lastAppliedFile != "" {
+		declaredFields = file.DeclaredFields{}
+	}
 	rawState, err := file.Get(ctx, targetContent, file.RenderConfig{
-		CurrentState: currentState,
-		KongVersion:  parsedKongVersion,
+		CurrentState:   currentState,
+		KongVersion:    parsedKongVersion,
+		DeclaredFields: declaredFields,
 	}, dumpConfig, kongClient)
 	if err != nil {
-		return err
+		return 0, err
+	}
+	var lastApplied *file.LastApplied
+	if lastAppliedFile != "" {
+		if lastApplied, err = file.ReadLastApplied(lastAppliedFile); err != nil {
+			return 0, err
+		}
+		err = file.MergeLiveFields(rawState, rawCurrentState, declaredFields,
+			lastApplied.Fields(workspaceName))
+		if err != nil {
+			return 0, err
+		}
 	}
 	if err := checkForRBACResources(*rawState, dumpConfig.RBACResourcesOnly); err != nil {
-		return err
+		return 0, err
 	}
-	targetState, err := state.Get(rawState)
+	err = utils.ShapeForKongVersion(rawState, parsedKongVersion, unsupportedFieldsMode)
 	if err != nil {
-		return err
+		return 0, err
 	}
-
-	totalOps, err := performDiff(
-		ctx, currentState, targetState, dry, parallelism, delay, kongClient, mode == modeKonnect)
+	targetState, err := state.Get(rawState)
 	if err != nil {
-		return err
+		return 0, err
 	}
 
-	if diffCmdNonZeroExitCode && totalOps > 0 {
-		os.Exit(exitCodeDiffDetection)
+	if len(dumpConfig.SelectorTags) > 0 && workspaceExists {
+		if err := checkSelectTagsOwnership(ctx, kongClient, currentState, targetState,
+			dumpConfig.SelectorTags); err != nil {
+			return 0, err
+		}
 	}
-	return nil
-}
 
-func determineSelectorTag(targetContent file.Content, config dump.Config) ([]string, error) {
-	if targetContent.Info != nil {
-		if len(targetContent.Info.SelectorTags) > 0 {
-			utils.RemoveDuplicates(&targetContent.Info.SelectorTags)
-			if len(config.SelectorTags) > 0 {
-				sort.Strings(config.SelectorTags)
-				sort.Strings(targetContent.Info.SelectorTags)
-				if !reflect.DeepEqual(config.SelectorTags, targetContent.Info.SelectorTags) {
-					return nil, fmt.Errorf(`tags specified in the state file (%v) and via --select-tags flag (%v) are different.
-					decK expects tags to be specified in either via flag or via state file.
-					In case both are specified, they must match`, targetContent.Info.SelectorTags, config.SelectorTags)
-				}
-				// Both present and equal, return whichever
-				return targetContent.Info.SelectorTags, nil
-			}
-			// Only targetContent.Info.SelectorTags present
-			return targetContent.Info.SelectorTags, nil
+	var currentStateHash string
+	if syncCmdPlan != nil || (dry && diffCmdPlanFile != "") {
+		currentStateHash, err = hashRawState(rawCurrentState)
+		if err != nil {
+			return 0, fmt.Errorf("hashing current state: %w", err)
+		}
+	}
+	if syncCmdPlan != nil {
+		if err := checkPlanCurrentState(syncCmdPlan, currentStateHash); err != nil {
+			return 0, err
 		}
 	}
-	// Either targetContent.Info or targetContent.Info.SelectorTags is empty, return config tags
-	return config.SelectorTags, nil
-}
 
-func fetchCurrentState(ctx context.Context, client *kong.Client, dumpConfig dump.Config) (*state.KongState, error) {
-	rawState, err := dump.Get(ctx, client, dumpConfig)
-	if err != nil {
-		return nil, err
+	hooks := !dry && syncHooksEnabled()
+	if hooks {
+		planned, err := plannedChanges(ctx, rawCurrentState, targetState, kongClient, mode == modeKonnect)
+		if err != nil {
+			return 0, err
+		}
+		if err := runSyncHooks(ctx, newSyncHookPayload(hookEventPreSync, workspaceName, planned, nil)); err != nil {
+			return 0, err
+		}
 	}
 
-	currentState, err := state.Get(rawState)
+	totalOps, changes, err := performDiff(
+		ctx, currentState, targetState, dry, parallelism, delay, kongClient, mode == modeKonnect)
+	if err != nil && snapshot != nil {
+		err = handleSyncFailure(ctx, kongClient, snapshot, err, parallelism, mode == modeKonnect)
+	}
+	if hooks {
+		if hookErr := runSyncHooks(ctx, newSyncHookPayload(hookEventPostSync, workspaceName, changes, err)); hookErr != nil {
+			cprint.DeletePrintln("Warning:", hookErr)
+		}
+	}
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	return currentState, nil
+	if !dry && lastApplied != nil {
+		lastApplied.Set(workspaceName, declaredFields)
+		if err := file.WriteLastApplied(lastAppliedFile, lastApplied); err != nil {
+			return 0, err
+		}
+	}
+	if dry && diffCmdPlanFile != "" {
+		p := newPlan(workspaceName, dumpConfig.SelectorTags, currentStateHash, targetContent, changes)
+		if err := writePlan(diffCmdPlanFile, p); err != nil {
+			return 0, err
+		}
+	}
+	return totalOps, nil
 }
 
+func determineSelectorTag(targetContent file.Content, config dump.Config) ([]string, error) {
+	return engine.SelectorTags(&targetContent, config.SelectorTags)
+}
+
+func fetchCurrentState(ctx context.Context, client *kong.Client, dumpConfig dump.Config) (*state.KongState, error) {
+	_, currentState, err := engine.CurrentState(ctx, client, dumpConfig)
+	return currentState, err
+}
+
+// skipSyncAnalytics is set by commands running syncFiles repeatedly,
+// which report their own analytics instead of one event per run.
+var skipSyncAnalytics bool
+
+// diffChangesHook, if set, receives the changes of each diff instead
+// of the changes being printed.
+var diffChangesHook func([]diff.EntityChange)
+
 func performDiff(ctx context.Context, currentState, targetState *state.KongState,
 	dry bool, parallelism int, delay int, client *kong.Client, isKonnect bool,
-) (int, error) {
-	s, err := diff.NewSyncer(diff.SyncerOpts{
-		CurrentState:  currentState,
-		TargetState:   targetState,
-		KongClient:    client,
+) (int, []diff.EntityChange, error) {
+	jsonOutput := dry && diffCmdOutputFormat == outputFormatJSON
+	result, err := engine.Apply(ctx, client, currentState, targetState, dry, engine.Options{
+		Parallelism:   parallelism,
 		StageDelaySec: delay,
-		NoMaskValues:  noMaskValues,
+		NoMaskValues:  noMaskValues || noMask,
+		NoMaskSecrets: noMask,
 		IsKonnect:     isKonnect,
+		CreatePrintln: cprint.CreatePrintln,
+		UpdatePrintln: cprint.UpdatePrintln,
+		DeletePrintln: cprint.DeletePrintln,
 	})
-	if err != nil {
-		return 0, err
+	if result == nil {
+		return 0, nil, err
 	}
-
-	stats, errs := s.Solve(ctx, parallelism, dry)
 	// print stats before error to report completed operations
-	printStats(stats)
-	if errs != nil {
-		return 0, utils.ErrArray{Errors: errs}
+	switch {
+	case diffChangesHook != nil:
+		diffChangesHook(result.Changes)
+	case jsonOutput:
+		if err := printJSONDiff(os.Stdout, result.Changes, result.Stats, engine.Errors(err)); err != nil {
+			return 0, nil, err
+		}
+	default:
+		printStats(result.Stats, result.Changes)
+	}
+	if err != nil {
+		return 0, result.Changes, err
 	}
-	totalOps := stats.CreateOps.Count() + stats.UpdateOps.Count() + stats.DeleteOps.Count()
-	return int(totalOps), nil
+	return result.Ops(), result.Changes, nil
 }
 
 func fetchKongVersion(ctx context.Context, config utils.KongClientConfig) (string, error) {
-	var version string
-
-	workspace := config.Workspace
-
-	// remove workspace to be able to call top-level / endpoint
-	config.Workspace = ""
 	client, err := utils.GetKongClient(config)
 	if err != nil {
 		return "", err
 	}
-	root, err := client.Root(ctx)
-	if err != nil {
-		if workspace == "" {
-			return "", err
-		}
-		// try with workspace path
-		req, err := http.NewRequest("GET",
-			utils.CleanAddress(config.Address)+"/"+workspace+"/kong",
-			nil)
-		if err != nil {
-			return "", err
-		}
-		var resp map[string]interface{}
-		_, err = client.Do(ctx, req, &resp)
-		if err != nil {
-			return "", err
-		}
-		version = resp["version"].(string)
-	} else {
-		version = root["version"].(string)
-	}
-	return version, nil
+	return engine.KongVersion(ctx, client)
 }
 
 func validateNoArgs(_ *cobra.Command, args []string) error {
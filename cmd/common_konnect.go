@@ -30,6 +30,18 @@ var addresses = []string{
 	defaultLegacyKonnectURL,
 }
 
+// validateKonnectWorkspaceFlags returns an error if a workspace is
+// requested when running against Konnect, which has no workspaces.
+func validateKonnectWorkspaceFlags(workspace string, allWorkspaces bool) error {
+	if workspace != "" {
+		return fmt.Errorf("--workspace flag is not supported when running against Konnect")
+	}
+	if allWorkspaces {
+		return fmt.Errorf("--all-workspaces flag is not supported when running against Konnect")
+	}
+	return nil
+}
+
 func authenticate(
 	ctx context.Context, client *konnect.Client, host string, konnectConfig utils.KonnectConfig,
 ) (konnect.AuthResponse, error) {
@@ -116,37 +128,16 @@ func GetKongClientForKonnectMode(
 
 	// initialize kong client
 	return utils.GetKongClient(utils.KongClientConfig{
-		Address:    konnectAddress,
-		HTTPClient: httpClient,
-		Debug:      konnectConfig.Debug,
-		Headers:    konnectConfig.Headers,
-		Retryable:  true,
+		Address:         konnectAddress,
+		HTTPClient:      httpClient,
+		DebugLevel:      konnectConfig.DebugLevel,
+		DebugSampleRate: konnectConfig.DebugSampleRate,
+		Headers:         konnectConfig.Headers,
+		Retryable:       true,
+		RateLimiter:     konnectConfig.RateLimiter,
 	})
 }
 
-func resetKonnectV2(ctx context.Context) error {
-	client, err := GetKongClientForKonnectMode(ctx, &konnectConfig)
-	if err != nil {
-		return err
-	}
-	if dumpConfig.KonnectRuntimeGroup == "" {
-		dumpConfig.KonnectRuntimeGroup = defaultRuntimeGroupName
-	}
-	currentState, err := fetchCurrentState(ctx, client, dumpConfig)
-	if err != nil {
-		return err
-	}
-	targetState, err := state.NewKongState()
-	if err != nil {
-		return err
-	}
-	_, err = performDiff(ctx, currentState, targetState, false, 10, 0, client, true)
-	if err != nil {
-		return err
-	}
-	return nil
-}
-
 func dumpKonnectV2(ctx context.Context) error {
 	client, err := GetKongClientForKonnectMode(ctx, &konnectConfig)
 	if err != nil {
@@ -178,7 +169,7 @@ func dumpKonnectV2(ctx context.Context) error {
 }
 
 func syncKonnect(ctx context.Context,
-	filenames []string, dry bool, parallelism int,
+	filenames []string, dry bool, parallelism int, nonZeroExitCode bool,
 ) error {
 	httpClient := utils.HTTPClient()
 
@@ -220,10 +211,12 @@ func syncKonnect(ctx context.Context,
 
 	// initialize kong client
 	kongClient, err := utils.GetKongClient(utils.KongClientConfig{
-		Address:    konnectConfig.Address + "/api/control_planes/" + kongCPID,
-		HTTPClient: httpClient,
-		Debug:      konnectConfig.Debug,
-		Headers:    konnectConfig.Headers,
+		Address:         konnectConfig.Address + "/api/control_planes/" + kongCPID,
+		HTTPClient:      httpClient,
+		DebugLevel:      konnectConfig.DebugLevel,
+		DebugSampleRate: konnectConfig.DebugSampleRate,
+		Headers:         konnectConfig.Headers,
+		RateLimiter:     konnectConfig.RateLimiter,
 	})
 	if err != nil {
 		return err
@@ -252,7 +245,9 @@ func syncKonnect(ctx context.Context,
 		TargetState:   targetState,
 		KongClient:    kongClient,
 		KonnectClient: konnectClient,
-		NoMaskValues:  noMaskValues,
+		NoMaskValues:  noMaskValues || noMask,
+		NoMaskSecrets: noMask,
+		RecordChanges: true,
 	})
 	if err != nil {
 		return err
@@ -260,11 +255,11 @@ func syncKonnect(ctx context.Context,
 
 	stats, errs := s.Solve(ctx, parallelism, dry)
 	// print stats before error to report completed operations
-	printStats(stats)
+	printStats(stats, s.Changes())
 	if errs != nil {
 		return utils.ErrArray{Errors: errs}
 	}
-	if diffCmdNonZeroExitCode &&
+	if dry && nonZeroExitCode &&
 		stats.CreateOps.Count()+stats.UpdateOps.Count()+stats.DeleteOps.Count() != 0 {
 		os.Exit(exitCodeDiffDetection)
 	}
@@ -286,28 +281,14 @@ func fetchKongControlPlaneID(ctx context.Context,
 func fetchKongRuntimeGroupID(ctx context.Context,
 	client *konnect.Client,
 ) (string, error) {
-	var runtimeGroups []*konnect.RuntimeGroup
-	var listOpt *konnect.ListOpt
-	for {
-		currentRuntimeGroups, next, err := client.RuntimeGroups.List(ctx, listOpt)
-		if err != nil {
-			return "", fmt.Errorf("fetching runtime groups: %w", err)
-		}
-		runtimeGroups = append(runtimeGroups, currentRuntimeGroups...)
-		if next == nil {
-			break
-		}
-		listOpt = next
-	}
 	if konnectRuntimeGroup == "" {
 		konnectRuntimeGroup = defaultRuntimeGroupName
 	}
-	for _, rg := range runtimeGroups {
-		if *rg.Name == konnectRuntimeGroup {
-			return *rg.ID, nil
-		}
+	rg, err := client.RuntimeGroups.GetByName(ctx, konnectRuntimeGroup)
+	if err != nil {
+		return "", fmt.Errorf("fetching runtime groups: %w", err)
 	}
-	return "", fmt.Errorf("runtime groups not found: %s", konnectRuntimeGroup)
+	return *rg.ID, nil
 }
 
 func singleOutKongCP(controlPlanes []konnect.ControlPlane) (string, error) {
@@ -5,6 +5,7 @@ import (
 
 	"github.com/kong/deck/konnect"
 	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_singleOutKongCP(t *testing.T) {
@@ -85,3 +86,11 @@ func Test_singleOutKongCP(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateKonnectWorkspaceFlags(t *testing.T) {
+	assert.NoError(t, validateKonnectWorkspaceFlags("", false))
+	assert.EqualError(t, validateKonnectWorkspaceFlags("team-a", false),
+		"--workspace flag is not supported when running against Konnect")
+	assert.EqualError(t, validateKonnectWorkspaceFlags("", true),
+		"--all-workspaces flag is not supported when running against Konnect")
+}
@@ -1,11 +1,19 @@
 package cmd
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 
 	"github.com/kong/deck/dump"
 	"github.com/kong/deck/file"
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDetermineSelectorTag(t *testing.T) {
@@ -96,3 +104,69 @@ func TestDetermineSelectorTag(t *testing.T) {
 		})
 	}
 }
+
+func TestSyncFilesLastApplied(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "kong.yaml")
+	writeState := func(service string) {
+		require.NoError(t, os.WriteFile(stateFile, []byte(`
+_format_version: "3.0"
+services:
+- name: svc1
+  host: example.com
+`+service), 0o600))
+	}
+	writeState("")
+
+	defer func(config utils.KongClientConfig, analytics bool) {
+		rootConfig, disableAnalytics = config, analytics
+		lastAppliedFile = ""
+	}(rootConfig, disableAnalytics)
+	rootConfig = utils.KongClientConfig{Address: server.URL}
+	disableAnalytics = true
+	lastAppliedFile = filepath.Join(dir, "last-applied.json")
+
+	ctx := context.Background()
+	_, err := syncFiles(ctx, []string{stateFile}, false, 1, 0, "")
+	require.NoError(t, err)
+
+	// a field never set in the state file is changed outside of decK
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	svc, err := client.Services.Get(ctx, kong.String("svc1"))
+	require.NoError(t, err)
+	svc.Retries = kong.Int(10)
+	_, err = client.Services.Update(ctx, svc)
+	require.NoError(t, err)
+
+	ops, err := syncFiles(ctx, []string{stateFile}, true, 1, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, ops)
+
+	lastAppliedFile = ""
+	ops, err = syncFiles(ctx, []string{stateFile}, true, 1, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ops, "without last-applied file, the default is enforced")
+	lastAppliedFile = filepath.Join(dir, "last-applied.json")
+
+	// the field is then managed by the state file
+	writeState("  retries: 7\n")
+	_, err = syncFiles(ctx, []string{stateFile}, false, 1, 0, "")
+	require.NoError(t, err)
+	svc, err = client.Services.Get(ctx, kong.String("svc1"))
+	require.NoError(t, err)
+	assert.Equal(t, 7, *svc.Retries)
+
+	// and removed from it: it is reset, as without last-applied file
+	writeState("")
+	ops, err = syncFiles(ctx, []string{stateFile}, false, 1, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, ops)
+	lastAppliedFile = ""
+	ops, err = syncFiles(ctx, []string{stateFile}, true, 1, 0, "")
+	require.NoError(t, err)
+	assert.Equal(t, 0, ops)
+}
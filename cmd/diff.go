@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/kong/deck/cprint"
 	"github.com/spf13/cobra"
 )
 
@@ -11,6 +12,8 @@ var (
 	diffCmdParallelism     int
 	diffCmdNonZeroExitCode bool
 	diffWorkspace          string
+	diffAllWorkspaces      bool
+	diffCmdOutputFormat    string
 )
 
 // newDiffCmd represents the diff command
@@ -23,9 +26,16 @@ func newDiffCmd() *cobra.Command {
 It loads entities from Kong and performs a diff with
 the entities in local files. This allows you to see the entities
 that will be created, updated, or deleted.
+
+The exit code is 0 if no diff is found, 2 if there is a diff present,
+and 1 if an error occurs.
 `,
 		Args: validateNoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if diffAllWorkspaces {
+				return syncAllWorkspaces(cmd.Context(), diffCmdKongStateFile, true,
+					diffCmdParallelism, 0)
+			}
 			return syncMain(cmd.Context(), diffCmdKongStateFile, true,
 				diffCmdParallelism, 0, diffWorkspace)
 		},
@@ -34,6 +44,19 @@ that will be created, updated, or deleted.
 				return fmt.Errorf("a state file with Kong's configuration " +
 					"must be specified using `-s`/`--state` flag")
 			}
+			if err := validateOutputFormat(diffCmdOutputFormat); err != nil {
+				return err
+			}
+			if diffCmdOutputFormat == outputFormatJSON && diffAllWorkspaces {
+				return fmt.Errorf("--format json cannot be used with --all-workspaces")
+			}
+			if diffCmdPlanFile != "" && diffAllWorkspaces {
+				return fmt.Errorf("--plan-out cannot be used with --all-workspaces")
+			}
+			if diffCmdOutputFormat == outputFormatJSON {
+				// events are part of the JSON document
+				cprint.DisableOutput = true
+			}
 			return preRunSilenceEventsFlag()
 		},
 	}
@@ -41,18 +64,34 @@ that will be created, updated, or deleted.
 	diffCmd.Flags().StringSliceVarP(&diffCmdKongStateFile,
 		"state", "s", []string{"kong.yaml"}, "file(s) containing Kong's configuration.\n"+
 			"This flag can be specified multiple times for multiple files.\n"+
+			"Directories are read recursively for YAML and JSON files.\n"+
 			"Use `-` to read from stdin.")
 	diffCmd.Flags().StringVarP(&diffWorkspace, "workspace", "w",
 		"", "Diff configuration with a specific workspace "+
 			"(Kong Enterprise only).\n"+
 			"This takes precedence over _workspace fields in state files.")
+	diffCmd.Flags().BoolVar(&diffAllWorkspaces, "all-workspaces", false,
+		"Diff each workspace with the state files holding its _workspace field "+
+			"(Kong Enterprise only).\n"+
+			"State files without _workspace are diffed with the default workspace.")
+	diffCmd.MarkFlagsMutuallyExclusive("workspace", "all-workspaces")
+	diffCmd.Flags().StringVar(&diffCmdPlanFile, "plan-out", "",
+		"save the diff as a plan to this file, to be applied with sync --plan.\n"+
+			"Plans hold the rendered state files, including the values of\n"+
+			"environment variables: store them accordingly.")
+	diffCmd.MarkFlagsMutuallyExclusive("plan-out", "all-workspaces")
 	diffCmd.Flags().BoolVar(&dumpConfig.SkipConsumers, "skip-consumers",
 		false, "do not diff consumers or "+
 			"any plugins associated with consumers")
 	diffCmd.Flags().IntVar(&diffCmdParallelism, "parallelism",
-		10, "Maximum number of concurrent operations.")
+		10, "Maximum number of concurrent operations.\n"+
+			"Entities depending on each other, like services and their routes,\n"+
+			"are still processed in order.")
 	diffCmd.Flags().BoolVar(&noMaskValues, "no-mask-deck-env-vars-value",
 		false, "do not mask DECK_ environment variable values at diff output.")
+	diffCmd.Flags().BoolVar(&noMask, "no-mask",
+		false, "do not mask secrets, such as credentials, private keys of certificates\n"+
+			"and encrypted plugin fields, nor DECK_ environment variable values at diff output.")
 	diffCmd.Flags().StringSliceVar(&dumpConfig.SelectorTags,
 		"select-tag", []string{},
 		"only entities matching tags specified via this flag are diffed.\n"+
@@ -60,11 +99,21 @@ that will be created, updated, or deleted.
 	diffCmd.Flags().BoolVar(&dumpConfig.RBACResourcesOnly, "rbac-resources-only",
 		false, "sync only the RBAC resources (Kong Enterprise only).")
 	diffCmd.Flags().BoolVar(&diffCmdNonZeroExitCode, "non-zero-exit-code",
-		false, "return exit code 2 if there is a diff present,\n"+
+		true, "return exit code 2 if there is a diff present,\n"+
 			"exit code 0 if no diff is found,\n"+
-			"and exit code 1 if an error occurs.")
+			"and exit code 1 if an error occurs.\n"+
+			"Set to false to return exit code 0 when there is a diff present.")
 	diffCmd.Flags().BoolVar(&dumpConfig.SkipCACerts, "skip-ca-certificates",
 		false, "do not diff CA certificates.")
+	diffCmd.Flags().StringVar(&unsupportedFields, "unsupported-fields",
+		"", "how to handle fields not supported by the Kong version in use:\n"+
+			"'strip' removes them, 'reject' fails with an error.\n"+
+			"By default, all fields are sent to Kong as is.")
+	diffCmd.Flags().StringVar(&diffCmdOutputFormat, "format", outputFormatText,
+		"output format of the diff, one of: text, json.\n"+
+			"The json format lists the entities to create, update and delete,\n"+
+			"with the old and new values of each changed field.")
+	addLastAppliedFileFlag(diffCmd.Flags())
 	addSilenceEventsFlag(diffCmd.Flags())
 	return diffCmd
 }
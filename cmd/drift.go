@@ -0,0 +1,241 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/kong/deck/cprint"
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	driftCmdKongStateFile []string
+	driftCmdParallelism   int
+	driftCmdInterval      time.Duration
+	driftCmdWebhookURL    string
+	driftCmdOutputFormat  string
+	driftWorkspace        string
+)
+
+// driftReport is the result of a drift check.
+type driftReport struct {
+	Time    time.Time           `json:"time"`
+	Drift   bool                `json:"drift"`
+	Changes []diff.EntityChange `json:"changes"`
+	Summary jsonDiffSummary     `json:"summary"`
+	Error   string              `json:"error,omitempty"`
+}
+
+func newDriftReport(now time.Time, changes []diff.EntityChange, err error) driftReport {
+	report := driftReport{
+		Time:    now.UTC(),
+		Drift:   len(changes) > 0,
+		Changes: changes,
+		Summary: summarizeChanges(changes),
+	}
+	if report.Changes == nil {
+		report.Changes = []diff.EntityChange{}
+	}
+	if err != nil {
+		report.Error = err.Error()
+	}
+	return report
+}
+
+// fingerprint identifies the outcome of a check regardless of its time,
+// so that the same drift is reported once while watching.
+func (r driftReport) fingerprint() string {
+	r.Time = time.Time{}
+	b, _ := json.Marshal(r)
+	return string(b)
+}
+
+// printDriftReport writes a drift report to w, as text or as a line of JSON.
+func printDriftReport(w io.Writer, r driftReport, format string) error {
+	if format == outputFormatJSON {
+		return json.NewEncoder(w).Encode(r)
+	}
+	timestamp := r.Time.Format(time.RFC3339)
+	var err error
+	switch {
+	case r.Error != "":
+		_, err = fmt.Fprintf(w, "%s drift check failed: %s\n", timestamp, r.Error)
+	case !r.Drift:
+		_, err = fmt.Fprintf(w, "%s no drift detected\n", timestamp)
+	default:
+		_, err = fmt.Fprintf(w, "%s drift detected: %d created, %d updated, %d deleted\n",
+			timestamp, r.Summary.Created, r.Summary.Updated, r.Summary.Deleted)
+		for _, c := range r.Changes {
+			if err != nil {
+				break
+			}
+			_, err = fmt.Fprintf(w, "  %s %s %s\n", c.Action, c.Kind, c.Name)
+		}
+	}
+	return err
+}
+
+// detectDrift diffs Kong with the state files and returns the changes
+// a sync would perform, i.e. the changes made to Kong out of band.
+func detectDrift(ctx context.Context, filenames []string, parallelism int,
+	workspace string,
+) ([]diff.EntityChange, error) {
+	// select tags are read again from the state files on every check
+	selectorTags := dumpConfig.SelectorTags
+	defer func() { dumpConfig.SelectorTags = selectorTags }()
+
+	var changes []diff.EntityChange
+	diffChangesHook = func(c []diff.EntityChange) { changes = c }
+	defer func() { diffChangesHook = nil }()
+	skipSyncAnalytics = true
+	defer func() { skipSyncAnalytics = false }()
+
+	if _, err := syncFiles(ctx, filenames, true, parallelism, 0, workspace); err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// driftWatcher reports drift checks, and notifies the webhook when the
+// outcome of a check differs from the previous one.
+type driftWatcher struct {
+	out        io.Writer
+	format     string
+	webhookURL string
+	client     *http.Client
+
+	lastFingerprint string
+}
+
+func (w *driftWatcher) report(ctx context.Context, r driftReport) error {
+	fingerprint := r.fingerprint()
+	first := w.lastFingerprint == ""
+	if fingerprint == w.lastFingerprint {
+		return nil
+	}
+	w.lastFingerprint = fingerprint
+	if err := printDriftReport(w.out, r, w.format); err != nil {
+		return err
+	}
+	// a clean first check is not worth a notification
+	if w.webhookURL == "" || (first && !r.Drift && r.Error == "") {
+		return nil
+	}
+	if err := postWebhook(ctx, w.client, w.webhookURL, r); err != nil {
+		cprint.DeletePrintln("Warning:", err)
+	}
+	return nil
+}
+
+// newDriftCmd represents the drift command
+func newDriftCmd() *cobra.Command {
+	driftCmd := &cobra.Command{
+		Use:   "drift",
+		Short: "Detect changes made to Kong outside of the state files",
+		Long: `The drift command diffs the entities in Kong with the ones in the
+state files, and reports the changes made to Kong out of band, for example
+from Kong Manager or the Admin API.
+
+Without --interval, a single check is performed and the command exits
+with code 2 if drift is detected.
+
+With --interval, Kong is checked periodically until the command is
+interrupted. A report is printed, and sent to the --webhook-url if set,
+whenever the drift changes, including when it is resolved.`,
+		Args: validateNoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			if len(driftCmdKongStateFile) == 0 {
+				return fmt.Errorf("a state file with Kong's configuration " +
+					"must be specified using `-s`/`--state` flag")
+			}
+			if driftCmdInterval < 0 {
+				return fmt.Errorf("interval cannot be negative")
+			}
+			if err := validateOutputFormat(driftCmdOutputFormat); err != nil {
+				return err
+			}
+			// the changes are part of the drift reports
+			cprint.DisableOutput = true
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			_ = sendAnalytics("drift", "", getMode(nil))
+			watcher := &driftWatcher{
+				out:        os.Stdout,
+				format:     driftCmdOutputFormat,
+				webhookURL: driftCmdWebhookURL,
+				client:     utils.HTTPClient(),
+			}
+
+			if driftCmdInterval == 0 {
+				changes, err := detectDrift(ctx, driftCmdKongStateFile, driftCmdParallelism, driftWorkspace)
+				if err != nil {
+					return err
+				}
+				if err := watcher.report(ctx, newDriftReport(time.Now(), changes, nil)); err != nil {
+					return err
+				}
+				if len(changes) > 0 {
+					os.Exit(exitCodeDiffDetection)
+				}
+				return nil
+			}
+
+			ticker := time.NewTicker(driftCmdInterval)
+			defer ticker.Stop()
+			for {
+				changes, err := detectDrift(ctx, driftCmdKongStateFile, driftCmdParallelism, driftWorkspace)
+				if ctx.Err() != nil {
+					return nil
+				}
+				if err := watcher.report(ctx, newDriftReport(time.Now(), changes, err)); err != nil {
+					return err
+				}
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-ticker.C:
+				}
+			}
+		},
+	}
+
+	driftCmd.Flags().StringSliceVarP(&driftCmdKongStateFile,
+		"state", "s", []string{"kong.yaml"}, "file(s) containing Kong's configuration.\n"+
+			"This flag can be specified multiple times for multiple files.\n"+
+			"Directories are read recursively for YAML and JSON files.")
+	driftCmd.Flags().StringVarP(&driftWorkspace, "workspace", "w",
+		"", "Check a specific workspace "+
+			"(Kong Enterprise only).\n"+
+			"This takes precedence over _workspace fields in state files.")
+	driftCmd.Flags().DurationVar(&driftCmdInterval, "interval", 0,
+		"check Kong periodically at this interval, e.g. 5m, until interrupted.\n"+
+			"A single check is performed if not set.")
+	driftCmd.Flags().StringVar(&driftCmdWebhookURL, "webhook-url", "",
+		"URL to POST drift reports to, as JSON.")
+	driftCmd.Flags().StringVar(&driftCmdOutputFormat, "format", outputFormatText,
+		"output format of the drift reports, one of: text, json.")
+	driftCmd.Flags().IntVar(&driftCmdParallelism, "parallelism",
+		10, "Maximum number of concurrent requests to Kong.")
+	driftCmd.Flags().BoolVar(&dumpConfig.SkipConsumers, "skip-consumers",
+		false, "do not check consumers, consumer-groups or "+
+			"any plugins associated with them.")
+	driftCmd.Flags().StringSliceVar(&dumpConfig.SelectorTags,
+		"select-tag", []string{},
+		"only entities matching tags specified via this flag are checked.\n"+
+			"When this setting has multiple tag values, entities must match every tag.")
+	driftCmd.Flags().BoolVar(&noMaskValues, "no-mask-deck-env-vars-value",
+		false, "do not mask DECK_ environment variable values in drift reports.")
+	driftCmd.Flags().BoolVar(&noMask, "no-mask",
+		false, "do not mask secrets, such as credentials, private keys of certificates\n"+
+			"and encrypted plugin fields, nor DECK_ environment variable values at diff output.")
+	return driftCmd
+}
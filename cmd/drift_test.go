@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kong/deck/diff"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintDriftReport(t *testing.T) {
+	now := time.Date(2023, 6, 1, 10, 0, 0, 0, time.UTC)
+	changes := []diff.EntityChange{
+		{Action: diff.ActionUpdate, Kind: "service", Name: "svc1"},
+		{Action: diff.ActionDelete, Kind: "route", Name: "r1"},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, printDriftReport(&buf, newDriftReport(now, changes, nil), outputFormatText))
+	assert.Equal(t, "2023-06-01T10:00:00Z drift detected: 0 created, 1 updated, 1 deleted\n"+
+		"  update service svc1\n"+
+		"  delete route r1\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, printDriftReport(&buf, newDriftReport(now, nil, nil), outputFormatText))
+	assert.Equal(t, "2023-06-01T10:00:00Z no drift detected\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, printDriftReport(&buf, newDriftReport(now, nil, nil), outputFormatJSON))
+	assert.JSONEq(t, `{
+		"time": "2023-06-01T10:00:00Z",
+		"drift": false,
+		"changes": [],
+		"summary": {"created": 0, "updated": 0, "deleted": 0}
+	}`, buf.String())
+}
+
+func TestDriftWatcherReportsChanges(t *testing.T) {
+	var received []driftReport
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var report driftReport
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		received = append(received, report)
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	watcher := &driftWatcher{
+		out:        &out,
+		format:     outputFormatText,
+		webhookURL: server.URL,
+		client:     server.Client(),
+	}
+	ctx := context.Background()
+	drift := []diff.EntityChange{{Action: diff.ActionCreate, Kind: "service", Name: "svc1"}}
+
+	for _, changes := range [][]diff.EntityChange{nil, nil, drift, drift, nil} {
+		require.NoError(t, watcher.report(ctx, newDriftReport(time.Now(), changes, nil)))
+	}
+
+	// the initial clean check is printed but not sent,
+	// repeated outcomes are neither printed nor sent
+	assert.Equal(t, 4, bytes.Count(out.Bytes(), []byte("\n")))
+	assert.Contains(t, out.String(), "  create service svc1\n")
+	require.Len(t, received, 2)
+	assert.True(t, received[0].Drift)
+	assert.Equal(t, int32(1), received[0].Summary.Created)
+	assert.False(t, received[1].Drift)
+}
@@ -55,6 +55,9 @@ configure Kong.`,
 			}
 
 			if inKonnectMode(nil) {
+				if err := validateKonnectWorkspaceFlags(dumpWorkspace, dumpAllWorkspaces); err != nil {
+					return err
+				}
 				_ = sendAnalytics("dump", "", modeKonnect)
 				return dumpKonnectV2(ctx)
 			}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// newFileCmd represents the file command, grouping the
+// commands which operate on state files without Kong.
+func newFileCmd() *cobra.Command {
+	fileCmd := &cobra.Command{
+		Use:   "file",
+		Short: "Subcommand to manipulate state files",
+		Long: `The file command groups the commands which transform
+state files, without connecting to Kong unless stated otherwise.`,
+		Args: validateNoArgs,
+	}
+	fileCmd.AddCommand(newFilePatchCmd())
+	fileCmd.AddCommand(newFileOpenapi2KongCmd())
+	fileCmd.AddCommand(newFileLintCmd())
+	fileCmd.AddCommand(newFileSchemaCmd())
+	fileCmd.AddCommand(newFileMergeCmd())
+	fileCmd.AddCommand(newFileSplitCmd())
+	return fileCmd
+}
+
+// readInputFile reads filename, or stdin if filename is `-`.
+func readInputFile(filename string) ([]byte, error) {
+	if filename == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading file: %w", err)
+	}
+	return content, nil
+}
+
+// writeOutputFile writes content to filename, or stdout if filename is `-`.
+func writeOutputFile(filename string, content []byte) error {
+	if filename == "-" {
+		_, err := os.Stdout.Write(content)
+		return err
+	}
+	if err := os.WriteFile(filename, content, 0o600); err != nil {
+		return fmt.Errorf("writing file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kong/deck/lint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fileLintCmdStateFiles     []string
+	fileLintCmdFormat         string
+	fileLintCmdFailSeverity   string
+	fileLintCmdNoBuiltinRules bool
+)
+
+// lintFinding is a lint finding in a state file.
+type lintFinding struct {
+	File string `json:"file"`
+	lint.Finding
+}
+
+type jsonLintOutput struct {
+	Findings []lintFinding  `json:"findings"`
+	Summary  map[string]int `json:"summary"`
+}
+
+// printLintFindings writes findings to w, as text or JSON.
+func printLintFindings(w io.Writer, findings []lintFinding, format string) error {
+	if format == outputFormatJSON {
+		output := jsonLintOutput{
+			Findings: findings,
+			Summary:  map[string]int{"error": 0, "warn": 0, "info": 0},
+		}
+		if output.Findings == nil {
+			output.Findings = []lintFinding{}
+		}
+		for _, f := range findings {
+			output.Summary[f.Severity.String()]++
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(output)
+	}
+	for _, f := range findings {
+		if _, err := fmt.Fprintf(w, "%s: %s\n", f.File, f.Finding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// newFileLintCmd represents the file lint command
+func newFileLintCmd() *cobra.Command {
+	fileLintCmd := &cobra.Command{
+		Use:   "lint [flags] [...ruleset-files]",
+		Short: "Check state files against lint rules",
+		Long: `The lint command checks state files against built-in rules,
+such as untagged entities, services without rate limiting or deprecated
+fields, and against the rules of the given ruleset files.
+
+A ruleset file looks like:
+
+  rules:
+    # change the severity of a built-in rule, or turn it off
+    service-rate-limiting:
+      severity: "off"
+    # add a rule
+    https-only:
+      description: services must use https
+      severity: error
+      given:
+      - $.services[*]
+      then:
+        field: protocol
+        function: enumeration
+        function_options:
+          values: [https]
+
+Rules select nodes with the selectors of the patch command, then check
+a field of these nodes with one of the functions: truthy, falsy, defined,
+undefined, pattern, not-pattern (with the match option) and enumeration
+(with the values option).
+
+The command fails if any finding is at least as severe as --fail-severity.`,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFormat(fileLintCmdFormat)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			failSeverity, err := lint.ParseSeverity(fileLintCmdFailSeverity)
+			if err != nil {
+				return err
+			}
+			var rulesets []*lint.Ruleset
+			for _, filename := range args {
+				content, err := readInputFile(filename)
+				if err != nil {
+					return err
+				}
+				ruleset, err := lint.ParseRuleset(content)
+				if err != nil {
+					return fmt.Errorf("%s: %w", filename, err)
+				}
+				rulesets = append(rulesets, ruleset)
+			}
+			var base []lint.Rule
+			if !fileLintCmdNoBuiltinRules {
+				base = lint.BuiltinRules()
+			}
+			rules, err := lint.Rules(base, rulesets...)
+			if err != nil {
+				return err
+			}
+
+			var findings []lintFinding
+			failures := 0
+			for _, filename := range fileLintCmdStateFiles {
+				content, err := readInputFile(filename)
+				if filename == "-" {
+					filename = "stdin"
+				}
+				if err != nil {
+					return err
+				}
+				fileFindings, err := lint.Lint(content, rules)
+				if err != nil {
+					return fmt.Errorf("%s: %w", filename, err)
+				}
+				for _, f := range fileFindings {
+					findings = append(findings, lintFinding{File: filename, Finding: f})
+					if failSeverity != lint.SeverityOff && f.Severity >= failSeverity {
+						failures++
+					}
+				}
+			}
+			if err := printLintFindings(os.Stdout, findings, fileLintCmdFormat); err != nil {
+				return err
+			}
+			if failures > 0 {
+				cmd.SilenceUsage = true
+				return fmt.Errorf("linting failed: %d findings with severity %s or above",
+					failures, failSeverity)
+			}
+			return nil
+		},
+	}
+
+	fileLintCmd.Flags().StringSliceVarP(&fileLintCmdStateFiles, "state", "s", []string{"-"},
+		"state file(s) to lint. Use `-` to read from stdin.")
+	fileLintCmd.Flags().StringVar(&fileLintCmdFormat, "format", outputFormatText,
+		"output format of the findings, one of: text, json.")
+	fileLintCmd.Flags().StringVar(&fileLintCmdFailSeverity, "fail-severity", "error",
+		"minimum severity of the findings failing the command, one of: info, warn, error.\n"+
+			"Use `off` to never fail.")
+	fileLintCmd.Flags().BoolVar(&fileLintCmdNoBuiltinRules, "no-builtin-rules", false,
+		"only check the rules of the given ruleset files.")
+	return fileLintCmd
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/kong/deck/lint"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintLintFindings(t *testing.T) {
+	findings := []lintFinding{
+		{
+			File: "kong.yaml",
+			Finding: lint.Finding{
+				Rule:     "entity-tags",
+				Severity: lint.SeverityWarn,
+				Message:  "entities should be tagged",
+				Path:     "$.services[0]",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, printLintFindings(&buf, findings, outputFormatText))
+	assert.Equal(t, "kong.yaml: warn [entity-tags] $.services[0]: entities should be tagged\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, printLintFindings(&buf, findings, outputFormatJSON))
+	assert.JSONEq(t, `{
+		"findings": [{
+			"file": "kong.yaml",
+			"rule": "entity-tags",
+			"severity": "warn",
+			"message": "entities should be tagged",
+			"path": "$.services[0]"
+		}],
+		"summary": {"error": 0, "warn": 1, "info": 0}
+	}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, printLintFindings(&buf, nil, outputFormatJSON))
+	assert.JSONEq(t, `{"findings": [], "summary": {"error": 0, "warn": 0, "info": 0}}`, buf.String())
+}
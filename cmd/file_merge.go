@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kong/deck/file"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	fileMergeCmdOutputFile string
+	fileMergeCmdFormat     string
+)
+
+// validateFileFormat validates the output format of file commands.
+func validateFileFormat(format string) error {
+	if format != "yaml" && format != outputFormatJSON {
+		return fmt.Errorf("invalid output format '%s', must be one of: yaml, json", format)
+	}
+	return nil
+}
+
+// readDocument reads filename as a generic state file document.
+func readDocument(filename string) (file.Document, error) {
+	content, err := readInputFile(filename)
+	if err != nil {
+		return file.Document{}, err
+	}
+	doc := file.Document{Source: filename}
+	if err := yaml.Unmarshal(content, &doc.Data); err != nil {
+		return file.Document{}, fmt.Errorf("parsing state file %s: %w", filename, err)
+	}
+	if doc.Data == nil {
+		doc.Data = map[string]interface{}{}
+	}
+	return doc, nil
+}
+
+// marshalDocument encodes a state file document in format.
+func marshalDocument(doc map[string]interface{}, format string) ([]byte, error) {
+	if format == outputFormatJSON {
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+	return yaml.Marshal(doc)
+}
+
+// newFileMergeCmd represents the file merge command
+func newFileMergeCmd() *cobra.Command {
+	fileMergeCmd := &cobra.Command{
+		Use:   "merge [flags] state-files...",
+		Short: "Merge multiple state files into one",
+		Long: `The merge command merges multiple state files into a single one.
+
+Lists of entities are concatenated. Top-level fields like _format_version
+or _workspace must have the same value in all files defining them.
+Entities defined in more than one file are reported as conflicts, and
+no output is written.
+
+Templates like ${{ env "DECK_FOO" }} are kept as is.`,
+		Example: `  # merge the state files of two teams
+  deck file merge team-a.yaml team-b.yaml -o kong.yaml`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateFileFormat(fileMergeCmdFormat); err != nil {
+				return err
+			}
+			docs := make([]file.Document, 0, len(args))
+			for _, filename := range args {
+				doc, err := readDocument(filename)
+				if err != nil {
+					return err
+				}
+				docs = append(docs, doc)
+			}
+			merged, err := file.MergeDocuments(docs)
+			if err != nil {
+				return err
+			}
+			output, err := marshalDocument(merged, fileMergeCmdFormat)
+			if err != nil {
+				return err
+			}
+			return writeOutputFile(fileMergeCmdOutputFile, output)
+		},
+	}
+
+	fileMergeCmd.Flags().StringVarP(&fileMergeCmdOutputFile, "output-file", "o", "-",
+		"file to write the merged state to. Use `-` to write to stdout.")
+	fileMergeCmd.Flags().StringVar(&fileMergeCmdFormat, "format", "yaml",
+		"output format of the merged state, one of: yaml, json.")
+	return fileMergeCmd
+}
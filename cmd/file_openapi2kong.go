@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/deck/openapi2kong"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fileOpenapi2KongCmdInputFile  string
+	fileOpenapi2KongCmdOutputFile string
+	fileOpenapi2KongCmdFormat     string
+	fileOpenapi2KongCmdSelectTags []string
+	fileOpenapi2KongCmdTags       []string
+)
+
+// newFileOpenapi2KongCmd represents the file openapi2kong command
+func newFileOpenapi2KongCmd() *cobra.Command {
+	fileOpenapi2KongCmd := &cobra.Command{
+		Use:   "openapi2kong",
+		Short: "Convert an OpenAPI specification into a state file",
+		Long: `The openapi2kong command converts an OpenAPI 3 specification into
+a state file, with a service for the API and a route per operation.
+
+The generated entities can be customized with extensions in the
+specification:
+
+  x-kong-name               name of the service, or of the routes of a
+                            path or operation
+  x-kong-service-defaults   fields of the service
+  x-kong-upstream-defaults  fields of the upstream created when
+                            multiple servers are listed
+  x-kong-route-defaults     fields of the routes, at the document,
+                            path or operation level
+  x-kong-plugin-<name>      a plugin, on the service at the document
+                            level, on the routes at the path or
+                            operation level`,
+		Args: validateNoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format := file.Format(strings.ToUpper(fileOpenapi2KongCmdFormat))
+			if format != file.YAML && format != file.JSON {
+				return fmt.Errorf("invalid output format '%s', must be one of: yaml, json",
+					fileOpenapi2KongCmdFormat)
+			}
+			spec, err := readInputFile(fileOpenapi2KongCmdInputFile)
+			if err != nil {
+				return err
+			}
+			content, err := openapi2kong.Convert(spec, openapi2kong.Options{
+				SelectTags: fileOpenapi2KongCmdSelectTags,
+				Tags:       fileOpenapi2KongCmdTags,
+			})
+			if err != nil {
+				return err
+			}
+			return file.WriteContentToFile(content, fileOpenapi2KongCmdOutputFile, format)
+		},
+	}
+
+	fileOpenapi2KongCmd.Flags().StringVarP(&fileOpenapi2KongCmdInputFile, "spec", "s", "-",
+		"OpenAPI specification to convert, in YAML or JSON. Use `-` to read from stdin.")
+	fileOpenapi2KongCmd.Flags().StringVarP(&fileOpenapi2KongCmdOutputFile, "output-file", "o", "-",
+		"file to write the state to. Use `-` to write to stdout.")
+	fileOpenapi2KongCmd.Flags().StringVar(&fileOpenapi2KongCmdFormat, "format", "yaml",
+		"output format of the state file, one of: yaml, json.")
+	fileOpenapi2KongCmd.Flags().StringSliceVar(&fileOpenapi2KongCmdSelectTags, "select-tag", []string{},
+		"select tags to set in the state file, also added to all entities.\n"+
+			"Multiple tags can be specified as a comma-separated list or by repeating the flag.")
+	fileOpenapi2KongCmd.Flags().StringSliceVar(&fileOpenapi2KongCmdTags, "tag", []string{},
+		"tags to add to all entities.\n"+
+			"Multiple tags can be specified as a comma-separated list or by repeating the flag.")
+	return fileOpenapi2KongCmd
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/kong/deck/patch"
+	"github.com/spf13/cobra"
+)
+
+var (
+	filePatchCmdInputFile  string
+	filePatchCmdOutputFile string
+	filePatchCmdFormat     string
+	filePatchCmdSelectors  []string
+	filePatchCmdValues     []string
+	filePatchCmdRemove     []string
+)
+
+// parsePatchValues parses `field:value` pairs into patch values.
+// Values are parsed as JSON if valid, and kept as strings otherwise.
+func parsePatchValues(values []string) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	for _, keyValue := range values {
+		field, raw, ok := strings.Cut(keyValue, ":")
+		if !ok || field == "" {
+			return nil, fmt.Errorf("invalid value '%s', expected 'field:value'", keyValue)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		res[field] = value
+	}
+	return res, nil
+}
+
+// newFilePatchCmd represents the file patch command
+func newFilePatchCmd() *cobra.Command {
+	filePatchCmd := &cobra.Command{
+		Use:   "patch [flags] [...patch-files]",
+		Short: "Apply patches to a state file",
+		Long: `The patch command applies patches to a state file.
+
+Patches select objects in the state file with JSONPath-like selectors,
+then set or remove fields of these objects. They can be read from
+patch files, or given on the command line with --selector, --value
+and --remove. Patch files are applied first, in order.
+
+A patch file looks like:
+
+  _format_version: "1.0"
+  patches:
+  - selectors:
+    - $..plugins[?(@.name == 'rate-limiting')].config
+    values:
+      minute: 100
+    remove:
+    - hour
+
+Selectors support '$', '.field', '..field', '[*]', '[n]' and filters
+like '[?(@.name == 'svc1')]' or '[?('team-a' in @.tags)]'.`,
+		Example: `  # set the read timeout of all services tagged with team-a
+  deck file patch -s kong.yaml -o patched.yaml \
+    --selector "$.services[?('team-a' in @.tags)]" --value read_timeout:30000
+
+  # apply the patches of a file to the state read from stdin
+  cat kong.yaml | deck file patch prod-patches.yaml`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateFileFormat(filePatchCmdFormat); err != nil {
+				return err
+			}
+			var patches []patch.Patch
+			for _, filename := range args {
+				content, err := readInputFile(filename)
+				if err != nil {
+					return err
+				}
+				f, err := patch.ParseFile(content)
+				if err != nil {
+					return fmt.Errorf("%s: %w", filename, err)
+				}
+				patches = append(patches, f.Patches...)
+			}
+			if len(filePatchCmdSelectors) > 0 {
+				values, err := parsePatchValues(filePatchCmdValues)
+				if err != nil {
+					return err
+				}
+				patches = append(patches, patch.Patch{
+					Selectors: filePatchCmdSelectors,
+					Values:    values,
+					Remove:    filePatchCmdRemove,
+				})
+			} else if len(filePatchCmdValues) > 0 || len(filePatchCmdRemove) > 0 {
+				return fmt.Errorf("--value and --remove require at least one --selector")
+			}
+
+			content, err := readInputFile(filePatchCmdInputFile)
+			if err != nil {
+				return err
+			}
+			output, err := patch.ApplyToState(content, patches, filePatchCmdFormat == outputFormatJSON)
+			if err != nil {
+				return err
+			}
+			return writeOutputFile(filePatchCmdOutputFile, output)
+		},
+	}
+
+	filePatchCmd.Flags().StringVarP(&filePatchCmdInputFile, "state", "s", "-",
+		"state file to patch. Use `-` to read from stdin.")
+	filePatchCmd.Flags().StringVarP(&filePatchCmdOutputFile, "output-file", "o", "-",
+		"file to write the patched state to. Use `-` to write to stdout.")
+	filePatchCmd.Flags().StringVar(&filePatchCmdFormat, "format", "yaml",
+		"output format of the patched state, one of: yaml, json.")
+	filePatchCmd.Flags().StringArrayVar(&filePatchCmdSelectors, "selector", []string{},
+		"selector of the objects to patch.\n"+
+			"This flag can be specified multiple times.")
+	filePatchCmd.Flags().StringArrayVar(&filePatchCmdValues, "value", []string{},
+		"field to set on the selected objects, as 'field:value'.\n"+
+			"The value is parsed as JSON, or used as a string if it is not valid JSON.\n"+
+			"This flag can be specified multiple times.")
+	filePatchCmd.Flags().StringArrayVar(&filePatchCmdRemove, "remove", []string{},
+		"field to remove from the selected objects.\n"+
+			"This flag can be specified multiple times.")
+	return filePatchCmd
+}
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePatchValues(t *testing.T) {
+	values, err := parsePatchValues([]string{
+		"read_timeout:30000",
+		"host:example.com",
+		"enabled:true",
+		"tags:[\"a\",\"b\"]",
+		"path:null",
+		"url:http://example.com:8080",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"read_timeout": float64(30000),
+		"host":         "example.com",
+		"enabled":      true,
+		"tags":         []interface{}{"a", "b"},
+		"path":         nil,
+		"url":          "http://example.com:8080",
+	}, values)
+
+	_, err = parsePatchValues([]string{"read_timeout"})
+	assert.EqualError(t, err, "invalid value 'read_timeout', expected 'field:value'")
+	_, err = parsePatchValues([]string{":1"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fileSchemaCmdOutputFile string
+	fileSchemaCmdPlugins    []string
+	fileSchemaCmdAllPlugins bool
+)
+
+// listEnabledPlugins returns the names of the plugins enabled in Kong.
+func listEnabledPlugins(ctx context.Context, client *kong.Client) ([]string, error) {
+	req, err := client.NewRequest(http.MethodGet, "/plugins/enabled", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	var enabled struct {
+		EnabledPlugins []string `json:"enabled_plugins"`
+	}
+	if _, err := client.Do(ctx, req, &enabled); err != nil {
+		return nil, fmt.Errorf("listing enabled plugins: %w", err)
+	}
+	return enabled.EnabledPlugins, nil
+}
+
+// fetchPluginSchemas returns the schemas of plugins, indexed by name.
+func fetchPluginSchemas(ctx context.Context, client *kong.Client,
+	names []string,
+) (map[string]map[string]interface{}, error) {
+	schemas := map[string]map[string]interface{}{}
+	for _, name := range names {
+		name := name
+		schema, err := client.Plugins.GetFullSchema(ctx, &name)
+		if err != nil {
+			return nil, fmt.Errorf("retrieving schema of plugin %s: %w", name, err)
+		}
+		schemas[name] = schema
+	}
+	return schemas, nil
+}
+
+// newFileSchemaCmd represents the file schema command
+func newFileSchemaCmd() *cobra.Command {
+	fileSchemaCmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Print the JSON schema of state files",
+		Long: `The schema command prints the JSON schema of state files, which
+editors and CI can use to validate state files and autocomplete them.
+
+By default, the configuration of plugins can be any object. With --plugin
+or --all-plugins, the schemas of plugins are read from Kong, so that the
+configuration of these plugins is validated too.`,
+		Args: validateNoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+			var pluginSchemas map[string]map[string]interface{}
+			if fileSchemaCmdAllPlugins || len(fileSchemaCmdPlugins) > 0 {
+				client, err := utils.GetKongClient(rootConfig)
+				if err != nil {
+					return err
+				}
+				names := fileSchemaCmdPlugins
+				if fileSchemaCmdAllPlugins {
+					if names, err = listEnabledPlugins(ctx, client); err != nil {
+						return err
+					}
+				}
+				if pluginSchemas, err = fetchPluginSchemas(ctx, client, names); err != nil {
+					return err
+				}
+			}
+			schema, err := file.JSONSchema(pluginSchemas)
+			if err != nil {
+				return err
+			}
+			return writeOutputFile(fileSchemaCmdOutputFile, schema)
+		},
+	}
+
+	fileSchemaCmd.Flags().StringVarP(&fileSchemaCmdOutputFile, "output-file", "o", "-",
+		"file to write the schema to. Use `-` to write to stdout.")
+	fileSchemaCmd.Flags().StringSliceVar(&fileSchemaCmdPlugins, "plugin", []string{},
+		"plugins whose configuration schema is read from Kong.\n"+
+			"Multiple plugins can be specified as a comma-separated list or by repeating the flag.")
+	fileSchemaCmd.Flags().BoolVar(&fileSchemaCmdAllPlugins, "all-plugins", false,
+		"read the configuration schema of all plugins enabled in Kong.")
+	fileSchemaCmd.MarkFlagsMutuallyExclusive("plugin", "all-plugins")
+	return fileSchemaCmd
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/kong/deck/file"
+	"github.com/spf13/cobra"
+)
+
+var (
+	fileSplitCmdInputFile string
+	fileSplitCmdOutputDir string
+	fileSplitCmdFormat    string
+	fileSplitCmdBy        string
+)
+
+// splitStateFile splits the state file filename and writes the resulting
+// files to dir. It returns the paths of the written files.
+func splitStateFile(filename, dir string, by file.SplitBy, format string) ([]string, error) {
+	doc, err := readDocument(filename)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := file.SplitDocument(doc.Data, by)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating output directory: %w", err)
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		output, err := marshalDocument(groups[name], format)
+		if err != nil {
+			return nil, err
+		}
+		path := filepath.Join(dir, name+"."+format)
+		if err := writeOutputFile(path, output); err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// newFileSplitCmd represents the file split command
+func newFileSplitCmd() *cobra.Command {
+	fileSplitCmd := &cobra.Command{
+		Use:   "split",
+		Short: "Split a state file into multiple files",
+		Long: `The split command splits a state file, like the output of dump,
+into multiple files written to a directory.
+
+With --by type, each top-level list of entities is written to its own
+file, like services.yaml or consumers.yaml. Routes and plugins nested in
+services stay in services.yaml.
+With --by tag, entities are written to a file named after their first
+tag which is not a select tag. Entities without tags are written to
+untagged.yaml.
+
+Each file holds the _format_version and _info of the input, so that the
+directory can be given to sync with --state.`,
+		Example: `  # split a dump per entity type
+  deck dump -o kong.yaml
+  deck file split -s kong.yaml -o kong/`,
+		Args: validateNoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := validateFileFormat(fileSplitCmdFormat); err != nil {
+				return err
+			}
+			paths, err := splitStateFile(fileSplitCmdInputFile, fileSplitCmdOutputDir,
+				file.SplitBy(fileSplitCmdBy), fileSplitCmdFormat)
+			if err != nil {
+				return err
+			}
+			for _, path := range paths {
+				fmt.Println("wrote", path)
+			}
+			return nil
+		},
+	}
+
+	fileSplitCmd.Flags().StringVarP(&fileSplitCmdInputFile, "state", "s", "-",
+		"state file to split. Use `-` to read from stdin.")
+	fileSplitCmd.Flags().StringVarP(&fileSplitCmdOutputDir, "output-dir", "o", ".",
+		"directory to write the files to. It is created if it does not exist.")
+	fileSplitCmd.Flags().StringVar(&fileSplitCmdFormat, "format", "yaml",
+		"output format of the files, one of: yaml, json.")
+	fileSplitCmd.Flags().StringVar(&fileSplitCmdBy, "by", string(file.SplitByType),
+		"how to group entities, one of: type, tag.")
+	return fileSplitCmd
+}
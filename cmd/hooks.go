@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/kong/deck/cprint"
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/state"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// Events of sync hooks.
+const (
+	hookEventPreSync  = "pre-sync"
+	hookEventPostSync = "post-sync"
+)
+
+// maxHookTextChanges is the number of changes listed in the text of
+// hook payloads, to keep chat notifications short.
+const maxHookTextChanges = 20
+
+var (
+	syncCmdPreSyncHooks  []string
+	syncCmdPostSyncHooks []string
+	syncCmdWebhookURLs   []string
+)
+
+// syncHookPayload is sent to hooks, on the standard input of commands and
+// as the body of webhooks.
+type syncHookPayload struct {
+	Event     string              `json:"event"`
+	Time      time.Time           `json:"time"`
+	Workspace string              `json:"workspace,omitempty"`
+	Changes   []diff.EntityChange `json:"changes"`
+	Summary   jsonDiffSummary     `json:"summary"`
+	Error     string              `json:"error,omitempty"`
+	// Text summarizes the payload for humans. It is the field displayed by
+	// Slack and Microsoft Teams incoming webhooks.
+	Text string `json:"text"`
+}
+
+func syncHooksEnabled() bool {
+	return len(syncCmdPreSyncHooks) > 0 || len(syncCmdPostSyncHooks) > 0 ||
+		len(syncCmdWebhookURLs) > 0
+}
+
+func newSyncHookPayload(event, workspace string, changes []diff.EntityChange, err error) syncHookPayload {
+	p := syncHookPayload{
+		Event:     event,
+		Time:      time.Now().UTC(),
+		Workspace: workspace,
+		Changes:   changes,
+		Summary:   summarizeChanges(changes),
+	}
+	if p.Changes == nil {
+		p.Changes = []diff.EntityChange{}
+	}
+	if err != nil {
+		p.Error = err.Error()
+	}
+
+	var text strings.Builder
+	text.WriteString("decK ")
+	if event == hookEventPreSync {
+		text.WriteString("sync starting")
+	} else {
+		text.WriteString("sync finished")
+	}
+	if workspace != "" {
+		fmt.Fprintf(&text, " in workspace %s", workspace)
+	}
+	fmt.Fprintf(&text, ": %d created, %d updated, %d deleted",
+		p.Summary.Created, p.Summary.Updated, p.Summary.Deleted)
+	for i, c := range p.Changes {
+		if i == maxHookTextChanges {
+			fmt.Fprintf(&text, "\n... and %d more", len(p.Changes)-maxHookTextChanges)
+			break
+		}
+		fmt.Fprintf(&text, "\n%s %s %s", c.Action, c.Kind, c.Name)
+	}
+	if p.Error != "" {
+		fmt.Fprintf(&text, "\nError: %s", p.Error)
+	}
+	p.Text = text.String()
+	return p
+}
+
+// plannedChanges returns the changes a sync would perform, without
+// printing them. The current state is not modified.
+func plannedChanges(ctx context.Context, rawCurrentState *utils.KongRawState,
+	targetState *state.KongState, client *kong.Client, isKonnect bool,
+) ([]diff.EntityChange, error) {
+	// the syncer updates the current state while solving
+	currentState, err := state.Get(rawCurrentState)
+	if err != nil {
+		return nil, err
+	}
+	noop := func(a ...interface{}) {}
+	s, err := diff.NewSyncer(diff.SyncerOpts{
+		CurrentState:  currentState,
+		TargetState:   targetState,
+		KongClient:    client,
+		NoMaskValues:  noMaskValues || noMask,
+		NoMaskSecrets: noMask,
+		IsKonnect:     isKonnect,
+		RecordChanges: true,
+		CreatePrintln: noop,
+		UpdatePrintln: noop,
+		DeletePrintln: noop,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, errs := s.Solve(ctx, 1, true); errs != nil {
+		return nil, utils.ErrArray{Errors: errs}
+	}
+	return s.Changes(), nil
+}
+
+// runSyncHooks runs the hook commands of the payload's event and sends
+// the payload to webhooks. The failure of a pre-sync command is returned
+// to abort the sync; other failures are reported as warnings.
+func runSyncHooks(ctx context.Context, p syncHookPayload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	commands := syncCmdPostSyncHooks
+	if p.Event == hookEventPreSync {
+		commands = syncCmdPreSyncHooks
+	}
+	for _, command := range commands {
+		if err := runHookCommand(ctx, command, p.Event, body); err != nil {
+			if p.Event == hookEventPreSync {
+				return fmt.Errorf("pre-sync hook failed, aborting sync: %w", err)
+			}
+			cprint.DeletePrintln("Warning: post-sync hook failed:", err)
+		}
+	}
+	client := utils.HTTPClient()
+	for _, url := range syncCmdWebhookURLs {
+		if err := postWebhook(ctx, client, url, p); err != nil {
+			cprint.DeletePrintln("Warning:", err)
+		}
+	}
+	return nil
+}
+
+// runHookCommand runs a hook command with the shell, with the payload
+// on its standard input.
+func runHookCommand(ctx context.Context, command, event string, payload []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(os.Environ(), "DECK_HOOK_EVENT="+event)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return nil
+}
+
+// postWebhook posts v as JSON to url.
+func postWebhook(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("sending webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/deck/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncHookPayloadText(t *testing.T) {
+	var changes []diff.EntityChange
+	for i := 0; i < maxHookTextChanges+2; i++ {
+		changes = append(changes, diff.EntityChange{
+			Action: diff.ActionCreate, Kind: "service", Name: fmt.Sprintf("svc%d", i),
+		})
+	}
+	p := newSyncHookPayload(hookEventPostSync, "ws", changes, errors.New("boom"))
+	lines := strings.Split(p.Text, "\n")
+	assert.Equal(t, "decK sync finished in workspace ws: 22 created, 0 updated, 0 deleted", lines[0])
+	assert.Equal(t, "create service svc0", lines[1])
+	assert.Equal(t, "... and 2 more", lines[len(lines)-2])
+	assert.Equal(t, "Error: boom", lines[len(lines)-1])
+	assert.Equal(t, int32(22), p.Summary.Created)
+}
+
+func TestSyncHooks(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+
+	var mu sync.Mutex
+	var webhookPayloads []syncHookPayload
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p syncHookPayload
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		mu.Lock()
+		webhookPayloads = append(webhookPayloads, p)
+		mu.Unlock()
+	}))
+	defer webhook.Close()
+
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "kong.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(`
+_format_version: "3.0"
+services:
+- name: svc1
+  host: example.com
+`), 0o600))
+	preHookOutput := filepath.Join(dir, "pre.json")
+
+	defer func(config utils.KongClientConfig, analytics bool) {
+		rootConfig, disableAnalytics = config, analytics
+		syncCmdPreSyncHooks, syncCmdPostSyncHooks, syncCmdWebhookURLs = nil, nil, nil
+	}(rootConfig, disableAnalytics)
+	rootConfig = utils.KongClientConfig{Address: server.URL}
+	disableAnalytics = true
+	ctx := context.Background()
+
+	// a failing pre-sync hook aborts the sync
+	syncCmdPreSyncHooks = []string{"exit 1"}
+	_, err := syncFiles(ctx, []string{stateFile}, false, 1, 0, "")
+	require.ErrorContains(t, err, "pre-sync hook failed")
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	services, err := client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services)
+
+	syncCmdPreSyncHooks = []string{"cat > " + preHookOutput}
+	syncCmdWebhookURLs = []string{webhook.URL}
+	_, err = syncFiles(ctx, []string{stateFile}, false, 1, 0, "")
+	require.NoError(t, err)
+
+	b, err := os.ReadFile(preHookOutput)
+	require.NoError(t, err)
+	var pre syncHookPayload
+	require.NoError(t, json.Unmarshal(b, &pre))
+	assert.Equal(t, hookEventPreSync, pre.Event)
+	require.Len(t, pre.Changes, 1)
+	assert.Equal(t, diff.ActionCreate, pre.Changes[0].Action)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, webhookPayloads, 2)
+	assert.Equal(t, hookEventPreSync, webhookPayloads[0].Event)
+	assert.Equal(t, hookEventPostSync, webhookPayloads[1].Event)
+	assert.Equal(t, jsonDiffSummary{
+		Created: 1,
+		ByKind:  map[string]jsonDiffCounts{"service": {Created: 1}},
+	}, webhookPayloads[1].Summary)
+	assert.Empty(t, webhookPayloads[1].Error)
+}
@@ -27,7 +27,7 @@ func newKonnectDiffCmd() *cobra.Command {
 				konnectConfig.Address = defaultLegacyKonnectURL
 			}
 			return syncKonnect(cmd.Context(), konnectDiffCmdKongStateFile, true,
-				konnectDiffCmdParallelism)
+				konnectDiffCmdParallelism, konnectDiffCmdNonZeroExitCode)
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return preRunSilenceEventsFlag()
@@ -44,6 +44,9 @@ func newKonnectDiffCmd() *cobra.Command {
 		100, "Maximum number of concurrent operations.")
 	konnectDiffCmd.Flags().BoolVar(&noMaskValues, "no-mask-deck-env-vars-value",
 		false, "do not mask DECK_ environment variable values at diff output.")
+	konnectDiffCmd.Flags().BoolVar(&noMask, "no-mask",
+		false, "do not mask secrets, such as credentials, private keys of certificates\n"+
+			"and encrypted plugin fields, nor DECK_ environment variable values at diff output.")
 	konnectDiffCmd.Flags().BoolVar(&konnectDiffCmdNonZeroExitCode, "non-zero-exit-code",
 		false, "return exit code 2 if there is a diff present,\n"+
 			"exit code 0 if no diff is found,\n"+
@@ -62,9 +62,10 @@ func newKonnectDumpCmd() *cobra.Command {
 
 			// initialize kong client
 			kongClient, err := utils.GetKongClient(utils.KongClientConfig{
-				Address:    konnectConfig.Address + "/api/control_planes/" + kongCPID,
-				HTTPClient: httpClient,
-				Debug:      konnectConfig.Debug,
+				Address:         konnectConfig.Address + "/api/control_planes/" + kongCPID,
+				HTTPClient:      httpClient,
+				DebugLevel:      konnectConfig.DebugLevel,
+				DebugSampleRate: konnectConfig.DebugSampleRate,
 			})
 			if err != nil {
 				return err
@@ -32,7 +32,7 @@ credentials.` + konnectAlphaState,
 			}
 			fmt.Printf("Successfully Konnected as %s %s (%s)!\n",
 				res.FirstName, res.LastName, res.Organization)
-			if konnectConfig.Debug {
+			if konnectConfig.DebugLevel > utils.DebugLevelNone {
 				fmt.Printf("Organization ID: %s\n", res.OrganizationID)
 			}
 			return nil
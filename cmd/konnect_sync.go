@@ -19,7 +19,7 @@ to get Konnect's state in sync with the input state.` + konnectAlphaState,
 				konnectConfig.Address = defaultLegacyKonnectURL
 			}
 			return syncKonnect(cmd.Context(), konnectDiffCmdKongStateFile, false,
-				konnectDiffCmdParallelism)
+				konnectDiffCmdParallelism, false)
 		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			return preRunSilenceEventsFlag()
@@ -36,6 +36,9 @@ to get Konnect's state in sync with the input state.` + konnectAlphaState,
 		100, "Maximum number of concurrent operations.")
 	konnectSyncCmd.Flags().BoolVar(&noMaskValues, "no-mask-deck-env-vars-value",
 		false, "do not mask DECK_ environment variable values at diff output.")
+	konnectSyncCmd.Flags().BoolVar(&noMask, "no-mask",
+		false, "do not mask secrets, such as credentials, private keys of certificates\n"+
+			"and encrypted plugin fields, nor DECK_ environment variable values at diff output.")
 	addSilenceEventsFlag(konnectSyncCmd.Flags())
 	return konnectSyncCmd
 }
@@ -55,7 +55,7 @@ func pingKonnect(ctx context.Context) error {
 	}
 
 	fmt.Printf("Successfully Konnected to the %s organization!\n", res.Name)
-	if konnectConfig.Debug {
+	if konnectConfig.DebugLevel > utils.DebugLevelNone {
 		fmt.Printf("Organization ID: %s\n", res.OrganizationID)
 	}
 	_ = sendAnalytics("ping", "", modeKonnect)
@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/file"
+	"github.com/kong/deck/utils"
+)
+
+const planFormatVersion = "1.0"
+
+// plan is a saved diff, which sync can apply as long as the state
+// of Kong did not change since the plan was computed.
+type plan struct {
+	FormatVersion string    `json:"_format_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Workspace     string    `json:"workspace,omitempty"`
+	SelectTags    []string  `json:"select_tags,omitempty"`
+	// CurrentStateHash is the hash of the state of Kong the plan was
+	// computed against.
+	CurrentStateHash string `json:"current_state_hash"`
+	// Target is the content of the state files, with environment
+	// variables rendered.
+	Target  *file.Content       `json:"target"`
+	Changes []diff.EntityChange `json:"changes"`
+	Summary jsonDiffSummary     `json:"summary"`
+}
+
+var (
+	// diffCmdPlanFile is the file the plan of a diff is written to.
+	diffCmdPlanFile string
+	// syncCmdPlan is the plan to apply instead of the state files.
+	syncCmdPlan *plan
+)
+
+func newPlan(workspace string, selectTags []string, currentStateHash string,
+	target *file.Content, changes []diff.EntityChange,
+) *plan {
+	p := &plan{
+		FormatVersion:    planFormatVersion,
+		CreatedAt:        time.Now().UTC(),
+		Workspace:        workspace,
+		SelectTags:       selectTags,
+		CurrentStateHash: currentStateHash,
+		Target:           target,
+		Changes:          changes,
+		Summary:          summarizeChanges(changes),
+	}
+	if p.Changes == nil {
+		p.Changes = []diff.EntityChange{}
+	}
+	return p
+}
+
+// writePlan writes a plan to filename. Plans hold the rendered state
+// files, including the values of environment variables, hence the
+// file is only readable by its owner.
+func writePlan(filename string, p *plan) error {
+	b, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing plan: %w", err)
+	}
+	return nil
+}
+
+// readPlan reads a plan written by writePlan.
+func readPlan(filename string) (*plan, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading plan: %w", err)
+	}
+	var p plan
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, fmt.Errorf("parsing plan %s: %w", filename, err)
+	}
+	if p.FormatVersion != planFormatVersion {
+		return nil, fmt.Errorf("unsupported plan format version '%s'", p.FormatVersion)
+	}
+	if p.Target == nil || p.CurrentStateHash == "" {
+		return nil, fmt.Errorf("invalid plan %s: target and current_state_hash are required", filename)
+	}
+	return &p, nil
+}
+
+// checkPlanCurrentState returns an error if the state of Kong changed
+// since the plan was computed.
+func checkPlanCurrentState(p *plan, currentStateHash string) error {
+	if p.CurrentStateHash != currentStateHash {
+		return fmt.Errorf("the state of Kong changed since the plan was computed on %s, "+
+			"refusing to apply it: run diff again to compute a new plan",
+			p.CreatedAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// hashRawState returns a hash of the entities of a state.
+// The hash does not depend on the order of entities.
+func hashRawState(raw *utils.KongRawState) (string, error) {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return "", err
+	}
+	var entities map[string][]json.RawMessage
+	if err := json.Unmarshal(b, &entities); err != nil {
+		return "", err
+	}
+	kinds := make([]string, 0, len(entities))
+	for kind := range entities {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+
+	h := sha256.New()
+	for _, kind := range kinds {
+		items := make([]string, 0, len(entities[kind]))
+		for _, item := range entities[kind] {
+			items = append(items, string(item))
+		}
+		sort.Strings(items)
+		fmt.Fprintf(h, "%s:%d\n", kind, len(items))
+		for _, item := range items {
+			fmt.Fprintln(h, item)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashRawStateIgnoresOrder(t *testing.T) {
+	svc1 := &kong.Service{ID: kong.String("1"), Name: kong.String("svc1")}
+	svc2 := &kong.Service{ID: kong.String("2"), Name: kong.String("svc2")}
+
+	h1, err := hashRawState(&utils.KongRawState{Services: []*kong.Service{svc1, svc2}})
+	require.NoError(t, err)
+	h2, err := hashRawState(&utils.KongRawState{Services: []*kong.Service{svc2, svc1}})
+	require.NoError(t, err)
+	assert.Equal(t, h1, h2)
+
+	h3, err := hashRawState(&utils.KongRawState{Services: []*kong.Service{svc1}})
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h3)
+}
+
+func TestPlanApply(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+
+	dir := t.TempDir()
+	stateFile := filepath.Join(dir, "kong.yaml")
+	require.NoError(t, os.WriteFile(stateFile, []byte(`
+_format_version: "3.0"
+services:
+- name: svc1
+  host: example.com
+`), 0o600))
+	planFile := filepath.Join(dir, "plan.json")
+
+	defer func(config utils.KongClientConfig, analytics bool) {
+		rootConfig, disableAnalytics = config, analytics
+		diffCmdPlanFile, syncCmdPlan = "", nil
+	}(rootConfig, disableAnalytics)
+	rootConfig = utils.KongClientConfig{Address: server.URL}
+	disableAnalytics = true
+
+	ctx := context.Background()
+	diffCmdPlanFile = planFile
+	_, err := syncFiles(ctx, []string{stateFile}, true, 1, 0, "")
+	require.NoError(t, err)
+	diffCmdPlanFile = ""
+
+	p, err := readPlan(planFile)
+	require.NoError(t, err)
+	require.Len(t, p.Changes, 1)
+	assert.Equal(t, diff.ActionCreate, p.Changes[0].Action)
+	assert.Equal(t, int32(1), p.Summary.Created)
+
+	// the state of Kong changes after the plan was computed
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	_, err = client.Services.Create(ctx, &kong.Service{Name: kong.String("svc2"), Host: kong.String("example.com")})
+	require.NoError(t, err)
+
+	syncCmdPlan = p
+	_, err = syncFiles(ctx, nil, false, 1, 0, p.Workspace)
+	require.ErrorContains(t, err, "the state of Kong changed since the plan was computed")
+
+	// the plan applies exactly once the state is back
+	require.NoError(t, client.Services.Delete(ctx, kong.String("svc2")))
+	ops, err := syncFiles(ctx, nil, false, 1, 0, p.Workspace)
+	require.NoError(t, err)
+	assert.Equal(t, 1, ops)
+	svc, err := client.Services.Get(ctx, kong.String("svc1"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", *svc.Host)
+}
+
+func TestDiffPlanOutAllWorkspaces(t *testing.T) {
+	defer func() {
+		diffCmdPlanFile, diffAllWorkspaces, diffCmdKongStateFile = "", false, nil
+	}()
+	cmd := newDiffCmd()
+	cmd.SetArgs([]string{"-s", "kong.yaml", "--all-workspaces", "--plan-out", "plan.json"})
+	cmd.SilenceUsage = true
+	err := cmd.Execute()
+	require.ErrorContains(t, err, "--plan-out cannot be used with --all-workspaces")
+}
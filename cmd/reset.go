@@ -1,30 +1,222 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
+	"reflect"
+	"strings"
 
+	"github.com/kong/deck/dump"
 	"github.com/kong/deck/state"
+	"github.com/kong/deck/types"
 	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
 	"github.com/spf13/cobra"
 )
 
 var (
-	resetCmdForce      bool
-	resetWorkspace     string
-	resetAllWorkspaces bool
+	resetCmdForce       bool
+	resetWorkspace      string
+	resetAllWorkspaces  bool
+	resetCmdEntityTypes []string
 )
 
+// resetTarget is the state of Kong deleted by a reset, in a workspace
+// or Konnect runtime group.
+type resetTarget struct {
+	workspace string
+	client    *kong.Client
+	isKonnect bool
+	state     *state.KongState
+	count     int
+}
+
+// filterRawStateEntityTypes returns the entities of raw of the given
+// entity types, all entities if entityTypes is empty.
+// Kong refuses to delete services which have routes, so the routes of
+// services, and the plugins scoped to these routes, are returned with
+// services.
+func filterRawStateEntityTypes(raw *utils.KongRawState, entityTypes []string) (*utils.KongRawState, error) {
+	if len(entityTypes) == 0 {
+		return raw, nil
+	}
+	res := &utils.KongRawState{}
+	requested := map[types.EntityType]bool{}
+	for _, entityType := range entityTypes {
+		requested[types.EntityType(entityType)] = true
+		switch types.EntityType(entityType) {
+		case types.Service:
+			res.Services = raw.Services
+		case types.Route:
+			res.Routes = raw.Routes
+		case types.Plugin:
+			res.Plugins = raw.Plugins
+		case types.Certificate:
+			res.Certificates = raw.Certificates
+		case types.SNI:
+			res.SNIs = raw.SNIs
+		case types.CACertificate:
+			res.CACertificates = raw.CACertificates
+		case types.Upstream:
+			res.Upstreams = raw.Upstreams
+		case types.Target:
+			res.Targets = raw.Targets
+		case types.Consumer:
+			res.Consumers = raw.Consumers
+		case types.ConsumerGroup:
+			res.ConsumerGroups = raw.ConsumerGroups
+		case types.Vault:
+			res.Vaults = raw.Vaults
+		case types.KeyAuth:
+			res.KeyAuths = raw.KeyAuths
+		case types.HMACAuth:
+			res.HMACAuths = raw.HMACAuths
+		case types.JWTAuth:
+			res.JWTAuths = raw.JWTAuths
+		case types.BasicAuth:
+			res.BasicAuths = raw.BasicAuths
+		case types.ACLGroup:
+			res.ACLGroups = raw.ACLGroups
+		case types.OAuth2Cred:
+			res.Oauth2Creds = raw.Oauth2Creds
+		case types.MTLSAuth:
+			res.MTLSAuths = raw.MTLSAuths
+		case types.RBACRole:
+			res.RBACRoles = raw.RBACRoles
+		case types.RBACEndpointPermission:
+			res.RBACEndpointPermissions = raw.RBACEndpointPermissions
+		default:
+			return nil, fmt.Errorf("invalid entity type '%s', must be one of: %s",
+				entityType, strings.Join(resetEntityTypes, ", "))
+		}
+	}
+	if requested[types.Service] {
+		addServiceDependents(res, raw, requested)
+	}
+	return res, nil
+}
+
+// addServiceDependents adds to res the routes of the services of raw,
+// and the plugins scoped to these routes, unless their types are
+// already requested.
+func addServiceDependents(res, raw *utils.KongRawState, requested map[types.EntityType]bool) {
+	routeIDs := map[string]bool{}
+	for _, r := range raw.Routes {
+		if r.Service == nil || r.ID == nil {
+			continue
+		}
+		routeIDs[*r.ID] = true
+		if !requested[types.Route] {
+			res.Routes = append(res.Routes, r)
+		}
+	}
+	if requested[types.Plugin] {
+		return
+	}
+	for _, p := range raw.Plugins {
+		if p.Route != nil && p.Route.ID != nil && routeIDs[*p.Route.ID] {
+			res.Plugins = append(res.Plugins, p)
+		}
+	}
+}
+
+// resetEntityTypes are the entity types accepted by --entity-type.
+var resetEntityTypes = []string{
+	string(types.Service), string(types.Route), string(types.Plugin),
+	string(types.Certificate), string(types.SNI), string(types.CACertificate),
+	string(types.Upstream), string(types.Target),
+	string(types.Consumer), string(types.ConsumerGroup), string(types.Vault),
+	string(types.KeyAuth), string(types.HMACAuth), string(types.JWTAuth),
+	string(types.BasicAuth), string(types.ACLGroup), string(types.OAuth2Cred),
+	string(types.MTLSAuth), string(types.RBACRole), string(types.RBACEndpointPermission),
+}
+
+// countRawStateEntities returns the number of entities in raw.
+func countRawStateEntities(raw *utils.KongRawState) int {
+	count := 0
+	v := reflect.ValueOf(raw).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).Kind() == reflect.Slice {
+			count += v.Field(i).Len()
+		}
+	}
+	return count
+}
+
+func fetchResetTarget(ctx context.Context, client *kong.Client, workspace string,
+	isKonnect bool,
+) (resetTarget, error) {
+	rawState, err := dump.Get(ctx, client, dumpConfig)
+	if err != nil {
+		return resetTarget{}, err
+	}
+	rawState, err = filterRawStateEntityTypes(rawState, resetCmdEntityTypes)
+	if err != nil {
+		return resetTarget{}, err
+	}
+	count := countRawStateEntities(rawState)
+	currentState, err := state.Get(rawState)
+	if err != nil {
+		return resetTarget{}, err
+	}
+	return resetTarget{
+		workspace: workspace,
+		client:    client,
+		isKonnect: isKonnect,
+		state:     currentState,
+		count:     count,
+	}, nil
+}
+
+// resetConfirmationMessage describes what a reset deletes.
+func resetConfirmationMessage(targets []resetTarget, entityTypes, selectTags []string) string {
+	var b strings.Builder
+	count := 0
+	var workspaces []string
+	for _, t := range targets {
+		count += t.count
+		if t.workspace != "" {
+			workspaces = append(workspaces, fmt.Sprintf("%s (%d)", t.workspace, t.count))
+		}
+	}
+	fmt.Fprintf(&b, "This will delete %d entities from Kong's database", count)
+	if len(workspaces) > 0 {
+		fmt.Fprintf(&b, "\n  workspaces: %s", strings.Join(workspaces, ", "))
+	}
+	if len(entityTypes) > 0 {
+		fmt.Fprintf(&b, "\n  entity types: %s", strings.Join(entityTypes, ", "))
+	}
+	if len(selectTags) > 0 {
+		fmt.Fprintf(&b, "\n  select tags: %s", strings.Join(selectTags, ", "))
+	}
+	if len(entityTypes) == 0 && len(selectTags) == 0 {
+		b.WriteString("\n  all entities will be deleted")
+	}
+	b.WriteString("\n> Are you sure? ")
+	return b.String()
+}
+
 // newResetCmd represents the reset command
 func newResetCmd() *cobra.Command {
 	resetCmd := &cobra.Command{
 		Use:   "reset",
 		Short: "Reset deletes all entities in Kong",
-		Long: `The reset command deletes all entities in Kong's database.string.
+		Long: `The reset command deletes entities in Kong's database.
+
+Without any filter, all entities are deleted: use this command with
+extreme care as it's equivalent to running "kong migrations reset"
+on your Kong instance.
 
-Use this command with extreme care as it's equivalent to running
-"kong migrations reset" on your Kong instance.
+Deletion can be restricted to entities matching select tags, to some
+entity types with --entity-type, and to a workspace. Kong also deletes
+most entities depending on deleted ones, for example the targets of
+upstreams, but refuses to delete services which have routes: with
+--entity-type service, the routes of services and the plugins scoped
+to these routes are deleted as well.
 
-By default, this command will ask for confirmation.`,
+By default, this command asks for confirmation, after listing how many
+entities will be deleted. Use --force to skip the confirmation in
+automation.`,
 		Args: validateNoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			ctx := cmd.Context()
@@ -32,74 +224,105 @@ By default, this command will ask for confirmation.`,
 			if resetAllWorkspaces && resetWorkspace != "" {
 				return fmt.Errorf("workspace cannot be specified with --all-workspace flag")
 			}
-
-			if !resetCmdForce {
-				ok, err := utils.Confirm("This will delete all configuration from Kong's database." +
-					"\n> Are you sure? ")
-				if err != nil {
-					return err
-				}
-				if !ok {
-					return nil
-				}
+			if _, err := filterRawStateEntityTypes(&utils.KongRawState{}, resetCmdEntityTypes); err != nil {
+				return err
 			}
 
+			var targets []resetTarget
 			mode := getMode(nil)
 			if mode == modeKonnect {
+				if err := validateKonnectWorkspaceFlags(resetWorkspace, resetAllWorkspaces); err != nil {
+					return err
+				}
 				_ = sendAnalytics("reset", "", mode)
-				return resetKonnectV2(ctx)
-			}
-
-			rootClient, err := utils.GetKongClient(rootConfig)
-			if err != nil {
-				return err
-			}
-
-			kongVersion, err := fetchKongVersion(ctx, rootConfig.ForWorkspace(resetWorkspace))
-			if err != nil {
-				return fmt.Errorf("reading Kong version: %w", err)
-			}
-			_ = sendAnalytics("reset", kongVersion, mode)
-
-			var workspaces []string
-			// Kong OSS or default workspace
-			if !resetAllWorkspaces && resetWorkspace == "" {
-				workspaces = append(workspaces, "")
-			}
-
-			// Kong Enterprise
-			if resetAllWorkspaces {
-				workspaces, err = listWorkspaces(ctx, rootClient)
+				client, err := GetKongClientForKonnectMode(ctx, &konnectConfig)
 				if err != nil {
 					return err
 				}
-			}
-			if resetWorkspace != "" {
-				exists, err := workspaceExists(ctx, rootConfig, resetWorkspace)
+				if dumpConfig.KonnectRuntimeGroup == "" {
+					dumpConfig.KonnectRuntimeGroup = defaultRuntimeGroupName
+				}
+				target, err := fetchResetTarget(ctx, client, "", true)
 				if err != nil {
 					return err
 				}
-				if !exists {
-					return fmt.Errorf("workspace '%v' does not exist in Kong", resetWorkspace)
+				targets = append(targets, target)
+			} else {
+				rootClient, err := utils.GetKongClient(rootConfig)
+				if err != nil {
+					return err
 				}
 
-				workspaces = append(workspaces, resetWorkspace)
+				kongVersion, err := fetchKongVersion(ctx, rootConfig.ForWorkspace(resetWorkspace))
+				if err != nil {
+					return fmt.Errorf("reading Kong version: %w", err)
+				}
+				_ = sendAnalytics("reset", kongVersion, mode)
+
+				var workspaces []string
+				// Kong OSS or default workspace
+				if !resetAllWorkspaces && resetWorkspace == "" {
+					workspaces = append(workspaces, "")
+				}
+
+				// Kong Enterprise
+				if resetAllWorkspaces {
+					workspaces, err = listWorkspaces(ctx, rootClient)
+					if err != nil {
+						return err
+					}
+				}
+				if resetWorkspace != "" {
+					exists, err := workspaceExists(ctx, rootConfig, resetWorkspace)
+					if err != nil {
+						return err
+					}
+					if !exists {
+						return fmt.Errorf("workspace '%v' does not exist in Kong", resetWorkspace)
+					}
+
+					workspaces = append(workspaces, resetWorkspace)
+				}
+
+				for _, workspace := range workspaces {
+					wsClient, err := utils.GetKongClient(rootConfig.ForWorkspace(workspace))
+					if err != nil {
+						return err
+					}
+					target, err := fetchResetTarget(ctx, wsClient, workspace, false)
+					if err != nil {
+						return err
+					}
+					targets = append(targets, target)
+				}
+			}
+
+			count := 0
+			for _, t := range targets {
+				count += t.count
+			}
+			if count == 0 {
+				fmt.Println("Nothing to reset.")
+				return nil
 			}
 
-			for _, workspace := range workspaces {
-				wsClient, err := utils.GetKongClient(rootConfig.ForWorkspace(workspace))
+			if !resetCmdForce {
+				ok, err := utils.Confirm(resetConfirmationMessage(targets,
+					resetCmdEntityTypes, dumpConfig.SelectorTags))
 				if err != nil {
 					return err
 				}
-				currentState, err := fetchCurrentState(ctx, wsClient, dumpConfig)
-				if err != nil {
-					return err
+				if !ok {
+					return nil
 				}
+			}
+
+			for _, t := range targets {
 				targetState, err := state.NewKongState()
 				if err != nil {
 					return err
 				}
-				_, err = performDiff(ctx, currentState, targetState, false, 10, 0, wsClient, false)
+				_, _, err = performDiff(ctx, t.state, targetState, false, 10, 0, t.client, t.isKonnect)
 				if err != nil {
 					return err
 				}
@@ -120,6 +343,9 @@ By default, this command will ask for confirmation.`,
 		false, "reset configuration of all workspaces (Kong Enterprise only).")
 	resetCmd.Flags().BoolVar(&noMaskValues, "no-mask-deck-env-vars-value",
 		false, "do not mask DECK_ environment variable values at diff output.")
+	resetCmd.Flags().BoolVar(&noMask, "no-mask",
+		false, "do not mask secrets, such as credentials, private keys of certificates\n"+
+			"and encrypted plugin fields, nor DECK_ environment variable values at diff output.")
 	resetCmd.Flags().StringSliceVar(&dumpConfig.SelectorTags,
 		"select-tag", []string{},
 		"only entities matching tags specified via this flag are deleted.\n"+
@@ -128,6 +354,9 @@ By default, this command will ask for confirmation.`,
 		false, "reset only the RBAC resources (Kong Enterprise only).")
 	resetCmd.Flags().BoolVar(&dumpConfig.SkipCACerts, "skip-ca-certificates",
 		false, "do not reset CA certificates.")
+	resetCmd.Flags().StringSliceVar(&resetCmdEntityTypes, "entity-type", []string{},
+		"only entities of these types are deleted, e.g. service, route or plugin.\n"+
+			"This flag can be specified multiple times for multiple types.")
 
 	return resetCmd
 }
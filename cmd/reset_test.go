@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterRawStateEntityTypes(t *testing.T) {
+	raw := &utils.KongRawState{
+		Services:  []*kong.Service{{ID: kong.String("s1")}},
+		Routes:    []*kong.Route{{ID: kong.String("r1")}, {ID: kong.String("r2")}},
+		Consumers: []*kong.Consumer{{ID: kong.String("c1")}},
+	}
+	assert.Equal(t, 4, countRawStateEntities(raw))
+
+	filtered, err := filterRawStateEntityTypes(raw, nil)
+	require.NoError(t, err)
+	assert.Same(t, raw, filtered)
+
+	filtered, err = filterRawStateEntityTypes(raw, []string{"route", "consumer"})
+	require.NoError(t, err)
+	assert.Equal(t, &utils.KongRawState{Routes: raw.Routes, Consumers: raw.Consumers}, filtered)
+	assert.Equal(t, 3, countRawStateEntities(filtered))
+
+	// routes of services and their plugins are deleted with services
+	raw.Routes[0].Service = raw.Services[0]
+	raw.Plugins = []*kong.Plugin{
+		{ID: kong.String("p1"), Route: &kong.Route{ID: kong.String("r1")}},
+		{ID: kong.String("p2"), Route: &kong.Route{ID: kong.String("r2")}},
+		{ID: kong.String("p3")},
+	}
+	filtered, err = filterRawStateEntityTypes(raw, []string{"service"})
+	require.NoError(t, err)
+	assert.Equal(t, &utils.KongRawState{
+		Services: raw.Services,
+		Routes:   raw.Routes[:1],
+		Plugins:  raw.Plugins[:1],
+	}, filtered)
+	filtered, err = filterRawStateEntityTypes(raw, []string{"service", "route", "plugin"})
+	require.NoError(t, err)
+	assert.Equal(t, &utils.KongRawState{
+		Services: raw.Services,
+		Routes:   raw.Routes,
+		Plugins:  raw.Plugins,
+	}, filtered)
+
+	_, err = filterRawStateEntityTypes(raw, []string{"services"})
+	assert.ErrorContains(t, err, "invalid entity type 'services'")
+}
+
+func TestResetConfirmationMessage(t *testing.T) {
+	assert.Equal(t, "This will delete 3 entities from Kong's database"+
+		"\n  all entities will be deleted"+
+		"\n> Are you sure? ",
+		resetConfirmationMessage([]resetTarget{{count: 3}}, nil, nil))
+
+	assert.Equal(t, "This will delete 5 entities from Kong's database"+
+		"\n  workspaces: ws1 (2), ws2 (3)"+
+		"\n  entity types: route"+
+		"\n  select tags: team-a"+
+		"\n> Are you sure? ",
+		resetConfirmationMessage([]resetTarget{
+			{workspace: "ws1", count: 2},
+			{workspace: "ws2", count: 3},
+		}, []string{"route"}, []string{"team-a"}))
+}
+
+func TestResetEntityTypes(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	svc, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("svc"), Host: kong.String("example.com")})
+	require.NoError(t, err)
+	_, err = client.Routes.Create(ctx, &kong.Route{
+		Name: kong.String("route"), Paths: kong.StringSlice("/"), Service: svc,
+	})
+	require.NoError(t, err)
+	_, err = client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("alice")})
+	require.NoError(t, err)
+
+	defer func(config utils.KongClientConfig, analytics bool) {
+		rootConfig, disableAnalytics = config, analytics
+		resetCmdEntityTypes, resetCmdForce = nil, false
+	}(rootConfig, disableAnalytics)
+	rootConfig = utils.KongClientConfig{Address: server.URL}
+	disableAnalytics = true
+
+	cmd := newResetCmd()
+	cmd.SetArgs([]string{"--entity-type", "consumer", "--force"})
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	consumers, err := client.Consumers.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, consumers)
+	services, err := client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, services, 1)
+	routes, err := client.Routes.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, routes, 1)
+
+	// the routes of services are deleted with them
+	cmd = newResetCmd()
+	cmd.SetArgs([]string{"--entity-type", "service", "--force"})
+	require.NoError(t, cmd.ExecuteContext(ctx))
+
+	services, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services)
+	routes, err = client.Routes.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, routes)
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/kong/deck/cprint"
+	"github.com/kong/deck/state"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+// syncCmdRollbackOnFailure restores Kong to its state before a sync
+// if the sync fails, without asking for confirmation.
+var syncCmdRollbackOnFailure bool
+
+// stdinIsTerminal returns whether the user can be prompted.
+// It is a variable for testing purpose.
+var stdinIsTerminal = func() bool {
+	fi, err := os.Stdin.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// handleSyncFailure handles syncErr, the failure of a sync which may have
+// applied some of its changes. If --rollback-on-failure is set or the user
+// confirms it, Kong is restored to snapshot, its state before the sync.
+// The returned error always wraps syncErr.
+func handleSyncFailure(ctx context.Context, client *kong.Client, snapshot *state.KongState,
+	syncErr error, parallelism int, isKonnect bool,
+) error {
+	rollback := syncCmdRollbackOnFailure
+	if !rollback && !assumeYes && stdinIsTerminal() {
+		cprint.DeletePrintln("Error: sync failed:", syncErr)
+		ok, err := utils.Confirm("Kong may have been partially updated.\n" +
+			"> Roll back the changes applied by the sync? ")
+		rollback = err == nil && ok
+	}
+	if !rollback {
+		return syncErr
+	}
+
+	cprint.UpdatePrintln("Rolling back the changes applied by the sync")
+	currentState, err := fetchCurrentState(ctx, client, dumpConfig)
+	if err != nil {
+		return fmt.Errorf("%w\nrolling back failed, Kong may be partially updated: "+
+			"reading current state: %v", syncErr, err)
+	}
+	if _, _, err := performDiff(ctx, currentState, snapshot, false, parallelism, 0, client, isKonnect); err != nil {
+		return fmt.Errorf("%w\nrolling back failed, Kong may be partially updated: %v", syncErr, err)
+	}
+	return fmt.Errorf("%w\nthe changes applied by the sync were rolled back", syncErr)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleSyncFailure(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	svc1, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("svc1"), Host: kong.String("example.com")})
+	require.NoError(t, err)
+	snapshot, err := fetchCurrentState(ctx, client, dumpConfig)
+	require.NoError(t, err)
+
+	// changes applied by a sync before failing
+	_, err = client.Services.Update(ctx, &kong.Service{ID: svc1.ID, Host: kong.String("changed.com")})
+	require.NoError(t, err)
+	_, err = client.Services.Create(ctx, &kong.Service{Name: kong.String("svc2"), Host: kong.String("example.com")})
+	require.NoError(t, err)
+
+	defer func(isTerminal func() bool) {
+		stdinIsTerminal = isTerminal
+		syncCmdRollbackOnFailure = false
+	}(stdinIsTerminal)
+	stdinIsTerminal = func() bool { return false }
+	syncErr := errors.New("creating route failed")
+
+	// without --rollback-on-failure, nor a terminal to ask for confirmation
+	err = handleSyncFailure(ctx, client, snapshot, syncErr, 1, false)
+	assert.Equal(t, syncErr, err)
+	services, err := client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, services, 2)
+
+	syncCmdRollbackOnFailure = true
+	err = handleSyncFailure(ctx, client, snapshot, syncErr, 1, false)
+	assert.ErrorIs(t, err, syncErr)
+	assert.ErrorContains(t, err, "rolled back")
+	services, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	assert.Equal(t, "svc1", *services[0].Name)
+	assert.Equal(t, "example.com", *services[0].Host)
+}
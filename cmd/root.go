@@ -4,9 +4,9 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
-	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/kong/deck/utils"
@@ -43,10 +43,8 @@ configuration file.
 It can be used to export, import, or sync entities to Kong.`,
 		SilenceUsage: true,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
-			if _, err := url.ParseRequestURI(rootConfig.Address); err != nil {
-				return fmt.Errorf("invalid URL: %w", err)
-			}
-			return nil
+			_, err := utils.ParseAddress(rootConfig.Address)
+			return err
 		},
 	}
 	cobra.OnInitialize(initConfig)
@@ -56,6 +54,7 @@ It can be used to export, import, or sync entities to Kong.`,
 
 	rootCmd.PersistentFlags().String("kong-addr", defaultKongURL,
 		"HTTP address of Kong's Admin API.\n"+
+			"Use unix:///path/to/admin.sock to connect over a unix domain socket.\n"+
 			"This value can also be set using the environment variable DECK_KONG_ADDR\n"+
 			" environment variable.")
 	viper.BindPFlag("kong-addr",
@@ -101,11 +100,17 @@ It can be used to export, import, or sync entities to Kong.`,
 
 	rootCmd.PersistentFlags().Int("verbose", 0,
 		"Enable verbose logging levels\n"+
-			"Setting this value to 2 outputs all HTTP requests/responses\n"+
-			"between decK and Kong.")
+			"Setting this value to 1 outputs the headers of all HTTP requests/responses\n"+
+			"between decK and Kong, and 2 outputs their bodies as well.")
 	viper.BindPFlag("verbose",
 		rootCmd.PersistentFlags().Lookup("verbose"))
 
+	rootCmd.PersistentFlags().Int("verbose-sample-rate", 0,
+		"Log only 1 in N HTTP requests/responses when --verbose is set.\n"+
+			"0 or 1 logs all of them.")
+	viper.BindPFlag("verbose-sample-rate",
+		rootCmd.PersistentFlags().Lookup("verbose-sample-rate"))
+
 	rootCmd.PersistentFlags().Bool("no-color", false,
 		"Disable colorized output")
 	viper.BindPFlag("no-color",
@@ -134,6 +139,43 @@ It can be used to export, import, or sync entities to Kong.`,
 	viper.BindPFlag("timeout",
 		rootCmd.PersistentFlags().Lookup("timeout"))
 
+	rootCmd.PersistentFlags().Float64("rate-limit-qps", 0,
+		"Maximum number of requests per second sent to Kong's Admin API or Konnect.\n"+
+			"The limit is shared by all concurrent operations. Defaults to no limit.")
+	viper.BindPFlag("rate-limit-qps",
+		rootCmd.PersistentFlags().Lookup("rate-limit-qps"))
+
+	rootCmd.PersistentFlags().Int("rate-limit-burst", 1,
+		"Maximum number of requests sent at once above `--rate-limit-qps`.")
+	viper.BindPFlag("rate-limit-burst",
+		rootCmd.PersistentFlags().Lookup("rate-limit-burst"))
+
+	rootCmd.PersistentFlags().Int("circuit-breaker-threshold", 0,
+		"Number of consecutive failures of Kong's Admin API after which requests\n"+
+			"fail fast for `--circuit-breaker-cooldown`. Defaults to no circuit breaker.")
+	viper.BindPFlag("circuit-breaker-threshold",
+		rootCmd.PersistentFlags().Lookup("circuit-breaker-threshold"))
+
+	rootCmd.PersistentFlags().Duration("circuit-breaker-cooldown", 30*time.Second,
+		"Duration for which requests fail fast once the circuit breaker opens.")
+	viper.BindPFlag("circuit-breaker-cooldown",
+		rootCmd.PersistentFlags().Lookup("circuit-breaker-cooldown"))
+
+	rootCmd.PersistentFlags().String("proxy-url", "",
+		"Proxy to reach Kong's Admin API through, e.g. socks5://bastion:1080.\n"+
+			"Defaults to the proxy set with the HTTP_PROXY, HTTPS_PROXY and NO_PROXY\n"+
+			"environment variables.")
+	viper.BindPFlag("proxy-url",
+		rootCmd.PersistentFlags().Lookup("proxy-url"))
+
+	rootCmd.PersistentFlags().Int("compression-min-size", 0,
+		"Compress request bodies of at least this many bytes with gzip,\n"+
+			"e.g. large configurations sent to Kong in DB-less mode.\n"+
+			"Kong's Admin API, or a proxy in front of it, must accept gzipped requests.\n"+
+			"Defaults to no compression.")
+	viper.BindPFlag("compression-min-size",
+		rootCmd.PersistentFlags().Lookup("compression-min-size"))
+
 	rootCmd.PersistentFlags().String("tls-client-cert", "",
 		"PEM-encoded TLS client certificate to use for authentication with Kong's Admin API.\n"+
 			"This value can also be set using DECK_TLS_CLIENT_CERT "+
@@ -203,6 +245,16 @@ It can be used to export, import, or sync entities to Kong.`,
 	viper.BindPFlag("konnect-runtime-group-name",
 		rootCmd.PersistentFlags().Lookup("konnect-runtime-group-name"))
 
+	rootCmd.PersistentFlags().String("konnect-control-plane-name", "",
+		"Konnect Control Plane name.\n"+
+			"Control Planes were previously known as Runtime groups, "+
+			"this is an alias of `--konnect-runtime-group-name`.")
+	viper.BindPFlag("konnect-control-plane-name",
+		rootCmd.PersistentFlags().Lookup("konnect-control-plane-name"))
+
+	// user must select the control plane with at most one flag
+	rootCmd.MarkFlagsMutuallyExclusive("konnect-runtime-group-name", "konnect-control-plane-name")
+
 	rootCmd.AddCommand(newSyncCmd())
 	rootCmd.AddCommand(newVersionCmd())
 	rootCmd.AddCommand(newValidateCmd())
@@ -210,7 +262,9 @@ It can be used to export, import, or sync entities to Kong.`,
 	rootCmd.AddCommand(newPingCmd())
 	rootCmd.AddCommand(newDumpCmd())
 	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.AddCommand(newDriftCmd())
 	rootCmd.AddCommand(newConvertCmd())
+	rootCmd.AddCommand(newFileCmd())
 	rootCmd.AddCommand(newCompletionCmd())
 	rootCmd.AddCommand(newKonnectCmd())
 	return rootCmd
@@ -277,8 +331,16 @@ func initConfig() {
 	rootConfig.TLSCACert = caCertContent
 	rootConfig.Headers = extendHeaders(viper.GetStringSlice("headers"))
 	rootConfig.SkipWorkspaceCrud = viper.GetBool("skip-workspace-crud")
-	rootConfig.Debug = (viper.GetInt("verbose") >= 1)
+	rootConfig.DebugLevel = debugLevel(viper.GetInt("verbose"))
+	rootConfig.DebugSampleRate = viper.GetInt("verbose-sample-rate")
 	rootConfig.Timeout = (viper.GetInt("timeout"))
+	rootConfig.RateLimiter = utils.NewRateLimiter(viper.GetFloat64("rate-limit-qps"),
+		viper.GetInt("rate-limit-burst"))
+	rootConfig.CircuitBreaker = utils.NewCircuitBreaker(viper.GetInt("circuit-breaker-threshold"),
+		viper.GetDuration("circuit-breaker-cooldown"))
+
+	rootConfig.CompressionMinSize = viper.GetInt("compression-min-size")
+	rootConfig.Transport.ProxyURL = viper.GetString("proxy-url")
 
 	clientCertContent := viper.GetString("tls-client-cert")
 
@@ -367,13 +429,31 @@ func initKonnectConfig() error {
 	konnectConfig.Email = viper.GetString("konnect-email")
 	konnectConfig.Password = password
 	konnectConfig.Token = token
-	konnectConfig.Debug = (viper.GetInt("verbose") >= 1)
+	konnectConfig.DebugLevel = debugLevel(viper.GetInt("verbose"))
+	konnectConfig.DebugSampleRate = viper.GetInt("verbose-sample-rate")
 	konnectConfig.Address = viper.GetString("konnect-addr")
 	konnectConfig.Headers = extendHeaders(viper.GetStringSlice("headers"))
+	konnectConfig.RateLimiter = rootConfig.RateLimiter
 	konnectRuntimeGroup = viper.GetString("konnect-runtime-group-name")
+	if controlPlane := viper.GetString("konnect-control-plane-name"); controlPlane != "" {
+		konnectRuntimeGroup = controlPlane
+	}
 	return nil
 }
 
+// debugLevel returns the logging of HTTP requests/responses
+// for the verbosity set with --verbose.
+func debugLevel(verbosity int) utils.DebugLevel {
+	switch {
+	case verbosity >= 2:
+		return utils.DebugLevelFullBody
+	case verbosity == 1:
+		return utils.DebugLevelHeaders
+	default:
+		return utils.DebugLevelNone
+	}
+}
+
 func extendHeaders(headers []string) []string {
 	userAgentHeader := fmt.Sprintf("User-Agent:decK/%s", VERSION)
 	headers = append(headers, userAgentHeader)
@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kong/deck/state"
+	"github.com/kong/go-kong/kong"
+)
+
+// foreignEntity is an entity present in Kong which does not carry
+// the select tags, and thus is managed by someone else.
+type foreignEntity struct {
+	kind string
+	name string
+	tags []*string
+}
+
+func (e foreignEntity) String() string {
+	tags := make([]string, 0, len(e.tags))
+	for _, tag := range e.tags {
+		tags = append(tags, *tag)
+	}
+	return fmt.Sprintf("%s %s (tags: [%s])", e.kind, e.name, strings.Join(tags, ", "))
+}
+
+// lookupFunc fetches an entity from Kong by name and returns its tags.
+type lookupFunc func(ctx context.Context, name string) ([]*string, error)
+
+// findForeign looks up in Kong the entities of the target state, by name,
+// that are not part of the current state. With select tags, the current
+// state holds only the tagged entities: any of them found in Kong is
+// managed outside of the select tags and would conflict once created.
+func findForeign(ctx context.Context, kind string, names []string,
+	inCurrentState func(name string) bool, lookup lookupFunc,
+) ([]foreignEntity, error) {
+	var res []foreignEntity
+	for _, name := range names {
+		if name == "" || inCurrentState(name) {
+			continue
+		}
+		tags, err := lookup(ctx, name)
+		if err != nil {
+			if kong.IsNotFoundErr(err) {
+				continue
+			}
+			return nil, fmt.Errorf("looking up %s %s: %w", kind, name, err)
+		}
+		res = append(res, foreignEntity{kind: kind, name: name, tags: tags})
+	}
+	return res, nil
+}
+
+// checkSelectTagsOwnership returns an error if entities of targetState
+// to be created already exist in Kong without the select tags.
+// This prevents teams sharing a Kong cluster through select tags from
+// taking over each other's entities.
+func checkSelectTagsOwnership(ctx context.Context, client *kong.Client,
+	currentState, targetState *state.KongState, selectTags []string,
+) error {
+	var foreign []foreignEntity
+	add := func(entities []foreignEntity, err error) error {
+		foreign = append(foreign, entities...)
+		return err
+	}
+
+	services, err := targetState.Services.GetAll()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, s := range services {
+		names = append(names, stringValue(s.Name))
+	}
+	if err := add(findForeign(ctx, "service", names,
+		func(name string) bool {
+			_, err := currentState.Services.Get(name)
+			return err == nil
+		},
+		func(ctx context.Context, name string) ([]*string, error) {
+			s, err := client.Services.Get(ctx, kong.String(name))
+			if err != nil {
+				return nil, err
+			}
+			return s.Tags, nil
+		})); err != nil {
+		return err
+	}
+
+	routes, err := targetState.Routes.GetAll()
+	if err != nil {
+		return err
+	}
+	names = nil
+	for _, r := range routes {
+		names = append(names, stringValue(r.Name))
+	}
+	if err := add(findForeign(ctx, "route", names,
+		func(name string) bool {
+			_, err := currentState.Routes.Get(name)
+			return err == nil
+		},
+		func(ctx context.Context, name string) ([]*string, error) {
+			r, err := client.Routes.Get(ctx, kong.String(name))
+			if err != nil {
+				return nil, err
+			}
+			return r.Tags, nil
+		})); err != nil {
+		return err
+	}
+
+	consumers, err := targetState.Consumers.GetAll()
+	if err != nil {
+		return err
+	}
+	names = nil
+	for _, c := range consumers {
+		names = append(names, stringValue(c.Username))
+	}
+	if err := add(findForeign(ctx, "consumer", names,
+		func(name string) bool {
+			_, err := currentState.Consumers.Get(name)
+			return err == nil
+		},
+		func(ctx context.Context, name string) ([]*string, error) {
+			c, err := client.Consumers.Get(ctx, kong.String(name))
+			if err != nil {
+				return nil, err
+			}
+			return c.Tags, nil
+		})); err != nil {
+		return err
+	}
+
+	upstreams, err := targetState.Upstreams.GetAll()
+	if err != nil {
+		return err
+	}
+	names = nil
+	for _, u := range upstreams {
+		names = append(names, stringValue(u.Name))
+	}
+	if err := add(findForeign(ctx, "upstream", names,
+		func(name string) bool {
+			_, err := currentState.Upstreams.Get(name)
+			return err == nil
+		},
+		func(ctx context.Context, name string) ([]*string, error) {
+			u, err := client.Upstreams.Get(ctx, kong.String(name))
+			if err != nil {
+				return nil, err
+			}
+			return u.Tags, nil
+		})); err != nil {
+		return err
+	}
+
+	if len(foreign) == 0 {
+		return nil
+	}
+	lines := make([]string, 0, len(foreign))
+	for _, e := range foreign {
+		lines = append(lines, "  "+e.String())
+	}
+	return fmt.Errorf("the following entities already exist in Kong "+
+		"but do not carry the select tags %v, they are managed by another configuration:\n%s",
+		selectTags, strings.Join(lines, "\n"))
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
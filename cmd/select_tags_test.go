@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/deck/state"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSelectTagsOwnership(t *testing.T) {
+	server := kongtest.NewServer()
+	defer server.Close()
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.Services.Create(ctx, &kong.Service{
+		Name: kong.String("shared"), Host: kong.String("example.com"),
+		Tags: kong.StringSlice("team-b"),
+	})
+	require.NoError(t, err)
+	_, err = client.Services.Create(ctx, &kong.Service{
+		Name: kong.String("owned"), Host: kong.String("example.com"),
+		Tags: kong.StringSlice("team-a"),
+	})
+	require.NoError(t, err)
+	_, err = client.Consumers.Create(ctx, &kong.Consumer{
+		Username: kong.String("alice"), Tags: kong.StringSlice("team-b"),
+	})
+	require.NoError(t, err)
+
+	// the current state holds the entities tagged with team-a
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.Services.Add(state.Service{Service: kong.Service{
+		ID: kong.String("s1"), Name: kong.String("owned"), Tags: kong.StringSlice("team-a"),
+	}}))
+
+	targetState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, targetState.Services.Add(state.Service{Service: kong.Service{
+		ID: kong.String("s1"), Name: kong.String("owned"), Tags: kong.StringSlice("team-a"),
+	}}))
+	require.NoError(t, targetState.Services.Add(state.Service{Service: kong.Service{
+		ID: kong.String("s2"), Name: kong.String("new"), Tags: kong.StringSlice("team-a"),
+	}}))
+	require.NoError(t, checkSelectTagsOwnership(ctx, client, currentState, targetState,
+		[]string{"team-a"}))
+
+	require.NoError(t, targetState.Services.Add(state.Service{Service: kong.Service{
+		ID: kong.String("s3"), Name: kong.String("shared"), Tags: kong.StringSlice("team-a"),
+	}}))
+	require.NoError(t, targetState.Consumers.Add(state.Consumer{Consumer: kong.Consumer{
+		ID: kong.String("c1"), Username: kong.String("alice"), Tags: kong.StringSlice("team-a"),
+	}}))
+	err = checkSelectTagsOwnership(ctx, client, currentState, targetState, []string{"team-a"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service shared (tags: [team-b])")
+	assert.Contains(t, err.Error(), "consumer alice (tags: [team-b])")
+	assert.NotContains(t, err.Error(), "service new")
+}
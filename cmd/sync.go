@@ -10,6 +10,8 @@ var (
 	syncCmdParallelism   int
 	syncCmdDBUpdateDelay int
 	syncWorkspace        string
+	syncCmdAllWorkspaces bool
+	syncCmdPlanFile      string
 )
 
 // newSyncCmd represents the sync command
@@ -20,9 +22,32 @@ func newSyncCmd() *cobra.Command {
 		Short: "Sync performs operations to get Kong's configuration " +
 			"to match the state file",
 		Long: `The sync command reads the state file and performs operation on Kong
-to get Kong's state in sync with the input state.`,
+to get Kong's state in sync with the input state.
+
+If the sync fails after applying some of the changes, the command offers
+to roll them back, restoring Kong to its state before the sync.
+With --rollback-on-failure, the changes are rolled back without asking.`,
 		Args: validateNoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if syncCmdPlanFile != "" {
+				if cmd.Flags().Changed("state") || syncCmdAllWorkspaces || syncWorkspace != "" {
+					return fmt.Errorf("--plan cannot be used with --state, --workspace or --all-workspaces")
+				}
+				p, err := readPlan(syncCmdPlanFile)
+				if err != nil {
+					return err
+				}
+				syncCmdPlan = p
+				if len(dumpConfig.SelectorTags) == 0 {
+					dumpConfig.SelectorTags = p.SelectTags
+				}
+				return syncMain(cmd.Context(), nil, false,
+					syncCmdParallelism, syncCmdDBUpdateDelay, p.Workspace)
+			}
+			if syncCmdAllWorkspaces {
+				return syncAllWorkspaces(cmd.Context(), syncCmdKongStateFile, false,
+					syncCmdParallelism, syncCmdDBUpdateDelay)
+			}
 			return syncMain(cmd.Context(), syncCmdKongStateFile, false,
 				syncCmdParallelism, syncCmdDBUpdateDelay, syncWorkspace)
 		},
@@ -38,18 +63,47 @@ to get Kong's state in sync with the input state.`,
 	syncCmd.Flags().StringSliceVarP(&syncCmdKongStateFile,
 		"state", "s", []string{"kong.yaml"}, "file(s) containing Kong's configuration.\n"+
 			"This flag can be specified multiple times for multiple files.\n"+
+			"Directories are read recursively for YAML and JSON files.\n"+
 			"Use `-` to read from stdin.")
 	syncCmd.Flags().StringVarP(&syncWorkspace, "workspace", "w", "",
 		"Sync configuration to a specific workspace "+
 			"(Kong Enterprise only).\n"+
 			"This takes precedence over _workspace fields in state files.")
+	syncCmd.Flags().BoolVar(&syncCmdAllWorkspaces, "all-workspaces", false,
+		"Sync each workspace with the state files holding its _workspace field, "+
+			"creating missing workspaces (Kong Enterprise only).\n"+
+			"State files without _workspace are synced to the default workspace.")
+	syncCmd.MarkFlagsMutuallyExclusive("workspace", "all-workspaces")
+	syncCmd.Flags().StringVar(&syncCmdPlanFile, "plan", "",
+		"apply a plan saved by the diff command with --plan-out, instead of the state files.\n"+
+			"The plan is not applied if the state of Kong changed since it was computed.")
+	syncCmd.Flags().BoolVar(&syncCmdRollbackOnFailure, "rollback-on-failure", false,
+		"if the sync fails, restore the entities to their state before the sync,\n"+
+			"without asking for confirmation. Secrets hashed by Kong, like the\n"+
+			"passwords of basic-auth credentials, cannot be restored for deleted entities.")
+	syncCmd.Flags().StringArrayVar(&syncCmdPreSyncHooks, "pre-sync-hook", []string{},
+		"shell command run before applying changes, with a JSON payload holding the\n"+
+			"planned changes on its standard input. The sync is aborted if it fails.\n"+
+			"This flag can be specified multiple times for multiple commands.")
+	syncCmd.Flags().StringArrayVar(&syncCmdPostSyncHooks, "post-sync-hook", []string{},
+		"shell command run after applying changes, with a JSON payload holding the\n"+
+			"applied changes and the error of the sync, if any, on its standard input.\n"+
+			"This flag can be specified multiple times for multiple commands.")
+	syncCmd.Flags().StringSliceVar(&syncCmdWebhookURLs, "webhook-url", []string{},
+		"URL to POST the payloads of pre-sync and post-sync hooks to, as JSON.\n"+
+			"The text field of payloads is displayed by Slack and Microsoft Teams.")
 	syncCmd.Flags().BoolVar(&dumpConfig.SkipConsumers, "skip-consumers",
 		false, "do not sync consumers, consumer-groups or "+
 			"any plugins associated with them.")
 	syncCmd.Flags().IntVar(&syncCmdParallelism, "parallelism",
-		10, "Maximum number of concurrent operations.")
+		10, "Maximum number of concurrent operations.\n"+
+			"Entities depending on each other, like services and their routes,\n"+
+			"are still processed in order.")
 	syncCmd.Flags().BoolVar(&noMaskValues, "no-mask-deck-env-vars-value",
 		false, "do not mask DECK_ environment variable values at diff output.")
+	syncCmd.Flags().BoolVar(&noMask, "no-mask",
+		false, "do not mask secrets, such as credentials, private keys of certificates\n"+
+			"and encrypted plugin fields, nor DECK_ environment variable values at diff output.")
 	syncCmd.Flags().StringSliceVar(&dumpConfig.SelectorTags,
 		"select-tag", []string{},
 		"only entities matching tags specified via this flag are synced.\n"+
@@ -62,6 +116,11 @@ to get Kong's state in sync with the input state.`,
 			"See `db_update_propagation` in kong.conf.")
 	syncCmd.Flags().BoolVar(&dumpConfig.SkipCACerts, "skip-ca-certificates",
 		false, "do not sync CA certificates.")
+	syncCmd.Flags().StringVar(&unsupportedFields, "unsupported-fields",
+		"", "how to handle fields not supported by the Kong version in use:\n"+
+			"'strip' removes them, 'reject' fails with an error.\n"+
+			"By default, all fields are sent to Kong as is.")
+	addLastAppliedFileFlag(syncCmd.Flags())
 	addSilenceEventsFlag(syncCmd.Flags())
 	return syncCmd
 }
@@ -1,13 +1,20 @@
 package cmd
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
 	"github.com/fatih/color"
 	"github.com/kong/deck/cprint"
 	"github.com/kong/deck/diff"
 	"github.com/spf13/pflag"
 )
 
-func printStats(stats diff.Stats) {
+// printStats prints the number of operations of a diff or sync, in total
+// and per entity type if changes were recorded.
+func printStats(stats diff.Stats, changes []diff.EntityChange) {
 	// do not use github.com/kong/deck/print because that package
 	// is used only for events logs
 	printFn := color.New(color.FgGreen, color.Bold).PrintfFunc()
@@ -15,6 +22,109 @@ func printStats(stats diff.Stats) {
 	printFn("  Created: %d\n", stats.CreateOps.Count())
 	printFn("  Updated: %d\n", stats.UpdateOps.Count())
 	printFn("  Deleted: %d\n", stats.DeleteOps.Count())
+	byKind := summarizeChanges(changes).ByKind
+	if len(byKind) == 0 {
+		return
+	}
+	kinds := make([]string, 0, len(byKind))
+	for kind := range byKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	printFn("  By entity type:\n")
+	for _, kind := range kinds {
+		c := byKind[kind]
+		printFn("    %s: %d created, %d updated, %d deleted\n", kind, c.Created, c.Updated, c.Deleted)
+	}
+}
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputFormatText, outputFormatJSON:
+		return nil
+	default:
+		return fmt.Errorf("invalid output format '%s', must be one of: %s, %s",
+			format, outputFormatText, outputFormatJSON)
+	}
+}
+
+type jsonDiffCounts struct {
+	Created int32 `json:"created"`
+	Updated int32 `json:"updated"`
+	Deleted int32 `json:"deleted"`
+}
+
+func (c *jsonDiffCounts) add(action string) {
+	switch action {
+	case diff.ActionCreate:
+		c.Created++
+	case diff.ActionUpdate:
+		c.Updated++
+	case diff.ActionDelete:
+		c.Deleted++
+	}
+}
+
+type jsonDiffSummary struct {
+	Created int32 `json:"created"`
+	Updated int32 `json:"updated"`
+	Deleted int32 `json:"deleted"`
+	// ByKind counts the changes per entity type, e.g. "service".
+	ByKind map[string]jsonDiffCounts `json:"by_kind,omitempty"`
+}
+
+// summarizeChanges counts changes by action, in total and per entity type.
+func summarizeChanges(changes []diff.EntityChange) jsonDiffSummary {
+	var total jsonDiffCounts
+	var byKind map[string]jsonDiffCounts
+	for _, c := range changes {
+		total.add(c.Action)
+		if byKind == nil {
+			byKind = map[string]jsonDiffCounts{}
+		}
+		counts := byKind[c.Kind]
+		counts.add(c.Action)
+		byKind[c.Kind] = counts
+	}
+	return jsonDiffSummary{
+		Created: total.Created,
+		Updated: total.Updated,
+		Deleted: total.Deleted,
+		ByKind:  byKind,
+	}
+}
+
+type jsonDiffOutput struct {
+	Changes []diff.EntityChange `json:"changes"`
+	Summary jsonDiffSummary     `json:"summary"`
+	Errors  []string            `json:"errors,omitempty"`
+}
+
+// printJSONDiff writes the changes and stats of a diff as JSON to w.
+func printJSONDiff(w io.Writer, changes []diff.EntityChange, stats diff.Stats, errs []error) error {
+	output := jsonDiffOutput{
+		Changes: changes,
+		Summary: jsonDiffSummary{
+			Created: stats.CreateOps.Count(),
+			Updated: stats.UpdateOps.Count(),
+			Deleted: stats.DeleteOps.Count(),
+			ByKind:  summarizeChanges(changes).ByKind,
+		},
+	}
+	if output.Changes == nil {
+		output.Changes = []diff.EntityChange{}
+	}
+	for _, err := range errs {
+		output.Errors = append(output.Errors, err.Error())
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
 }
 
 var silenceEvents bool
@@ -26,6 +136,15 @@ func preRunSilenceEventsFlag() error {
 	return nil
 }
 
+func addLastAppliedFileFlag(set *pflag.FlagSet) {
+	set.StringVar(&lastAppliedFile, "last-applied-file", "",
+		"file recording the fields set in state files by the last sync, to perform\n"+
+			"a three-way merge: fields of services, routes, upstreams, consumers and\n"+
+			"plugins never set in state files keep their values in Kong instead of\n"+
+			"being reset to their defaults, while fields removed from state files\n"+
+			"since the last sync are reset. The file is updated by sync.")
+}
+
 func addSilenceEventsFlag(set *pflag.FlagSet) {
 	set.BoolVar(&silenceEvents, "silence-events", false,
 		"disable printing events to stdout")
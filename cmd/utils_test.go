@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrintJSONDiff(t *testing.T) {
+	stats := diff.Stats{
+		CreateOps: &utils.AtomicInt32Counter{},
+		UpdateOps: &utils.AtomicInt32Counter{},
+		DeleteOps: &utils.AtomicInt32Counter{},
+	}
+	stats.UpdateOps.Increment(1)
+
+	var buf bytes.Buffer
+	require.NoError(t, printJSONDiff(&buf, []diff.EntityChange{{
+		Action: diff.ActionUpdate,
+		Kind:   "service",
+		Name:   "svc",
+		ID:     "s1",
+		Changes: []diff.FieldChange{
+			{Field: "host", Old: "a.example.com", New: "b.example.com"},
+		},
+	}}, stats, []error{errors.New("boom")}))
+	assert.JSONEq(t, `{
+		"changes": [{
+			"action": "update", "kind": "service", "name": "svc", "id": "s1",
+			"changes": [{"field": "host", "old": "a.example.com", "new": "b.example.com"}]
+		}],
+		"summary": {
+			"created": 0, "updated": 1, "deleted": 0,
+			"by_kind": {"service": {"created": 0, "updated": 1, "deleted": 0}}
+		},
+		"errors": ["boom"]
+	}`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, printJSONDiff(&buf, nil, diff.Stats{
+		CreateOps: &utils.AtomicInt32Counter{},
+		UpdateOps: &utils.AtomicInt32Counter{},
+		DeleteOps: &utils.AtomicInt32Counter{},
+	}, nil))
+	assert.JSONEq(t, `{"changes": [], "summary": {"created": 0, "updated": 0, "deleted": 0}}`, buf.String())
+}
+
+func TestSummarizeChanges(t *testing.T) {
+	summary := summarizeChanges([]diff.EntityChange{
+		{Action: diff.ActionCreate, Kind: "service", Name: "svc1"},
+		{Action: diff.ActionCreate, Kind: "route", Name: "r1"},
+		{Action: diff.ActionCreate, Kind: "route", Name: "r2"},
+		{Action: diff.ActionDelete, Kind: "route", Name: "r3"},
+		{Action: diff.ActionUpdate, Kind: "plugin", Name: "key-auth"},
+	})
+	assert.Equal(t, jsonDiffSummary{
+		Created: 3,
+		Updated: 1,
+		Deleted: 1,
+		ByKind: map[string]jsonDiffCounts{
+			"service": {Created: 1},
+			"route":   {Created: 2, Deleted: 1},
+			"plugin":  {Updated: 1},
+		},
+	}, summary)
+	assert.Equal(t, jsonDiffSummary{}, summarizeChanges(nil))
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	assert.NoError(t, validateOutputFormat("text"))
+	assert.NoError(t, validateOutputFormat("json"))
+	assert.Error(t, validateOutputFormat("yaml"))
+}
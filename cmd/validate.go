@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/blang/semver/v4"
 	"github.com/kong/deck/dump"
+	"github.com/kong/deck/engine"
 	"github.com/kong/deck/file"
 	"github.com/kong/deck/state"
 	"github.com/kong/deck/utils"
@@ -19,6 +21,7 @@ var (
 	validateOnline               bool
 	validateWorkspace            string
 	validateParallelism          int
+	validateKongVersion          string
 )
 
 // newValidateCmd represents the diff command
@@ -33,6 +36,14 @@ and alerts if there are broken relationships, or missing links present.
 
 No communication takes places between decK and Kong during the execution of
 this command unless --online flag is used.
+
+With --kong-version, the state file is also checked against the given
+Kong version without contacting Kong: the format version of the file,
+entities and entity fields which this version does not support, and
+bundled plugins which it does not ship are reported. No schema is bundled:
+unknown fields, invalid values and plugin configurations are not checked,
+use --online for that. This is useful to validate state files in CI before
+upgrading or downgrading Kong.
 `,
 		Args: validateNoArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -52,6 +63,16 @@ this command unless --online flag is used.
 			if err != nil {
 				return err
 			}
+			var kongVersion semver.Version
+			if validateKongVersion != "" {
+				kongVersion, err = utils.ParseKongVersion(validateKongVersion)
+				if err != nil {
+					return fmt.Errorf("parsing Kong version: %w", err)
+				}
+				if err := engine.CheckFormatVersion(targetContent, kongVersion); err != nil {
+					return err
+				}
+			}
 			ctx := cmd.Context()
 			var kongClient *kong.Client
 			if validateOnline {
@@ -63,6 +84,7 @@ this command unless --online flag is used.
 
 			rawState, err := file.Get(ctx, targetContent, file.RenderConfig{
 				CurrentState: dummyEmptyState,
+				KongVersion:  kongVersion,
 			}, dump.Config{}, kongClient)
 			if err != nil {
 				return err
@@ -70,6 +92,11 @@ this command unless --online flag is used.
 			if err := checkForRBACResources(*rawState, validateCmdRBACResourcesOnly); err != nil {
 				return err
 			}
+			if validateKongVersion != "" {
+				if errs := validate.ValidateForKongVersion(rawState, kongVersion); len(errs) != 0 {
+					return validate.ErrorsWrapper{Errors: errs}
+				}
+			}
 			// this catches foreign relation errors
 			ks, err := state.Get(rawState)
 			if err != nil {
@@ -96,6 +123,7 @@ this command unless --online flag is used.
 	validateCmd.Flags().StringSliceVarP(&validateCmdKongStateFile,
 		"state", "s", []string{"kong.yaml"}, "file(s) containing Kong's configuration.\n"+
 			"This flag can be specified multiple times for multiple files.\n"+
+			"Directories are read recursively for YAML and JSON files.\n"+
 			"Use '-' to read from stdin.")
 	validateCmd.Flags().BoolVar(&validateOnline, "online",
 		false, "perform validations against Kong API. When this flag is used, validation is done\n"+
@@ -107,6 +135,12 @@ this command unless --online flag is used.
 			"This takes precedence over _workspace fields in state files.")
 	validateCmd.Flags().IntVar(&validateParallelism, "parallelism",
 		10, "Maximum number of concurrent requests to Kong.")
+	validateCmd.Flags().StringVar(&validateKongVersion, "kong-version",
+		"", "validate the state file against this Kong version, without contacting Kong.\n"+
+			"The format version, entities, entity fields and bundled plugins not supported\n"+
+			"by this version are reported. No schema is bundled: unknown fields, invalid values\n"+
+			"and plugin configurations are not checked.")
+	validateCmd.MarkFlagsMutuallyExclusive("online", "kong-version")
 
 	if err := ensureGetAllMethods(); err != nil {
 		panic(err.Error())
@@ -0,0 +1,194 @@
+package diff
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sort"
+	"sync"
+
+	"github.com/kong/deck/crud"
+	"github.com/kong/deck/state"
+)
+
+// Actions of an EntityChange.
+const (
+	ActionCreate = "create"
+	ActionUpdate = "update"
+	ActionDelete = "delete"
+)
+
+// FieldChange is the change of a single field of an entity.
+// Field is the path of the field, with nested fields separated by dots.
+// Old is nil for created fields, New is nil for removed fields.
+type FieldChange struct {
+	Field string      `json:"field"`
+	Old   interface{} `json:"old"`
+	New   interface{} `json:"new"`
+}
+
+// EntityChange is a machine-readable description of an operation
+// performed, or to be performed, on an entity.
+type EntityChange struct {
+	Action  string        `json:"action"`
+	Kind    string        `json:"kind"`
+	Name    string        `json:"name"`
+	ID      string        `json:"id,omitempty"`
+	Changes []FieldChange `json:"changes,omitempty"`
+}
+
+// changeRecorder collects EntityChanges from concurrent workers.
+type changeRecorder struct {
+	mu      sync.Mutex
+	changes []EntityChange
+}
+
+func (r *changeRecorder) record(change EntityChange) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changes = append(r.changes, change)
+}
+
+// sorted returns the recorded changes ordered by kind, name and action.
+func (r *changeRecorder) sorted() []EntityChange {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := append([]EntityChange{}, r.changes...)
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Kind != res[j].Kind {
+			return res[i].Kind < res[j].Kind
+		}
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].Action < res[j].Action
+	})
+	return res
+}
+
+// newEntityChange builds the EntityChange of an event.
+// Values of DECK_ environment variables are masked unless noMaskValues is set,
+// secrets are masked if masker is not nil.
+func newEntityChange(ctx context.Context, e crud.Event, noMaskValues bool,
+	masker *secretMasker,
+) (EntityChange, error) {
+	change := EntityChange{
+		Kind: string(e.Kind),
+		Name: e.Obj.(state.ConsoleString).Console(),
+	}
+	var oldObj, newObj interface{}
+	switch e.Op {
+	case crud.Create:
+		change.Action = ActionCreate
+		newObj = e.Obj
+	case crud.Update:
+		change.Action = ActionUpdate
+		oldObj, newObj = e.OldObj, e.Obj
+	case crud.Delete:
+		change.Action = ActionDelete
+		oldObj = e.Obj
+	}
+	oldFields, err := toFields(oldObj)
+	if err != nil {
+		return change, err
+	}
+	newFields, err := toFields(newObj)
+	if err != nil {
+		return change, err
+	}
+	if id, ok := newFields["id"].(string); ok {
+		change.ID = id
+	} else if id, ok := oldFields["id"].(string); ok {
+		change.ID = id
+	}
+	if masker != nil {
+		masker.mask(ctx, e.Kind, oldFields, newFields)
+	}
+	change.Changes = diffFields("", oldFields, newFields)
+	if !noMaskValues {
+		for i := range change.Changes {
+			change.Changes[i].Old = maskValue(change.Changes[i].Old)
+			change.Changes[i].New = maskValue(change.Changes[i].New)
+		}
+	}
+	return change, nil
+}
+
+// toFields returns the JSON representation of obj as a map,
+// without timestamps.
+func toFields(obj interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if obj == nil {
+		return fields, nil
+	}
+	b, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	delete(fields, "created_at")
+	delete(fields, "updated_at")
+	return fields, nil
+}
+
+// diffFields returns the changes between two objects, recursing into
+// nested objects. Arrays are compared as a whole.
+func diffFields(prefix string, oldFields, newFields map[string]interface{}) []FieldChange {
+	keys := map[string]struct{}{}
+	for k := range oldFields {
+		keys[k] = struct{}{}
+	}
+	for k := range newFields {
+		keys[k] = struct{}{}
+	}
+	sortedKeys := make([]string, 0, len(keys))
+	for k := range keys {
+		sortedKeys = append(sortedKeys, k)
+	}
+	sort.Strings(sortedKeys)
+
+	var res []FieldChange
+	for _, k := range sortedKeys {
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+		oldValue, newValue := oldFields[k], newFields[k]
+		if reflect.DeepEqual(oldValue, newValue) {
+			continue
+		}
+		oldMap, oldIsMap := oldValue.(map[string]interface{})
+		newMap, newIsMap := newValue.(map[string]interface{})
+		if oldIsMap && newIsMap {
+			res = append(res, diffFields(field, oldMap, newMap)...)
+			continue
+		}
+		res = append(res, FieldChange{Field: field, Old: oldValue, New: newValue})
+	}
+	return res
+}
+
+// maskValue masks the values of DECK_ environment variables in
+// the strings found in v.
+func maskValue(v interface{}) interface{} {
+	switch value := v.(type) {
+	case string:
+		return maskEnvVarValue(value)
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, item := range value {
+			res[i] = maskValue(item)
+		}
+		return res
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			res[k] = maskValue(item)
+		}
+		return res
+	default:
+		return v
+	}
+}
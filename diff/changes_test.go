@@ -0,0 +1,90 @@
+package diff
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kong/deck/crud"
+	"github.com/kong/deck/state"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewEntityChange(t *testing.T) {
+	t.Setenv("DECK_UPSTREAM_HOST", "secret.example.com")
+	oldService := &state.Service{Service: kong.Service{
+		ID:        kong.String("s1"),
+		Name:      kong.String("svc"),
+		Host:      kong.String("old.example.com"),
+		Port:      kong.Int(80),
+		Tags:      kong.StringSlice("a"),
+		CreatedAt: kong.Int(1),
+	}}
+	newService := &state.Service{Service: kong.Service{
+		ID:      kong.String("s1"),
+		Name:    kong.String("svc"),
+		Host:    kong.String("secret.example.com"),
+		Port:    kong.Int(80),
+		Tags:    kong.StringSlice("a", "b"),
+		Retries: kong.Int(3),
+	}}
+
+	change, err := newEntityChange(context.Background(), crud.Event{
+		Op: crud.Update, Kind: "service", Obj: newService, OldObj: oldService,
+	}, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, EntityChange{
+		Action: ActionUpdate,
+		Kind:   "service",
+		Name:   "svc",
+		ID:     "s1",
+		Changes: []FieldChange{
+			{Field: "host", Old: "old.example.com", New: "[masked]"},
+			{Field: "retries", Old: nil, New: float64(3)},
+			{Field: "tags", Old: []interface{}{"a"}, New: []interface{}{"a", "b"}},
+		},
+	}, change)
+
+	change, err = newEntityChange(context.Background(), crud.Event{
+		Op: crud.Update, Kind: "service", Obj: newService, OldObj: oldService,
+	}, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "secret.example.com", change.Changes[0].New)
+
+	change, err = newEntityChange(context.Background(), crud.Event{Op: crud.Delete, Kind: "service", Obj: oldService}, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ActionDelete, change.Action)
+	assert.Equal(t, "s1", change.ID)
+	for _, c := range change.Changes {
+		assert.Nil(t, c.New, c.Field)
+	}
+}
+
+func TestDiffFieldsNested(t *testing.T) {
+	changes := diffFields("",
+		map[string]interface{}{
+			"name":   "rate-limiting",
+			"config": map[string]interface{}{"minute": float64(10), "policy": "local"},
+		},
+		map[string]interface{}{
+			"name":   "rate-limiting",
+			"config": map[string]interface{}{"minute": float64(20), "policy": "local", "hour": float64(100)},
+		})
+	assert.Equal(t, []FieldChange{
+		{Field: "config.hour", New: float64(100)},
+		{Field: "config.minute", Old: float64(10), New: float64(20)},
+	}, changes)
+}
+
+func TestChangeRecorderSorted(t *testing.T) {
+	r := &changeRecorder{}
+	r.record(EntityChange{Action: ActionCreate, Kind: "service", Name: "b"})
+	r.record(EntityChange{Action: ActionDelete, Kind: "route", Name: "r"})
+	r.record(EntityChange{Action: ActionUpdate, Kind: "service", Name: "a"})
+	assert.Equal(t, []EntityChange{
+		{Action: ActionDelete, Kind: "route", Name: "r"},
+		{Action: ActionUpdate, Kind: "service", Name: "a"},
+		{Action: ActionCreate, Kind: "service", Name: "b"},
+	}, r.sorted())
+}
@@ -61,8 +61,11 @@ type Syncer struct {
 	entityDiffers map[types.EntityType]types.Differ
 
 	noMaskValues bool
+	masker       *secretMasker
 
 	isKonnect bool
+
+	changes *changeRecorder
 }
 
 type SyncerOpts struct {
@@ -76,9 +79,16 @@ type SyncerOpts struct {
 	StageDelaySec   int
 
 	NoMaskValues bool
+	// NoMaskSecrets disables masking of secrets, such as the keys of
+	// credentials, in the diffs and recorded changes.
+	NoMaskSecrets bool
 
 	IsKonnect bool
 
+	// RecordChanges enables recording of the operations performed
+	// by Solve, which are then available through Changes.
+	RecordChanges bool
+
 	CreatePrintln func(a ...interface{})
 	UpdatePrintln func(a ...interface{})
 	DeletePrintln func(a ...interface{})
@@ -104,6 +114,14 @@ func NewSyncer(opts SyncerOpts) (*Syncer, error) {
 		isKonnect:     opts.IsKonnect,
 	}
 
+	if !opts.NoMaskSecrets {
+		s.masker = newSecretMasker(opts.KongClient)
+	}
+
+	if opts.RecordChanges {
+		s.changes = &changeRecorder{}
+	}
+
 	if s.createPrintln == nil {
 		s.createPrintln = cprint.CreatePrintln
 	}
@@ -178,6 +196,9 @@ func (sc *Syncer) diff() error {
 	return nil
 }
 
+// delete queues the deletion of entities, level by level.
+// Entities of a level are deleted concurrently, and only once
+// all entities of the previous level have been deleted.
 func (sc *Syncer) delete() error {
 	for _, types := range reverseOrder() {
 		for _, entityType := range types {
@@ -185,12 +206,15 @@ func (sc *Syncer) delete() error {
 			if err != nil {
 				return err
 			}
-			sc.wait()
 		}
+		sc.wait()
 	}
 	return nil
 }
 
+// createUpdate queues the creation and update of entities, level by level.
+// Entities of a level are processed concurrently, and only once
+// all entities of the previous level have been processed.
 func (sc *Syncer) createUpdate() error {
 	for _, types := range order() {
 		for _, entityType := range types {
@@ -198,8 +222,8 @@ func (sc *Syncer) createUpdate() error {
 			if err != nil {
 				return err
 			}
-			sc.wait()
 		}
+		sc.wait()
 	}
 	return nil
 }
@@ -234,7 +258,7 @@ func (sc *Syncer) wait() {
 // Run starts a diff and invokes d for every diff.
 func (sc *Syncer) Run(ctx context.Context, parallelism int, d Do) []error {
 	if parallelism < 1 {
-		return append([]error{}, fmt.Errorf("parallelism can not be negative"))
+		return append([]error{}, fmt.Errorf("parallelism must be at least 1"))
 	}
 
 	var wg sync.WaitGroup
@@ -359,9 +383,24 @@ type Stats struct {
 }
 
 // Generete Diff output for 'sync' and 'diff' commands
-func generateDiffString(e crud.Event, isDelete bool, noMaskValues bool) (string, error) {
+// Secrets are masked if masker is not nil.
+func generateDiffString(ctx context.Context, e crud.Event, isDelete bool,
+	noMaskValues bool, masker *secretMasker,
+) (string, error) {
 	var diffString string
 	var err error
+	if _, ok := e.OldObj.(*state.Document); !ok && masker != nil {
+		oldFields, err := toFields(e.OldObj)
+		if err != nil {
+			return "", err
+		}
+		newFields, err := toFields(e.Obj)
+		if err != nil {
+			return "", err
+		}
+		masker.mask(ctx, e.Kind, oldFields, newFields)
+		e.OldObj, e.Obj = oldFields, newFields
+	}
 	if oldObj, ok := e.OldObj.(*state.Document); ok {
 		if !isDelete {
 			diffString, err = getDocumentDiff(oldObj, e.Obj.(*state.Document))
@@ -411,7 +450,7 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool) (Stats,
 		case crud.Create:
 			sc.createPrintln("creating", e.Kind, c.Console())
 		case crud.Update:
-			diffString, err := generateDiffString(e, false, sc.noMaskValues)
+			diffString, err := generateDiffString(ctx, e, false, sc.noMaskValues, sc.masker)
 			if err != nil {
 				return nil, err
 			}
@@ -434,6 +473,13 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool) (Stats,
 			// return the new obj as is
 			result = e.Obj
 		}
+		if sc.changes != nil {
+			change, err := newEntityChange(ctx, e, sc.noMaskValues, sc.masker)
+			if err != nil {
+				return nil, err
+			}
+			sc.changes.record(change)
+		}
 		// record operation in both: diff and sync commands
 		recordOp(e.Op)
 
@@ -441,3 +487,13 @@ func (sc *Syncer) Solve(ctx context.Context, parallelism int, dry bool) (Stats,
 	})
 	return stats, errs
 }
+
+// Changes returns the operations performed by Solve, ordered by
+// entity kind and name.
+// It returns nil unless RecordChanges was set in SyncerOpts.
+func (sc *Syncer) Changes() []EntityChange {
+	if sc.changes == nil {
+		return nil
+	}
+	return sc.changes.sorted()
+}
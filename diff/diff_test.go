@@ -0,0 +1,72 @@
+package diff
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kong/deck/crud"
+	"github.com/kong/deck/state"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncerRunProcessesLevelConcurrently(t *testing.T) {
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	targetState, err := state.NewKongState()
+	require.NoError(t, err)
+	// consumers and certificates are in the same level
+	require.NoError(t, targetState.Consumers.Add(state.Consumer{Consumer: kong.Consumer{
+		ID: kong.String("c1"), Username: kong.String("alice"),
+	}}))
+	require.NoError(t, targetState.Certificates.Add(state.Certificate{Certificate: kong.Certificate{
+		ID: kong.String("cert1"), Cert: kong.String("cert"), Key: kong.String("key"),
+	}}))
+	// services depend on certificates and are in the next level
+	require.NoError(t, targetState.Services.Add(state.Service{Service: kong.Service{
+		ID: kong.String("s1"), Name: kong.String("svc"),
+	}}))
+
+	syncer, err := NewSyncer(SyncerOpts{
+		CurrentState:  currentState,
+		TargetState:   targetState,
+		CreatePrintln: func(a ...interface{}) {},
+	})
+	require.NoError(t, err)
+
+	var (
+		mu        sync.Mutex
+		processed []crud.Kind
+	)
+	// the first level is processed only if both of its events are in
+	// flight at the same time
+	var firstLevel sync.WaitGroup
+	firstLevel.Add(2)
+	errs := syncer.Run(context.Background(), 2, func(e crud.Event) (crud.Arg, error) {
+		if e.Kind == "consumer" || e.Kind == "certificate" {
+			firstLevel.Done()
+			done := make(chan struct{})
+			go func() {
+				firstLevel.Wait()
+				close(done)
+			}()
+			select {
+			case <-done:
+			case <-time.After(5 * time.Second):
+				return nil, fmt.Errorf("%s was not processed concurrently", e.Kind)
+			}
+		}
+		mu.Lock()
+		processed = append(processed, e.Kind)
+		mu.Unlock()
+		return e.Obj, nil
+	})
+	require.Empty(t, errs)
+	require.Len(t, processed, 3)
+	assert.ElementsMatch(t, []crud.Kind{"consumer", "certificate"}, processed[:2])
+	assert.Equal(t, crud.Kind("service"), processed[2])
+}
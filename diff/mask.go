@@ -0,0 +1,206 @@
+package diff
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/kong/deck/crud"
+	"github.com/kong/deck/types"
+	"github.com/kong/go-kong/kong"
+)
+
+const (
+	maskedValue        = "[masked]"
+	maskedChangedValue = "[masked, changed]"
+
+	vaultReferencePrefix = "{vault://"
+)
+
+// sensitiveFields are the fields holding secrets, by kind of entity.
+var sensitiveFields = map[crud.Kind][]string{
+	crud.Kind(types.KeyAuth):     {"key"},
+	crud.Kind(types.BasicAuth):   {"password"},
+	crud.Kind(types.HMACAuth):    {"secret"},
+	crud.Kind(types.JWTAuth):     {"secret"},
+	crud.Kind(types.OAuth2Cred):  {"client_secret"},
+	crud.Kind(types.Certificate): {"key", "key_alt"},
+}
+
+// sensitiveConfigRegex matches the names of plugin configuration fields
+// likely to hold secrets. It is used when the schema of a plugin
+// is not available.
+var sensitiveConfigRegex = regexp.MustCompile(`(?i)(password|secret|private_key|api_key|token)$`)
+
+// secretMasker masks the secrets of entities: credentials, private keys
+// of certificates, and the fields of plugin configurations marked as
+// encrypted or referenceable in the schemas of the plugins.
+type secretMasker struct {
+	client *kong.Client
+
+	mu sync.Mutex
+	// pluginFields caches the sensitive configuration fields of plugins,
+	// nil for plugins whose schema could not be read.
+	pluginFields map[string][]string
+}
+
+func newSecretMasker(client *kong.Client) *secretMasker {
+	return &secretMasker{client: client, pluginFields: map[string][]string{}}
+}
+
+// mask replaces in place the secrets of oldFields and newFields, the
+// JSON representations of an entity before and after a change.
+// Changed secrets are masked differently so that the change is visible.
+// Either map can be empty for creations and deletions.
+func (m *secretMasker) mask(ctx context.Context, kind crud.Kind,
+	oldFields, newFields map[string]interface{},
+) {
+	for _, path := range m.sensitivePaths(ctx, kind, oldFields, newFields) {
+		oldValue, oldOK := lookupField(oldFields, path)
+		newValue, newOK := lookupField(newFields, path)
+		if oldOK && !isVaultReference(oldValue) {
+			setField(oldFields, path, maskedValue)
+		}
+		if newOK && !isVaultReference(newValue) {
+			if oldOK && !reflect.DeepEqual(oldValue, newValue) {
+				setField(newFields, path, maskedChangedValue)
+			} else {
+				setField(newFields, path, maskedValue)
+			}
+		}
+	}
+}
+
+func (m *secretMasker) sensitivePaths(ctx context.Context, kind crud.Kind,
+	oldFields, newFields map[string]interface{},
+) [][]string {
+	var res [][]string
+	for _, field := range sensitiveFields[kind] {
+		res = append(res, []string{field})
+	}
+	if kind != crud.Kind(types.Plugin) && kind != crud.Kind(types.ConsumerGroupPlugin) {
+		return res
+	}
+	name, _ := newFields["name"].(string)
+	if name == "" {
+		name, _ = oldFields["name"].(string)
+	}
+	fields := m.pluginSensitiveFields(ctx, name)
+	if fields == nil {
+		// without schema, guess secrets from the names of the fields
+		for _, config := range []map[string]interface{}{oldFields, newFields} {
+			c, _ := config["config"].(map[string]interface{})
+			res = append(res, guessSensitivePaths([]string{"config"}, c)...)
+		}
+		return res
+	}
+	for _, field := range fields {
+		res = append(res, append([]string{"config"}, strings.Split(field, ".")...))
+	}
+	return res
+}
+
+// pluginSensitiveFields returns the paths of the configuration fields of
+// a plugin marked as encrypted or referenceable, nil if the schema of the
+// plugin cannot be read.
+func (m *secretMasker) pluginSensitiveFields(ctx context.Context, name string) []string {
+	if m.client == nil || name == "" {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if fields, ok := m.pluginFields[name]; ok {
+		return fields
+	}
+	var fields []string
+	schema, err := m.client.Plugins.GetFullSchema(ctx, &name)
+	if err == nil {
+		fields = []string{}
+		for _, f := range schemaFields(schema["fields"]) {
+			if config, ok := f["config"].(map[string]interface{}); ok {
+				fields = append(fields, schemaSensitiveFields("", config["fields"])...)
+			}
+		}
+	}
+	m.pluginFields[name] = fields
+	return fields
+}
+
+// schemaFields returns the fields of a schema, which are
+// lists of objects holding a single field each.
+func schemaFields(v interface{}) []map[string]interface{} {
+	list, _ := v.([]interface{})
+	res := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		if f, ok := item.(map[string]interface{}); ok {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// schemaSensitiveFields returns the dotted paths of the fields of a
+// schema, recursing into records, which are encrypted or referenceable.
+func schemaSensitiveFields(prefix string, fields interface{}) []string {
+	var res []string
+	for _, f := range schemaFields(fields) {
+		for name, v := range f {
+			def, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			path := prefix + name
+			if def["encrypted"] == true || def["referenceable"] == true {
+				res = append(res, path)
+				continue
+			}
+			if def["type"] == "record" {
+				res = append(res, schemaSensitiveFields(path+".", def["fields"])...)
+			}
+		}
+	}
+	return res
+}
+
+func guessSensitivePaths(prefix []string, fields map[string]interface{}) [][]string {
+	var res [][]string
+	for name, value := range fields {
+		path := append(append([]string{}, prefix...), name)
+		if nested, ok := value.(map[string]interface{}); ok {
+			res = append(res, guessSensitivePaths(path, nested)...)
+			continue
+		}
+		if sensitiveConfigRegex.MatchString(name) {
+			res = append(res, path)
+		}
+	}
+	return res
+}
+
+func isVaultReference(v interface{}) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, vaultReferencePrefix)
+}
+
+func lookupField(fields map[string]interface{}, path []string) (interface{}, bool) {
+	var node interface{} = fields
+	for _, name := range path {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[name]; !ok || node == nil {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func setField(fields map[string]interface{}, path []string, value interface{}) {
+	for _, name := range path[:len(path)-1] {
+		fields, _ = fields[name].(map[string]interface{})
+	}
+	fields[path[len(path)-1]] = value
+}
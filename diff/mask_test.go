@@ -0,0 +1,121 @@
+package diff
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/deck/crud"
+	"github.com/kong/deck/state"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecretMaskerCredentials(t *testing.T) {
+	m := newSecretMasker(nil)
+	oldFields := map[string]interface{}{"key": "old-key", "tags": []interface{}{"a"}}
+	newFields := map[string]interface{}{"key": "new-key", "tags": []interface{}{"a"}}
+	m.mask(context.Background(), "key-auth", oldFields, newFields)
+	assert.Equal(t, "[masked]", oldFields["key"])
+	assert.Equal(t, "[masked, changed]", newFields["key"])
+	assert.Equal(t, []interface{}{"a"}, newFields["tags"])
+
+	newFields = map[string]interface{}{"cert": "cert", "key": "private-key"}
+	m.mask(context.Background(), "certificate", map[string]interface{}{}, newFields)
+	assert.Equal(t, map[string]interface{}{"cert": "cert", "key": "[masked]"}, newFields)
+
+	// vault references are not secrets
+	newFields = map[string]interface{}{"password": "{vault://env/password}"}
+	m.mask(context.Background(), "basic-auth", map[string]interface{}{}, newFields)
+	assert.Equal(t, "{vault://env/password}", newFields["password"])
+}
+
+func TestSecretMaskerPluginSchema(t *testing.T) {
+	schemaRequests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/schemas/plugins/session" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		schemaRequests++
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"fields": [
+			{"protocols": {"type": "set"}},
+			{"config": {"type": "record", "fields": [
+				{"secret": {"type": "string", "encrypted": true, "referenceable": true}},
+				{"cookie_name": {"type": "string"}},
+				{"redis": {"type": "record", "fields": [
+					{"username": {"type": "string", "referenceable": true}},
+					{"host": {"type": "string"}}
+				]}}
+			]}}
+		]}`))
+	}))
+	defer server.Close()
+	client, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+
+	m := newSecretMasker(client)
+	for i := 0; i < 2; i++ {
+		oldFields := map[string]interface{}{
+			"name": "session",
+			"config": map[string]interface{}{
+				"secret":      "s3cr3t",
+				"cookie_name": "session",
+				"redis":       map[string]interface{}{"username": "admin", "host": "redis"},
+			},
+		}
+		newFields := map[string]interface{}{
+			"name": "session",
+			"config": map[string]interface{}{
+				"secret":      "s3cr3t",
+				"cookie_name": "session",
+				"redis":       map[string]interface{}{"username": "root", "host": "redis"},
+			},
+		}
+		m.mask(context.Background(), crud.Kind("plugin"), oldFields, newFields)
+		assert.Equal(t, map[string]interface{}{
+			"secret":      "[masked]",
+			"cookie_name": "session",
+			"redis":       map[string]interface{}{"username": "[masked, changed]", "host": "redis"},
+		}, newFields["config"])
+		assert.Equal(t, "[masked]", oldFields["config"].(map[string]interface{})["secret"])
+	}
+	assert.Equal(t, 1, schemaRequests, "schemas are cached")
+
+	// without schema, secrets are guessed from the names of the fields
+	newFields := map[string]interface{}{
+		"name": "custom",
+		"config": map[string]interface{}{
+			"api_key": "k",
+			"nested":  map[string]interface{}{"client_secret": "s", "timeout": float64(10)},
+		},
+	}
+	m.mask(context.Background(), crud.Kind("plugin"), map[string]interface{}{}, newFields)
+	assert.Equal(t, map[string]interface{}{
+		"api_key": "[masked]",
+		"nested":  map[string]interface{}{"client_secret": "[masked]", "timeout": float64(10)},
+	}, newFields["config"])
+}
+
+func TestNewEntityChangeMasksSecrets(t *testing.T) {
+	oldCred := &state.KeyAuth{KeyAuth: kong.KeyAuth{ID: kong.String("k1"), Key: kong.String("old-key")}}
+	newCred := &state.KeyAuth{KeyAuth: kong.KeyAuth{ID: kong.String("k1"), Key: kong.String("new-key")}}
+
+	e := crud.Event{Op: crud.Update, Kind: "key-auth", Obj: newCred, OldObj: oldCred}
+	change, err := newEntityChange(context.Background(), e, true, newSecretMasker(nil))
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{{Field: "key", Old: "[masked]", New: "[masked, changed]"}}, change.Changes)
+
+	diffString, err := generateDiffString(context.Background(), e, false, true, newSecretMasker(nil))
+	require.NoError(t, err)
+	assert.NotContains(t, diffString, "old-key")
+	assert.NotContains(t, diffString, "new-key")
+	assert.Contains(t, diffString, "[masked, changed]")
+
+	change, err = newEntityChange(context.Background(), e, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []FieldChange{{Field: "key", Old: "old-key", New: "new-key"}}, change.Changes)
+}
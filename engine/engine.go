@@ -0,0 +1,287 @@
+// Package engine exposes the dump, diff and sync engine of decK as a Go
+// library, so that other tools can embed it instead of running the CLI.
+//
+// Diff and Sync run the whole pipeline against a workspace of Kong:
+// reading the current state, rendering the target state from the
+// content of state files, and computing or applying the changes in
+// dependency order. The steps are also available on their own.
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/dump"
+	"github.com/kong/deck/file"
+	"github.com/kong/deck/state"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+)
+
+const (
+	// DefaultParallelism is the number of concurrent requests
+	// sent to Kong by Sync if Options.Parallelism is not set.
+	DefaultParallelism = 10
+
+	defaultFormatVersion = "1.1"
+	formatVersion30      = "3.0"
+)
+
+// Options configures Diff and Sync.
+type Options struct {
+	// Dump configures which entities of Kong are managed,
+	// e.g. with select tags.
+	Dump dump.Config
+
+	// KongVersion is the version of Kong the target state is shaped for.
+	// Defaults to the version reported by Kong.
+	KongVersion string
+	// UnsupportedFields handles fields which are not supported
+	// by KongVersion.
+	UnsupportedFields utils.UnsupportedFieldsMode
+
+	// Parallelism is the number of concurrent requests sent to Kong.
+	Parallelism int
+	// StageDelaySec is the delay between the deletion and creation
+	// stages of a sync, in seconds.
+	StageDelaySec int
+
+	// NoMaskValues disables masking of environment variable values in
+	// the recorded changes. NoMaskSecrets disables masking of secrets,
+	// such as the keys of credentials.
+	NoMaskValues  bool
+	NoMaskSecrets bool
+
+	IsKonnect bool
+
+	// CreatePrintln, UpdatePrintln and DeletePrintln, if set, receive the
+	// human readable diff of each change. Nothing is printed by default.
+	CreatePrintln func(a ...interface{})
+	UpdatePrintln func(a ...interface{})
+	DeletePrintln func(a ...interface{})
+}
+
+// Result is the outcome of a diff or a sync.
+type Result struct {
+	Stats   diff.Stats
+	Changes []diff.EntityChange
+}
+
+// Ops returns the number of operations of the diff or sync.
+func (r *Result) Ops() int {
+	return int(r.Stats.CreateOps.Count() + r.Stats.UpdateOps.Count() + r.Stats.DeleteOps.Count())
+}
+
+// Diff returns the changes needed for the workspace of client to match
+// content, without applying them.
+func Diff(ctx context.Context, client *kong.Client, content *file.Content,
+	opts Options,
+) (*Result, error) {
+	return run(ctx, client, content, opts, true)
+}
+
+// Sync applies the changes needed for the workspace of client to match
+// content. The workspace must exist.
+// The changes applied before a failure are part of the result.
+func Sync(ctx context.Context, client *kong.Client, content *file.Content,
+	opts Options,
+) (*Result, error) {
+	return run(ctx, client, content, opts, false)
+}
+
+func run(ctx context.Context, client *kong.Client, content *file.Content,
+	opts Options, dry bool,
+) (*Result, error) {
+	if content == nil {
+		return nil, fmt.Errorf("content cannot be nil")
+	}
+	version := opts.KongVersion
+	if version == "" {
+		var err error
+		if version, err = KongVersion(ctx, client); err != nil {
+			return nil, fmt.Errorf("reading Kong version: %w", err)
+		}
+	}
+	kongVersion, err := utils.ParseKongVersion(version)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Kong version: %w", err)
+	}
+	if err := CheckFormatVersion(content, kongVersion); err != nil {
+		return nil, err
+	}
+	opts.Dump.SelectorTags, err = SelectorTags(content, opts.Dump.SelectorTags)
+	if err != nil {
+		return nil, err
+	}
+	_, currentState, err := CurrentState(ctx, client, opts.Dump)
+	if err != nil {
+		return nil, err
+	}
+	targetState, err := TargetState(ctx, client, content, currentState, kongVersion, opts)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(ctx, client, currentState, targetState, dry, opts)
+}
+
+// KongVersion returns the version of Kong reported by client.
+// The root endpoint is read first, as the workspace of client may not
+// exist yet, falling back to the /kong endpoint of the workspace.
+func KongVersion(ctx context.Context, client *kong.Client) (string, error) {
+	req, err := client.NewRequestRaw("GET", client.BaseRootURL(), "/", nil, nil)
+	if err != nil {
+		return "", err
+	}
+	root := map[string]interface{}{}
+	if _, err = client.Do(ctx, req, &root); err != nil {
+		if client.Workspace() == "" {
+			return "", err
+		}
+		// users limited to a workspace by RBAC can't read the root endpoint
+		if root, err = client.Root(ctx); err != nil {
+			return "", err
+		}
+	}
+	version, ok := root["version"].(string)
+	if !ok {
+		return "", fmt.Errorf("no version reported by Kong")
+	}
+	return version, nil
+}
+
+// CheckFormatVersion returns an error if the format version of content
+// cannot be applied to kongVersion. Kong 3.0 and above requires the 3.0
+// format, which older versions do not understand, e.g. regex paths of
+// routes prefixed with '~'.
+func CheckFormatVersion(content *file.Content, kongVersion semver.Version) error {
+	if kongVersion.LT(utils.Kong300Version) && content.FormatVersion == formatVersion30 {
+		return fmt.Errorf("cannot apply '%s' config format version to Kong version %s: "+
+			"the format requires Kong 3.0 or above", formatVersion30, kongVersion)
+	}
+	if kongVersion.GTE(utils.Kong300Version) &&
+		content.FormatVersion != formatVersion30 {
+		formatVersion := content.FormatVersion
+		if formatVersion == "" {
+			formatVersion = defaultFormatVersion
+		}
+		return fmt.Errorf(
+			"cannot apply '%s' config format version to Kong version 3.0 or above.\n"+
+				utils.UpgradeMessage, formatVersion)
+	}
+	return nil
+}
+
+// SelectorTags returns the select tags managing the entities of content:
+// the tags of its _info, or tags if it has none. Both must match if set.
+func SelectorTags(content *file.Content, tags []string) ([]string, error) {
+	if content.Info == nil || len(content.Info.SelectorTags) == 0 {
+		return tags, nil
+	}
+	utils.RemoveDuplicates(&content.Info.SelectorTags)
+	if len(tags) > 0 {
+		sort.Strings(tags)
+		sort.Strings(content.Info.SelectorTags)
+		if !reflect.DeepEqual(tags, content.Info.SelectorTags) {
+			return nil, fmt.Errorf(`tags specified in the state file (%v) and via --select-tags flag (%v) are different.
+					decK expects tags to be specified in either via flag or via state file.
+					In case both are specified, they must match`, content.Info.SelectorTags, tags)
+		}
+	}
+	return content.Info.SelectorTags, nil
+}
+
+// CurrentState reads the entities of the workspace of client selected
+// by dumpConfig.
+func CurrentState(ctx context.Context, client *kong.Client, dumpConfig dump.Config) (
+	*utils.KongRawState, *state.KongState, error,
+) {
+	rawState, err := dump.Get(ctx, client, dumpConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	currentState, err := state.Get(rawState)
+	if err != nil {
+		return nil, nil, err
+	}
+	return rawState, currentState, nil
+}
+
+// TargetState renders content into the state Kong must match, shaped for
+// kongVersion. References to existing entities are resolved against
+// currentState and client.
+func TargetState(ctx context.Context, client *kong.Client, content *file.Content,
+	currentState *state.KongState, kongVersion semver.Version, opts Options,
+) (*state.KongState, error) {
+	rawState, err := file.Get(ctx, content, file.RenderConfig{
+		CurrentState: currentState,
+		KongVersion:  kongVersion,
+	}, opts.Dump, client)
+	if err != nil {
+		return nil, err
+	}
+	err = utils.ShapeForKongVersion(rawState, kongVersion, opts.UnsupportedFields)
+	if err != nil {
+		return nil, err
+	}
+	return state.Get(rawState)
+}
+
+// Apply computes the changes from currentState to targetState, and
+// applies them through client in dependency order, unless dry is set.
+// currentState is updated as changes are applied.
+// The result is returned even on failure, with the completed changes.
+func Apply(ctx context.Context, client *kong.Client,
+	currentState, targetState *state.KongState, dry bool, opts Options,
+) (*Result, error) {
+	noop := func(a ...interface{}) {}
+	printer := func(f func(a ...interface{})) func(a ...interface{}) {
+		if f == nil {
+			return noop
+		}
+		return f
+	}
+	s, err := diff.NewSyncer(diff.SyncerOpts{
+		CurrentState:  currentState,
+		TargetState:   targetState,
+		KongClient:    client,
+		StageDelaySec: opts.StageDelaySec,
+		NoMaskValues:  opts.NoMaskValues,
+		NoMaskSecrets: opts.NoMaskSecrets,
+		IsKonnect:     opts.IsKonnect,
+		RecordChanges: true,
+		CreatePrintln: printer(opts.CreatePrintln),
+		UpdatePrintln: printer(opts.UpdatePrintln),
+		DeletePrintln: printer(opts.DeletePrintln),
+	})
+	if err != nil {
+		return nil, err
+	}
+	parallelism := opts.Parallelism
+	if parallelism < 1 {
+		parallelism = DefaultParallelism
+	}
+	stats, errs := s.Solve(ctx, parallelism, dry)
+	result := &Result{Stats: stats, Changes: s.Changes()}
+	if errs != nil {
+		return result, utils.ErrArray{Errors: errs}
+	}
+	return result, nil
+}
+
+// Errors returns the errors of the operations which failed in a diff or
+// a sync, or err itself.
+func Errors(err error) []error {
+	var errArray utils.ErrArray
+	if errors.As(err, &errArray) {
+		return errArray.Errors
+	}
+	if err == nil {
+		return nil
+	}
+	return []error{err}
+}
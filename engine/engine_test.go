@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/deck/diff"
+	"github.com/kong/deck/file"
+	"github.com/kong/deck/kongtest"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readContent(t *testing.T, yaml string) *file.Content {
+	t.Helper()
+	filename := filepath.Join(t.TempDir(), "kong.yaml")
+	require.NoError(t, os.WriteFile(filename, []byte(yaml), 0o600))
+	content, err := file.GetContentFromFiles([]string{filename})
+	require.NoError(t, err)
+	return content
+}
+
+func TestDiffAndSync(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	content := readContent(t, `
+_format_version: "3.0"
+services:
+- name: svc1
+  host: example.com
+  routes:
+  - name: r1
+    paths:
+    - /r1
+`)
+	var printed []interface{}
+	opts := Options{
+		CreatePrintln: func(a ...interface{}) { printed = append(printed, a...) },
+	}
+
+	result, err := Diff(ctx, client, content, opts)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Ops())
+	require.Len(t, result.Changes, 2)
+	assert.Equal(t, diff.ActionCreate, result.Changes[0].Action)
+	assert.NotEmpty(t, printed)
+	services, err := client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services)
+
+	result, err = Sync(ctx, client, content, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Ops())
+	services, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, services, 1)
+	assert.Equal(t, "svc1", *services[0].Name)
+
+	result, err = Diff(ctx, client, content, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Ops())
+}
+
+func TestDiffChecksFormatVersion(t *testing.T) {
+	server := kongtest.NewServer(kongtest.WithVersion("3.3.0"))
+	defer server.Close()
+	client, err := server.KongClient()
+	require.NoError(t, err)
+
+	content := readContent(t, "_format_version: \"1.1\"\n")
+	_, err = Diff(context.Background(), client, content, Options{})
+	assert.ErrorContains(t, err, "cannot apply '1.1' config format version")
+}
+
+func TestCheckFormatVersion(t *testing.T) {
+	kong28 := semver.MustParse("2.8.0")
+	kong34 := semver.MustParse("3.4.0")
+	format := func(version string) *file.Content {
+		return &file.Content{FormatVersion: version}
+	}
+	assert.NoError(t, CheckFormatVersion(format("1.1"), kong28))
+	assert.NoError(t, CheckFormatVersion(format(""), kong28))
+	assert.EqualError(t, CheckFormatVersion(format("3.0"), kong28),
+		"cannot apply '3.0' config format version to Kong version 2.8.0: the format requires Kong 3.0 or above")
+	assert.NoError(t, CheckFormatVersion(format("3.0"), kong34))
+	assert.ErrorContains(t, CheckFormatVersion(format("1.1"), kong34),
+		"cannot apply '1.1' config format version to Kong version 3.0 or above")
+}
+
+func TestSelectorTags(t *testing.T) {
+	content := &file.Content{Info: &file.Info{SelectorTags: []string{"b", "a", "a"}}}
+	tags, err := SelectorTags(content, nil)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, tags)
+
+	_, err = SelectorTags(content, []string{"c"})
+	assert.ErrorContains(t, err, "are different")
+
+	tags, err = SelectorTags(&file.Content{}, []string{"c"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, tags)
+}
+
+func TestKongVersion(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/prefix/":
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"message":"forbidden"}`))
+		case "/prefix/ws1/kong":
+			_, _ = w.Write([]byte(`{"version":"3.4.0"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client, err := kong.NewClient(kong.String(server.URL+"/prefix"), server.Client())
+	require.NoError(t, err)
+	_, err = KongVersion(context.Background(), client)
+	require.Error(t, err)
+
+	client.SetWorkspace("ws1")
+	paths = nil
+	version, err := KongVersion(context.Background(), client)
+	require.NoError(t, err)
+	assert.Equal(t, "3.4.0", version)
+	assert.Equal(t, []string{"/prefix/", "/prefix/ws1/kong"}, paths)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/blang/semver/v4"
 	"github.com/kong/deck/konnect"
 	"github.com/kong/deck/state"
+	"github.com/kong/deck/types"
 	"github.com/kong/deck/utils"
 	"github.com/kong/go-kong/kong"
 )
@@ -35,6 +36,14 @@ type stateBuilder struct {
 
 	checkRoutePaths bool
 
+	// declaredFields, if set, records the fields set in targetContent
+	// for each entity, before defaults are filled.
+	declaredFields DeclaredFields
+
+	// identityIDs caches the IDs of the entities of the current state
+	// by identity tag, for each entity type.
+	identityIDs map[types.EntityType]map[string]string
+
 	err error
 }
 
@@ -233,6 +242,13 @@ func (b *stateBuilder) consumers() {
 
 	for _, c := range b.targetContent.Consumers {
 		c := c
+		if utils.Empty(c.ID) {
+			var err error
+			if c.ID, err = b.idByIdentityTag(types.Consumer, c.Tags); err != nil {
+				b.err = err
+				return
+			}
+		}
 		if utils.Empty(c.ID) {
 			consumer, err := b.currentState.Consumers.Get(*c.Username)
 			if err == state.ErrNotFound {
@@ -253,6 +269,10 @@ func (b *stateBuilder) consumers() {
 			}
 		}
 		utils.MustMergeTags(&c.Consumer, b.selectTags)
+		if err := b.declaredFields.declare(types.Consumer, c.ID, c.Consumer); err != nil {
+			b.err = err
+			return
+		}
 
 		b.rawState.Consumers = append(b.rawState.Consumers, &c.Consumer)
 		err := b.intermediate.Consumers.Add(state.Consumer{Consumer: c.Consumer})
@@ -455,6 +475,7 @@ func (b *stateBuilder) ingestJWTAuths(creds []kong.JWTAuth) error {
 				cred.ID = kong.String(*existingCred.ID)
 			}
 		}
+		b.defaulter.MustSet(&cred)
 		if b.kongVersion.GTE(utils.Kong140Version) {
 			utils.MustMergeTags(&cred, b.selectTags)
 		}
@@ -476,6 +497,7 @@ func (b *stateBuilder) ingestOauth2Creds(creds []kong.Oauth2Credential) error {
 				cred.ID = kong.String(*existingCred.ID)
 			}
 		}
+		b.defaulter.MustSet(&cred)
 		if b.kongVersion.GTE(utils.Kong140Version) {
 			utils.MustMergeTags(&cred, b.selectTags)
 		}
@@ -650,6 +672,12 @@ func (b *stateBuilder) services() {
 }
 
 func (b *stateBuilder) ingestService(s *FService) error {
+	if utils.Empty(s.ID) {
+		var err error
+		if s.ID, err = b.idByIdentityTag(types.Service, s.Tags); err != nil {
+			return err
+		}
+	}
 	if utils.Empty(s.ID) {
 		svc, err := b.currentState.Services.Get(*s.Name)
 		if err == state.ErrNotFound {
@@ -661,6 +689,9 @@ func (b *stateBuilder) ingestService(s *FService) error {
 		}
 	}
 	utils.MustMergeTags(&s.Service, b.selectTags)
+	if err := b.declaredFields.declare(types.Service, s.ID, s.Service); err != nil {
+		return err
+	}
 	b.defaulter.MustSet(&s.Service)
 
 	b.rawState.Services = append(b.rawState.Services, &s.Service)
@@ -786,6 +817,13 @@ func (b *stateBuilder) upstreams() {
 
 	for _, u := range b.targetContent.Upstreams {
 		u := u
+		if utils.Empty(u.ID) {
+			var err error
+			if u.ID, err = b.idByIdentityTag(types.Upstream, u.Tags); err != nil {
+				b.err = err
+				return
+			}
+		}
 		if utils.Empty(u.ID) {
 			ups, err := b.currentState.Upstreams.Get(*u.Name)
 			if err == state.ErrNotFound {
@@ -798,6 +836,10 @@ func (b *stateBuilder) upstreams() {
 			}
 		}
 		utils.MustMergeTags(&u.Upstream, b.selectTags)
+		if err := b.declaredFields.declare(types.Upstream, u.ID, u.Upstream); err != nil {
+			b.err = err
+			return
+		}
 		b.defaulter.MustSet(&u.Upstream)
 
 		b.rawState.Upstreams = append(b.rawState.Upstreams, &u.Upstream)
@@ -909,6 +951,12 @@ func getStripPathBasedOnProtocols(route kong.Route) (*bool, error) {
 }
 
 func (b *stateBuilder) ingestRoute(r FRoute) error {
+	if utils.Empty(r.ID) {
+		var err error
+		if r.ID, err = b.idByIdentityTag(types.Route, r.Tags); err != nil {
+			return err
+		}
+	}
 	if utils.Empty(r.ID) {
 		route, err := b.currentState.Routes.Get(*r.Name)
 		if err == state.ErrNotFound {
@@ -921,6 +969,9 @@ func (b *stateBuilder) ingestRoute(r FRoute) error {
 	}
 
 	utils.MustMergeTags(&r, b.selectTags)
+	if err := b.declaredFields.declare(types.Route, r.ID, r.Route); err != nil {
+		return err
+	}
 
 	stripPath, err := getStripPathBasedOnProtocols(r.Route)
 	if err != nil {
@@ -1016,10 +1067,13 @@ func (b *stateBuilder) ingestPlugins(plugins []FPlugin) error {
 		if err != nil {
 			return err
 		}
+		utils.MustMergeTags(&p, b.selectTags)
+		if err := b.declaredFields.declare(types.Plugin, p.ID, p.Plugin); err != nil {
+			return err
+		}
 		if err := b.addPluginDefaults(&p); err != nil {
 			return fmt.Errorf("add defaults to plugin '%v': %v", *p.Name, err)
 		}
-		utils.MustMergeTags(&p, b.selectTags)
 		b.rawState.Plugins = append(b.rawState.Plugins, &p.Plugin)
 	}
 	return nil
@@ -984,9 +984,10 @@ func Test_stateBuilder_consumers(t *testing.T) {
 				},
 				JWTAuths: []*kong.JWTAuth{
 					{
-						ID:     kong.String("9e6f82e5-4e74-4e81-a79e-4bbd6fe34cdc"),
-						Key:    kong.String("jwt-key"),
-						Secret: kong.String("jwt-secret"),
+						ID:        kong.String("9e6f82e5-4e74-4e81-a79e-4bbd6fe34cdc"),
+						Algorithm: kong.String("HS256"),
+						Key:       kong.String("jwt-key"),
+						Secret:    kong.String("jwt-secret"),
 						Consumer: &kong.Consumer{
 							ID:       kong.String("5b1484f2-5209-49d9-b43e-92ba09dd9d52"),
 							Username: kong.String("foo"),
@@ -995,9 +996,11 @@ func Test_stateBuilder_consumers(t *testing.T) {
 				},
 				Oauth2Creds: []*kong.Oauth2Credential{
 					{
-						ID:       kong.String("ba843ee8-d63e-4c4f-be1c-ebea546d8fac"),
-						ClientID: kong.String("oauth2-clientid"),
-						Name:     kong.String("oauth2-name"),
+						ID:         kong.String("ba843ee8-d63e-4c4f-be1c-ebea546d8fac"),
+						ClientType: kong.String("confidential"),
+						HashSecret: kong.Bool(false),
+						ClientID:   kong.String("oauth2-clientid"),
+						Name:       kong.String("oauth2-name"),
 						Consumer: &kong.Consumer{
 							ID:       kong.String("5b1484f2-5209-49d9-b43e-92ba09dd9d52"),
 							Username: kong.String("foo"),
@@ -1136,9 +1139,10 @@ func Test_stateBuilder_consumers(t *testing.T) {
 				},
 				JWTAuths: []*kong.JWTAuth{
 					{
-						ID:     kong.String("917b9402-1be0-49d2-b482-ca4dccc2054e"),
-						Key:    kong.String("jwt-key"),
-						Secret: kong.String("jwt-secret"),
+						ID:        kong.String("917b9402-1be0-49d2-b482-ca4dccc2054e"),
+						Algorithm: kong.String("HS256"),
+						Key:       kong.String("jwt-key"),
+						Secret:    kong.String("jwt-secret"),
 						Consumer: &kong.Consumer{
 							ID:       kong.String("4bfcb11f-c962-4817-83e5-9433cf20b663"),
 							Username: kong.String("foo"),
@@ -1147,9 +1151,11 @@ func Test_stateBuilder_consumers(t *testing.T) {
 				},
 				Oauth2Creds: []*kong.Oauth2Credential{
 					{
-						ID:       kong.String("4eef5285-3d6a-4f6b-b659-8957a940e2ca"),
-						ClientID: kong.String("oauth2-clientid"),
-						Name:     kong.String("oauth2-name"),
+						ID:         kong.String("4eef5285-3d6a-4f6b-b659-8957a940e2ca"),
+						ClientType: kong.String("confidential"),
+						HashSecret: kong.Bool(false),
+						ClientID:   kong.String("oauth2-clientid"),
+						Name:       kong.String("oauth2-name"),
 						Consumer: &kong.Consumer{
 							ID:       kong.String("4bfcb11f-c962-4817-83e5-9433cf20b663"),
 							Username: kong.String("foo"),
@@ -1275,9 +1281,10 @@ func Test_stateBuilder_consumers(t *testing.T) {
 				},
 				JWTAuths: []*kong.JWTAuth{
 					{
-						ID:     kong.String("917b9402-1be0-49d2-b482-ca4dccc2054e"),
-						Key:    kong.String("jwt-key"),
-						Secret: kong.String("jwt-secret"),
+						ID:        kong.String("917b9402-1be0-49d2-b482-ca4dccc2054e"),
+						Algorithm: kong.String("HS256"),
+						Key:       kong.String("jwt-key"),
+						Secret:    kong.String("jwt-secret"),
 						Consumer: &kong.Consumer{
 							ID:       kong.String("4bfcb11f-c962-4817-83e5-9433cf20b663"),
 							Username: kong.String("foo"),
@@ -1286,9 +1293,11 @@ func Test_stateBuilder_consumers(t *testing.T) {
 				},
 				Oauth2Creds: []*kong.Oauth2Credential{
 					{
-						ID:       kong.String("4eef5285-3d6a-4f6b-b659-8957a940e2ca"),
-						ClientID: kong.String("oauth2-clientid"),
-						Name:     kong.String("oauth2-name"),
+						ID:         kong.String("4eef5285-3d6a-4f6b-b659-8957a940e2ca"),
+						ClientType: kong.String("confidential"),
+						HashSecret: kong.Bool(false),
+						ClientID:   kong.String("oauth2-clientid"),
+						Name:       kong.String("oauth2-name"),
 						Consumer: &kong.Consumer{
 							ID:       kong.String("4bfcb11f-c962-4817-83e5-9433cf20b663"),
 							Username: kong.String("foo"),
@@ -0,0 +1,163 @@
+package file
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// entityKey identifies an entity across state files.
+type entityKey struct {
+	kind string
+	name string
+}
+
+// duplicateDetector tracks where entities are defined when merging
+// multiple state files, in order to report conflicting definitions.
+type duplicateDetector struct {
+	sources map[entityKey]string
+	errs    []string
+}
+
+func newDuplicateDetector() *duplicateDetector {
+	return &duplicateDetector{sources: map[entityKey]string{}}
+}
+
+// add records the definition of an entity in source.
+// Entities without name nor ID cannot be tracked and are ignored.
+func (d *duplicateDetector) add(source, kind string, nameOrID ...*string) {
+	var name string
+	for _, v := range nameOrID {
+		if v != nil && *v != "" {
+			name = *v
+			break
+		}
+	}
+	if name == "" {
+		return
+	}
+	key := entityKey{kind: kind, name: name}
+	if previous, ok := d.sources[key]; ok {
+		if previous == source {
+			d.errs = append(d.errs, fmt.Sprintf("%s '%s' is defined more than once in %s",
+				kind, name, source))
+		} else {
+			d.errs = append(d.errs, fmt.Sprintf("%s '%s' is defined in both %s and %s",
+				kind, name, previous, source))
+		}
+		return
+	}
+	d.sources[key] = source
+}
+
+// addContent records the entities defined in the content of source.
+func (d *duplicateDetector) addContent(source string, content *Content) {
+	for _, s := range content.Services {
+		d.add(source, "service", s.Name, s.ID)
+		for _, r := range s.Routes {
+			d.add(source, "route", r.Name, r.ID)
+		}
+	}
+	for _, r := range content.Routes {
+		d.add(source, "route", r.Name, r.ID)
+	}
+	for _, c := range content.Consumers {
+		d.add(source, "consumer", c.Username, c.ID)
+	}
+	for _, cg := range content.ConsumerGroups {
+		d.add(source, "consumer group", cg.Name, cg.ID)
+	}
+	for _, p := range content.Plugins {
+		d.add(source, "plugin", p.ID)
+	}
+	for _, u := range content.Upstreams {
+		d.add(source, "upstream", u.Name, u.ID)
+	}
+	for _, c := range content.Certificates {
+		d.add(source, "certificate", c.ID)
+	}
+	for _, c := range content.CACertificates {
+		d.add(source, "CA certificate", c.ID)
+	}
+	for _, v := range content.Vaults {
+		d.add(source, "vault", v.Prefix, v.ID)
+	}
+	for _, sp := range content.ServicePackages {
+		d.add(source, "service package", sp.Name, sp.ID)
+	}
+	pluginConfigs := make([]string, 0, len(content.PluginConfigs))
+	for name := range content.PluginConfigs {
+		pluginConfigs = append(pluginConfigs, name)
+	}
+	sort.Strings(pluginConfigs)
+	for i := range pluginConfigs {
+		d.add(source, "plugin config", &pluginConfigs[i])
+	}
+}
+
+// documentEntityKeys maps the top-level keys of state files to the kind
+// of entities they hold and the fields identifying these entities.
+var documentEntityKeys = map[string]struct {
+	kind   string
+	fields []string
+}{
+	"services":         {"service", []string{"name", "id"}},
+	"routes":           {"route", []string{"name", "id"}},
+	"consumers":        {"consumer", []string{"username", "id"}},
+	"consumer_groups":  {"consumer group", []string{"name", "id"}},
+	"plugins":          {"plugin", []string{"id"}},
+	"upstreams":        {"upstream", []string{"name", "id"}},
+	"certificates":     {"certificate", []string{"id"}},
+	"ca_certificates":  {"CA certificate", []string{"id"}},
+	"vaults":           {"vault", []string{"prefix", "id"}},
+	"service_packages": {"service package", []string{"name", "id"}},
+}
+
+// addDocument records the entities defined in source, parsed as a generic
+// document instead of Content, so that templates are not rendered.
+func (d *duplicateDetector) addDocument(source string, doc map[string]interface{}) {
+	keys := make([]string, 0, len(doc))
+	for key := range doc {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		entity, ok := documentEntityKeys[key]
+		if !ok {
+			continue
+		}
+		items, _ := doc[key].([]interface{})
+		for _, item := range items {
+			object, _ := item.(map[string]interface{})
+			d.add(source, entity.kind, documentFields(object, entity.fields)...)
+			if key != "services" {
+				continue
+			}
+			routes, _ := object["routes"].([]interface{})
+			for _, r := range routes {
+				route, _ := r.(map[string]interface{})
+				d.add(source, "route", documentFields(route, documentEntityKeys["routes"].fields)...)
+			}
+		}
+	}
+}
+
+// documentFields returns the string values of fields in object.
+func documentFields(object map[string]interface{}, fields []string) []*string {
+	res := make([]*string, 0, len(fields))
+	for _, field := range fields {
+		if v, ok := object[field].(string); ok {
+			res = append(res, &v)
+		}
+	}
+	return res
+}
+
+// err returns an error listing all conflicting definitions, if any.
+func (d *duplicateDetector) err() error {
+	if len(d.errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("conflicting definitions found in state files:\n  %s",
+		strings.Join(d.errs, "\n  "))
+}
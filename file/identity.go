@@ -0,0 +1,99 @@
+package file
+
+import (
+	"strings"
+
+	"github.com/kong/deck/types"
+	"github.com/kong/go-kong/kong"
+)
+
+// IdentityTagPrefix is the prefix of identity tags.
+// Entities without an ID in the state file are matched with the ones in
+// Kong by identity tag, e.g. "deck-identity:payments", then by name if
+// no entity in Kong has the tag. Renaming an entity with an identity tag
+// in the state file updates it instead of deleting and recreating it,
+// which keeps its plugins and references to it.
+// Names are unique in Kong: renames are sent concurrently, so a new name
+// must not belong to another entity in Kong, e.g. when two entities swap
+// names, or Kong rejects the update.
+const IdentityTagPrefix = "deck-identity:"
+
+// identityTag returns the identity tag among tags, "" if none.
+func identityTag(tags []*string) string {
+	for _, tag := range tags {
+		if tag != nil && strings.HasPrefix(*tag, IdentityTagPrefix) {
+			return *tag
+		}
+	}
+	return ""
+}
+
+// idByIdentityTag returns the ID of the entity of the current state of
+// entityType with the identity tag among tags, nil if none.
+func (b *stateBuilder) idByIdentityTag(entityType types.EntityType, tags []*string) (*string, error) {
+	tag := identityTag(tags)
+	if tag == "" {
+		return nil, nil
+	}
+	if b.identityIDs == nil {
+		b.identityIDs = map[types.EntityType]map[string]string{}
+	}
+	ids, ok := b.identityIDs[entityType]
+	if !ok {
+		var err error
+		if ids, err = b.currentIdentityIDs(entityType); err != nil {
+			return nil, err
+		}
+		b.identityIDs[entityType] = ids
+	}
+	if id, ok := ids[tag]; ok {
+		return kong.String(id), nil
+	}
+	return nil, nil
+}
+
+// currentIdentityIDs indexes the IDs of the entities of the current state
+// of entityType by identity tag.
+func (b *stateBuilder) currentIdentityIDs(entityType types.EntityType) (map[string]string, error) {
+	ids := map[string]string{}
+	add := func(tags []*string, id *string) {
+		if tag := identityTag(tags); tag != "" && id != nil {
+			ids[tag] = *id
+		}
+	}
+	switch entityType {
+	case types.Service:
+		services, err := b.currentState.Services.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range services {
+			add(s.Tags, s.ID)
+		}
+	case types.Route:
+		routes, err := b.currentState.Routes.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range routes {
+			add(r.Tags, r.ID)
+		}
+	case types.Upstream:
+		upstreams, err := b.currentState.Upstreams.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, u := range upstreams {
+			add(u.Tags, u.ID)
+		}
+	case types.Consumer:
+		consumers, err := b.currentState.Consumers.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range consumers {
+			add(c.Tags, c.ID)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,110 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/kong/deck/state"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilderMatchesRenamedEntitiesByIdentityTag(t *testing.T) {
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.Services.Add(state.Service{Service: kong.Service{
+		ID:   kong.String("svc-id"),
+		Name: kong.String("payments"),
+		Tags: kong.StringSlice("team-a", "deck-identity:payments"),
+	}}))
+	require.NoError(t, currentState.Routes.Add(state.Route{Route: kong.Route{
+		ID:   kong.String("route-id"),
+		Name: kong.String("payments-route"),
+		Tags: kong.StringSlice("deck-identity:payments-route"),
+	}}))
+	require.NoError(t, currentState.Consumers.Add(state.Consumer{Consumer: kong.Consumer{
+		ID:       kong.String("consumer-id"),
+		Username: kong.String("alice"),
+		Tags:     kong.StringSlice("deck-identity:alice"),
+	}}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			Services: []FService{{
+				Service: kong.Service{
+					Name: kong.String("billing"),
+					Host: kong.String("billing.example.com"),
+					Tags: kong.StringSlice("deck-identity:payments"),
+				},
+				Routes: []*FRoute{{Route: kong.Route{
+					Name:  kong.String("billing-route"),
+					Paths: kong.StringSlice("/billing"),
+					Tags:  kong.StringSlice("deck-identity:payments-route"),
+				}}},
+			}, {
+				Service: kong.Service{
+					Name: kong.String("untagged"),
+					Host: kong.String("untagged.example.com"),
+				},
+			}},
+			Consumers: []FConsumer{{Consumer: kong.Consumer{
+				Username: kong.String("alice.smith"),
+				Tags:     kong.StringSlice("deck-identity:alice"),
+			}}},
+		},
+		currentState: currentState,
+	}
+	raw, _, err := b.build()
+	require.NoError(t, err)
+
+	require.Len(t, raw.Services, 2)
+	assert.Equal(t, "svc-id", *raw.Services[0].ID)
+	assert.NotEqual(t, "svc-id", *raw.Services[1].ID)
+	require.Len(t, raw.Routes, 1)
+	assert.Equal(t, "route-id", *raw.Routes[0].ID)
+	assert.Equal(t, "svc-id", *raw.Routes[0].Service.ID)
+	require.Len(t, raw.Consumers, 1)
+	assert.Equal(t, "consumer-id", *raw.Consumers[0].ID)
+}
+
+// Kong rejects swapping names in a single sync, but entities are still
+// matched by identity tag over names.
+func TestBuilderMatchesSwappedNamesByIdentityTag(t *testing.T) {
+	currentState, err := state.NewKongState()
+	require.NoError(t, err)
+	require.NoError(t, currentState.Services.Add(state.Service{Service: kong.Service{
+		ID:   kong.String("blue-id"),
+		Name: kong.String("blue"),
+		Tags: kong.StringSlice("deck-identity:blue"),
+	}}))
+	require.NoError(t, currentState.Services.Add(state.Service{Service: kong.Service{
+		ID:   kong.String("green-id"),
+		Name: kong.String("green"),
+		Tags: kong.StringSlice("deck-identity:green"),
+	}}))
+
+	b := &stateBuilder{
+		targetContent: &Content{
+			Services: []FService{{
+				Service: kong.Service{
+					Name: kong.String("green"),
+					Host: kong.String("blue.example.com"),
+					Tags: kong.StringSlice("deck-identity:blue"),
+				},
+			}, {
+				Service: kong.Service{
+					Name: kong.String("blue"),
+					Host: kong.String("green.example.com"),
+					Tags: kong.StringSlice("deck-identity:green"),
+				},
+			}},
+		},
+		currentState: currentState,
+	}
+	raw, _, err := b.build()
+	require.NoError(t, err)
+
+	require.Len(t, raw.Services, 2)
+	assert.Equal(t, "blue-id", *raw.Services[0].ID)
+	assert.Equal(t, "green-id", *raw.Services[1].ID)
+}
@@ -0,0 +1,168 @@
+package file
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// JSONSchema returns the JSON schema of state files, for editors and CI
+// to validate them.
+// pluginSchemas holds the schemas of plugins returned by Kong, indexed by
+// plugin name: the configuration of these plugins is validated against
+// their schema, while the configuration of other plugins can be any object.
+func JSONSchema(pluginSchemas map[string]map[string]interface{}) ([]byte, error) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(kongJSONSchema), &schema); err != nil {
+		return nil, err
+	}
+	if len(pluginSchemas) > 0 {
+		definitions, _ := schema["definitions"].(map[string]interface{})
+		plugin, ok := definitions["FPlugin"]
+		if !ok {
+			return nil, fmt.Errorf("plugin definition not found in the schema of state files")
+		}
+		definitions["FPluginBase"] = plugin
+
+		names := make([]string, 0, len(pluginSchemas))
+		for name := range pluginSchemas {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		anyOf := make([]interface{}, 0, len(names)+1)
+		for _, name := range names {
+			config, err := pluginConfigJSONSchema(pluginSchemas[name])
+			if err != nil {
+				return nil, fmt.Errorf("converting schema of plugin %s: %w", name, err)
+			}
+			definitionName := "PluginConfig_" + name
+			definitions[definitionName] = config
+			anyOf = append(anyOf, pluginJSONSchema(
+				map[string]interface{}{"enum": []interface{}{name}},
+				map[string]interface{}{"$ref": "#/definitions/" + definitionName},
+			))
+		}
+		// plugins without schema
+		anyOf = append(anyOf, pluginJSONSchema(
+			map[string]interface{}{"not": map[string]interface{}{"enum": names}},
+			map[string]interface{}{"type": "object", "additionalProperties": true},
+		))
+		definitions["FPlugin"] = map[string]interface{}{"anyOf": anyOf}
+	}
+	b, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// pluginJSONSchema returns the schema of plugins whose name and
+// config match the given schemas.
+func pluginJSONSchema(name, config map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/definitions/FPluginBase"},
+			map[string]interface{}{
+				"properties": map[string]interface{}{
+					"name":   name,
+					"config": config,
+				},
+			},
+		},
+	}
+}
+
+// pluginConfigJSONSchema converts the config field of the schema of a
+// plugin, as returned by Kong, to a JSON schema.
+func pluginConfigJSONSchema(schema map[string]interface{}) (map[string]interface{}, error) {
+	for _, f := range schemaFieldList(schema["fields"]) {
+		for name, def := range f {
+			if name == "config" {
+				return luaFieldJSONSchema(def), nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("config field not found")
+}
+
+// schemaFieldList returns the fields of a Kong schema, which are a list
+// of objects holding a single field each.
+func schemaFieldList(v interface{}) []map[string]map[string]interface{} {
+	list, _ := v.([]interface{})
+	res := make([]map[string]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		field, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		f := map[string]map[string]interface{}{}
+		for name, def := range field {
+			if d, ok := def.(map[string]interface{}); ok {
+				f[name] = d
+			}
+		}
+		res = append(res, f)
+	}
+	return res
+}
+
+// luaFieldJSONSchema converts the definition of a field of a Kong schema
+// to a JSON schema.
+func luaFieldJSONSchema(def map[string]interface{}) map[string]interface{} {
+	res := map[string]interface{}{}
+	switch def["type"] {
+	case "string":
+		res["type"] = "string"
+		if oneOf, ok := def["one_of"].([]interface{}); ok {
+			res["enum"] = oneOf
+		}
+	case "integer":
+		res["type"] = "integer"
+	case "number":
+		res["type"] = "number"
+	case "boolean":
+		res["type"] = "boolean"
+	case "array", "set":
+		res["type"] = "array"
+		if elements, ok := def["elements"].(map[string]interface{}); ok {
+			res["items"] = luaFieldJSONSchema(elements)
+		}
+	case "map":
+		res["type"] = "object"
+		if values, ok := def["values"].(map[string]interface{}); ok {
+			res["additionalProperties"] = luaFieldJSONSchema(values)
+		}
+	case "record":
+		res["type"] = "object"
+		properties := map[string]interface{}{}
+		var required []string
+		for _, f := range schemaFieldList(def["fields"]) {
+			for name, fieldDef := range f {
+				properties[name] = luaFieldJSONSchema(fieldDef)
+				if _, hasDefault := fieldDef["default"]; fieldDef["required"] == true && !hasDefault {
+					required = append(required, name)
+				}
+			}
+		}
+		res["properties"] = properties
+		res["additionalProperties"] = false
+		if len(required) > 0 {
+			sort.Strings(required)
+			res["required"] = required
+		}
+	}
+	// fields which are not required can be null, as in dumps
+	if t, ok := res["type"]; ok && def["required"] != true {
+		res["type"] = []interface{}{t, "null"}
+		if enum, ok := res["enum"].([]interface{}); ok {
+			res["enum"] = append(enum, nil)
+		}
+	}
+	if description, ok := def["description"].(string); ok {
+		res["description"] = description
+	}
+	if value, ok := def["default"]; ok && value != nil {
+		res["default"] = value
+	}
+	return res
+}
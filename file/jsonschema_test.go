@@ -0,0 +1,84 @@
+package file
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+	"sigs.k8s.io/yaml"
+)
+
+const rateLimitingSchema = `{
+  "fields": [
+    {"protocols": {"type": "set", "elements": {"type": "string"}}},
+    {"config": {"type": "record", "required": true, "fields": [
+      {"minute": {"type": "number"}},
+      {"policy": {"type": "string", "default": "local", "one_of": ["local", "cluster", "redis"]}},
+      {"limit_by": {"type": "string", "required": true}},
+      {"redis": {"type": "record", "fields": [
+        {"host": {"type": "string"}},
+        {"port": {"type": "integer", "default": 6379}}
+      ]}},
+      {"headers": {"type": "map", "keys": {"type": "string"}, "values": {"type": "string"}}}
+    ]}}
+  ]
+}`
+
+func validateWithJSONSchema(t *testing.T, schema []byte, state string) *gojsonschema.Result {
+	t.Helper()
+	doc, err := yaml.YAMLToJSON([]byte(state))
+	require.NoError(t, err)
+	result, err := gojsonschema.Validate(gojsonschema.NewBytesLoader(schema),
+		gojsonschema.NewBytesLoader(doc))
+	require.NoError(t, err)
+	return result
+}
+
+func TestJSONSchema(t *testing.T) {
+	schema, err := JSONSchema(nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, kongJSONSchema, string(schema))
+
+	var pluginSchema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(rateLimitingSchema), &pluginSchema))
+	schema, err = JSONSchema(map[string]map[string]interface{}{"rate-limiting": pluginSchema})
+	require.NoError(t, err)
+
+	result := validateWithJSONSchema(t, schema, `
+_format_version: "3.0"
+services:
+- name: svc
+  host: example.com
+  plugins:
+  - name: rate-limiting
+    config:
+      minute: 10
+      limit_by: consumer
+      policy: redis
+      redis:
+        host: redis
+        port: null
+      headers:
+        x-foo: bar
+  - name: custom-plugin
+    config:
+      anything: goes
+`)
+	assert.True(t, result.Valid(), result.Errors())
+
+	for name, config := range map[string]string{
+		"typo":          "{minut: 10, limit_by: consumer}",
+		"wrong type":    "{minute: ten, limit_by: consumer}",
+		"invalid enum":  "{policy: memcached, limit_by: consumer}",
+		"missing field": "{minute: 10}",
+	} {
+		result := validateWithJSONSchema(t, schema, `
+_format_version: "3.0"
+plugins:
+- name: rate-limiting
+  config: `+config)
+		assert.False(t, result.Valid(), name)
+	}
+}
@@ -0,0 +1,269 @@
+package file
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kong/deck/types"
+	"github.com/kong/deck/utils"
+)
+
+// DeclaredFields holds the fields set in state files for each entity,
+// as dotted JSON paths like `config.minute`, keyed by entity type and ID.
+// Fields filled with defaults while rendering are not declared.
+type DeclaredFields map[string][]string
+
+func declaredFieldsKey(kind types.EntityType, id string) string {
+	return string(kind) + ":" + id
+}
+
+// declare records the fields set in entity.
+// It must be called before defaults are filled.
+func (d DeclaredFields) declare(kind types.EntityType, id *string, entity interface{}) error {
+	if d == nil || utils.Empty(id) {
+		return nil
+	}
+	object, err := toJSONObject(entity)
+	if err != nil {
+		return fmt.Errorf("recording fields of %s %s: %w", kind, *id, err)
+	}
+	var paths []string
+	collectPaths(object, "", &paths)
+	sort.Strings(paths)
+	d[declaredFieldsKey(kind, *id)] = paths
+	return nil
+}
+
+// collectPaths appends the paths of the leaves of object to paths.
+// Arrays are leaves; empty objects declare nothing.
+func collectPaths(object map[string]interface{}, prefix string, paths *[]string) {
+	for key, value := range object {
+		path := prefix + key
+		if nested, ok := value.(map[string]interface{}); ok {
+			collectPaths(nested, path+".", paths)
+			continue
+		}
+		*paths = append(*paths, path)
+	}
+}
+
+// LastApplied is the configuration last applied by sync. It tells fields
+// removed from state files, which are reset to their defaults, from
+// fields never managed by state files, which keep their live values.
+type LastApplied struct {
+	// Workspaces holds the declared fields of the last sync of each
+	// workspace. The default workspace and Konnect use the empty name.
+	Workspaces map[string]DeclaredFields `json:"workspaces"`
+}
+
+// Fields returns the fields declared by the last sync of workspace.
+func (l *LastApplied) Fields(workspace string) DeclaredFields {
+	return l.Workspaces[workspace]
+}
+
+// Set records fields as the ones declared by the last sync of workspace.
+func (l *LastApplied) Set(workspace string, fields DeclaredFields) {
+	if l.Workspaces == nil {
+		l.Workspaces = map[string]DeclaredFields{}
+	}
+	l.Workspaces[workspace] = fields
+}
+
+// ReadLastApplied reads the last-applied configuration from filename.
+// A missing file is an empty configuration, as before the first sync.
+func ReadLastApplied(filename string) (*LastApplied, error) {
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return &LastApplied{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading last-applied configuration: %w", err)
+	}
+	var l LastApplied
+	if err := json.Unmarshal(b, &l); err != nil {
+		return nil, fmt.Errorf("parsing last-applied configuration %s: %w", filename, err)
+	}
+	return &l, nil
+}
+
+// WriteLastApplied writes l to filename.
+func WriteLastApplied(filename string, l *LastApplied) error {
+	b, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filename, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing last-applied configuration: %w", err)
+	}
+	return nil
+}
+
+// unmergedFields are never taken from the live state.
+var unmergedFields = map[string]bool{
+	"id":         true,
+	"created_at": true,
+	"updated_at": true,
+}
+
+// MergeLiveFields performs a three-way merge of target, the state
+// rendered from state files, with live, the current state of Kong.
+// Fields of services, routes, upstreams, consumers and plugins which
+// are neither set in state files nor were set when lastApplied was
+// recorded take their live value: changes made outside of decK to
+// fields it does not manage are neither overwritten nor reported as
+// differences. Fields removed from state files since lastApplied are
+// reset to their defaults, like all fields without three-way merge.
+// target is modified in place.
+func MergeLiveFields(target, live *utils.KongRawState, declared, lastApplied DeclaredFields) error {
+	m := liveMerger{declared: declared, lastApplied: lastApplied}
+	if err := mergeLiveEntities(m, types.Service, target.Services, live.Services); err != nil {
+		return err
+	}
+	if err := mergeLiveEntities(m, types.Route, target.Routes, live.Routes); err != nil {
+		return err
+	}
+	if err := mergeLiveEntities(m, types.Upstream, target.Upstreams, live.Upstreams); err != nil {
+		return err
+	}
+	if err := mergeLiveEntities(m, types.Consumer, target.Consumers, live.Consumers); err != nil {
+		return err
+	}
+	return mergeLiveEntities(m, types.Plugin, target.Plugins, live.Plugins)
+}
+
+type liveMerger struct {
+	declared, lastApplied DeclaredFields
+}
+
+// managed returns the fields of an entity which are set in state files
+// or were set when the configuration was last applied.
+func (m liveMerger) managed(key string) []string {
+	return append(append([]string{}, m.declared[key]...), m.lastApplied[key]...)
+}
+
+func mergeLiveEntities[T any](m liveMerger, kind types.EntityType, target, live []*T) error {
+	liveByID := make(map[string]*T, len(live))
+	for _, entity := range live {
+		if id := entityID(entity); id != "" {
+			liveByID[id] = entity
+		}
+	}
+	for _, entity := range target {
+		id := entityID(entity)
+		liveEntity, ok := liveByID[id]
+		if !ok {
+			continue
+		}
+		targetObject, err := toJSONObject(entity)
+		if err != nil {
+			return err
+		}
+		liveObject, err := toJSONObject(liveEntity)
+		if err != nil {
+			return err
+		}
+		managed := m.managed(declaredFieldsKey(kind, id))
+		if !mergeLiveObject(targetObject, liveObject, "", managed) {
+			continue
+		}
+		b, err := json.Marshal(targetObject)
+		if err != nil {
+			return err
+		}
+		var merged T
+		if err := json.Unmarshal(b, &merged); err != nil {
+			return fmt.Errorf("merging live fields of %s %s: %w", kind, id, err)
+		}
+		*entity = merged
+	}
+	return nil
+}
+
+// mergeLiveObject sets the fields of live which are not managed into
+// target, and returns whether target changed.
+func mergeLiveObject(target, live map[string]interface{}, prefix string, managed []string) bool {
+	changed := false
+	keys := make(map[string]bool, len(target)+len(live))
+	for key := range target {
+		keys[key] = true
+	}
+	for key := range live {
+		keys[key] = true
+	}
+	for key := range keys {
+		path := prefix + key
+		if prefix == "" && unmergedFields[key] {
+			continue
+		}
+		exact, nested := managedPath(path, managed)
+		if exact {
+			continue
+		}
+		liveValue, inLive := live[key]
+		if nested {
+			targetObject, ok1 := target[key].(map[string]interface{})
+			liveObject, ok2 := liveValue.(map[string]interface{})
+			if ok1 && ok2 && mergeLiveObject(targetObject, liveObject, path+".", managed) {
+				changed = true
+			}
+			continue
+		}
+		if !inLive {
+			if _, ok := target[key]; ok {
+				delete(target, key)
+				changed = true
+			}
+			continue
+		}
+		if !reflect.DeepEqual(target[key], liveValue) {
+			target[key] = liveValue
+			changed = true
+		}
+	}
+	return changed
+}
+
+// managedPath reports whether path is managed itself, or holds managed
+// fields.
+func managedPath(path string, managed []string) (exact, nested bool) {
+	for _, p := range managed {
+		if p == path {
+			return true, false
+		}
+		if strings.HasPrefix(p, path+".") {
+			nested = true
+		}
+	}
+	return false, nested
+}
+
+// entityID returns the ID of a go-kong entity, or an empty string.
+func entityID(entity interface{}) string {
+	v := reflect.Indirect(reflect.ValueOf(entity))
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	field := v.FieldByName("ID")
+	if !field.IsValid() || field.Kind() != reflect.Ptr || field.IsNil() {
+		return ""
+	}
+	id, _ := field.Elem().Interface().(string)
+	return id
+}
+
+func toJSONObject(v interface{}) (map[string]interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]interface{}
+	if err := json.Unmarshal(b, &object); err != nil {
+		return nil, err
+	}
+	return object, nil
+}
@@ -0,0 +1,79 @@
+package file
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/kong/deck/types"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeLiveFields(t *testing.T) {
+	declared := DeclaredFields{}
+	require.NoError(t, declared.declare(types.Plugin, kong.String("p1"), kong.Plugin{
+		Name:   kong.String("rate-limiting"),
+		Config: kong.Configuration{"minute": 10},
+	}))
+	assert.Equal(t, []string{"config.minute", "name"}, declared["plugin:p1"])
+
+	target := &utils.KongRawState{
+		Plugins: []*kong.Plugin{{
+			ID:      kong.String("p1"),
+			Name:    kong.String("rate-limiting"),
+			Enabled: kong.Bool(true),
+			Config:  kong.Configuration{"minute": 10.0, "hour": nil, "policy": "local"},
+		}},
+		Services: []*kong.Service{{
+			ID:      kong.String("s1"),
+			Name:    kong.String("svc1"),
+			Retries: kong.Int(5),
+		}},
+	}
+	live := &utils.KongRawState{
+		Plugins: []*kong.Plugin{{
+			ID:      kong.String("p1"),
+			Name:    kong.String("rate-limiting"),
+			Enabled: kong.Bool(false),
+			Config:  kong.Configuration{"minute": 20.0, "hour": 100.0, "policy": "local"},
+			Tags:    kong.StringSlice("team-a"),
+		}},
+		Services: []*kong.Service{{
+			ID:      kong.String("s1"),
+			Name:    kong.String("svc1"),
+			Retries: kong.Int(10),
+		}},
+	}
+	lastApplied := DeclaredFields{
+		"plugin:p1":  {"config.hour", "name"},
+		"service:s1": {"name", "retries"},
+	}
+	require.NoError(t, MergeLiveFields(target, live, declared, lastApplied))
+
+	// config.minute is declared, config.hour was declared: both are enforced,
+	// while enabled and tags are not managed and keep their live values
+	assert.Equal(t, &kong.Plugin{
+		ID:      kong.String("p1"),
+		Name:    kong.String("rate-limiting"),
+		Enabled: kong.Bool(false),
+		Config:  kong.Configuration{"minute": 10.0, "hour": nil, "policy": "local"},
+		Tags:    kong.StringSlice("team-a"),
+	}, target.Plugins[0])
+	// retries was removed from the state files
+	assert.Equal(t, 5, *target.Services[0].Retries)
+}
+
+func TestLastAppliedReadWrite(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "last-applied.json")
+	l, err := ReadLastApplied(filename)
+	require.NoError(t, err)
+	assert.Empty(t, l.Fields(""))
+
+	l.Set("ws1", DeclaredFields{"service:s1": {"host", "name"}})
+	require.NoError(t, WriteLastApplied(filename, l))
+	l, err = ReadLastApplied(filename)
+	require.NoError(t, err)
+	assert.Equal(t, DeclaredFields{"service:s1": {"host", "name"}}, l.Fields("ws1"))
+}
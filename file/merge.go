@@ -0,0 +1,87 @@
+package file
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Document is a state file parsed as a generic document, so that
+// templates and fields unknown to decK are preserved.
+type Document struct {
+	// Source names the document in errors, usually its filename.
+	Source string
+	Data   map[string]interface{}
+}
+
+// MergeDocuments merges documents into a single one.
+// Lists of entities are concatenated, and _plugin_configs are merged.
+// Plugin configs and other top-level fields, like _format_version or
+// _workspace, must have the same value in all the documents defining them.
+// Entities defined in more than one document are reported as conflicts.
+func MergeDocuments(docs []Document) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	sources := map[string]string{}
+	detector := newDuplicateDetector()
+	var errs []string
+	for _, doc := range docs {
+		detector.addDocument(doc.Source, doc.Data)
+		keys := make([]string, 0, len(doc.Data))
+		for key := range doc.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value := doc.Data[key]
+			if configs, isMap := value.(map[string]interface{}); isMap && key == "_plugin_configs" {
+				merged, _ := res[key].(map[string]interface{})
+				if merged == nil {
+					merged = map[string]interface{}{}
+					res[key] = merged
+				}
+				names := make([]string, 0, len(configs))
+				for name := range configs {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					sourceKey := key + "." + name
+					previous, ok := merged[name]
+					if !ok {
+						merged[name] = configs[name]
+						sources[sourceKey] = doc.Source
+						continue
+					}
+					if !reflect.DeepEqual(previous, configs[name]) {
+						errs = append(errs, fmt.Sprintf("%s has different values in %s and %s",
+							sourceKey, sources[sourceKey], doc.Source))
+					}
+				}
+				continue
+			}
+			previous, ok := res[key]
+			if !ok {
+				res[key] = value
+				sources[key] = doc.Source
+				continue
+			}
+			if list, isList := value.([]interface{}); isList {
+				if previousList, ok := previous.([]interface{}); ok {
+					res[key] = append(append([]interface{}{}, previousList...), list...)
+					continue
+				}
+			}
+			if !reflect.DeepEqual(previous, value) {
+				errs = append(errs, fmt.Sprintf("%s has different values in %s and %s",
+					key, sources[key], doc.Source))
+			}
+		}
+	}
+	errs = append(errs, detector.errs...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("conflicting definitions found in state files:\n  %s",
+			strings.Join(errs, "\n  "))
+	}
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func parseDocument(t *testing.T, source, content string) Document {
+	t.Helper()
+	doc := Document{Source: source}
+	require.NoError(t, yaml.Unmarshal([]byte(content), &doc.Data))
+	return doc
+}
+
+func TestMergeDocuments(t *testing.T) {
+	a := parseDocument(t, "a.yaml", `
+_format_version: "3.0"
+services:
+- name: svc1
+  host: ${{ env "DECK_SVC1_HOST" }}
+  routes:
+  - name: r1
+_plugin_configs:
+  common:
+    minute: 10
+`)
+	b := parseDocument(t, "b.yaml", `
+_format_version: "3.0"
+services:
+- name: svc2
+  host: example.com
+consumers:
+- username: alice
+_plugin_configs:
+  other:
+    hour: 10
+`)
+	merged, err := MergeDocuments([]Document{a, b})
+	require.NoError(t, err)
+	expected := parseDocument(t, "", `
+_format_version: "3.0"
+services:
+- name: svc1
+  host: ${{ env "DECK_SVC1_HOST" }}
+  routes:
+  - name: r1
+- name: svc2
+  host: example.com
+consumers:
+- username: alice
+_plugin_configs:
+  common:
+    minute: 10
+  other:
+    hour: 10
+`)
+	assert.Equal(t, expected.Data, merged)
+	assert.Len(t, a.Data["services"], 1)
+
+	c := parseDocument(t, "c.yaml", `
+_format_version: "1.1"
+_workspace: team-c
+services:
+- name: svc1
+routes:
+- name: r1
+_plugin_configs:
+  common:
+    minute: 20
+`)
+	_, err = MergeDocuments([]Document{a, b, c})
+	require.Error(t, err)
+	assert.Equal(t, `conflicting definitions found in state files:
+  _format_version has different values in a.yaml and c.yaml
+  _plugin_configs.common has different values in a.yaml and c.yaml
+  route 'r1' is defined in both a.yaml and c.yaml
+  service 'svc1' is defined in both a.yaml and c.yaml`, err.Error())
+}
+
+func TestMergeDocumentsPluginConfigs(t *testing.T) {
+	a := parseDocument(t, "a.yaml", `
+_plugin_configs:
+  common:
+    minute: 10
+`)
+	b := parseDocument(t, "b.yaml", `
+_plugin_configs:
+  common:
+    minute: 10
+`)
+	merged, err := MergeDocuments([]Document{a, b})
+	require.NoError(t, err)
+	assert.Equal(t, a.Data, merged)
+
+	c := parseDocument(t, "c.yaml", `
+_plugin_configs:
+  common:
+    minute: 20
+`)
+	_, err = MergeDocuments([]Document{a, b, c})
+	require.EqualError(t, err, `conflicting definitions found in state files:
+  _plugin_configs.common has different values in a.yaml and c.yaml`)
+}
@@ -23,6 +23,9 @@ var (
 type RenderConfig struct {
 	CurrentState *state.KongState
 	KongVersion  semver.Version
+	// DeclaredFields, if not nil, is filled with the fields set in the
+	// file content for each entity, for MergeLiveFields.
+	DeclaredFields DeclaredFields
 }
 
 // GetContentFromFiles reads in a file with a slice of filenames and constructs
@@ -75,6 +78,7 @@ func Get(ctx context.Context, fileContent *Content, opt RenderConfig, dumpConfig
 	builder.targetContent = fileContent
 	builder.currentState = opt.CurrentState
 	builder.kongVersion = opt.KongVersion
+	builder.declaredFields = opt.DeclaredFields
 	builder.client = wsClient
 	builder.ctx = ctx
 	builder.skipCACerts = dumpConfig.SkipCACerts
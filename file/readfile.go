@@ -3,10 +3,12 @@ package file
 import (
 	"bufio"
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
@@ -30,14 +32,17 @@ func getContent(filenames []string) (*Content, error) {
 		allReaders = append(allReaders, readers...)
 	}
 	var res Content
+	duplicates := newDuplicateDetector()
 	for _, r := range allReaders {
+		source := readerName(r)
 		content, err := readContent(r)
 		if err != nil {
-			return nil, fmt.Errorf("reading file: %w", err)
+			return nil, fmt.Errorf("reading file %s: %w", source, err)
 		}
 		if content.Workspace != "" {
 			workspaces = append(workspaces, content.Workspace)
 		}
+		duplicates.addContent(source, content)
 		err = mergo.Merge(&res, content, mergo.WithAppendSlice)
 		if err != nil {
 			return nil, fmt.Errorf("merging file contents: %w", err)
@@ -46,9 +51,53 @@ func getContent(filenames []string) (*Content, error) {
 	if err := validateWorkspaces(workspaces); err != nil {
 		return nil, err
 	}
+	if err := duplicates.err(); err != nil {
+		return nil, err
+	}
 	return &res, nil
 }
 
+// GroupFilesByWorkspace reads the YAML and JSON files in the directories or
+// files of filenames, and groups the files by the workspace set in their
+// _workspace field. Files without _workspace are grouped under the empty
+// workspace, i.e. the default one.
+func GroupFilesByWorkspace(filenames []string) (map[string][]string, error) {
+	res := map[string][]string{}
+	for _, fileOrDir := range filenames {
+		if fileOrDir == "-" {
+			return nil, fmt.Errorf("state files cannot be read from stdin when grouping by workspace")
+		}
+		readers, err := getReaders(fileOrDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range readers {
+			source := readerName(r)
+			content, err := readContent(r)
+			if err != nil {
+				return nil, fmt.Errorf("reading file %s: %w", source, err)
+			}
+			res[content.Workspace] = append(res[content.Workspace], source)
+		}
+	}
+	return res, nil
+}
+
+// fileReader is a reader of a state file, which keeps
+// the name of the file for error reporting.
+type fileReader struct {
+	*bufio.Reader
+	name string
+}
+
+// readerName returns the name of the file read by r.
+func readerName(r io.Reader) string {
+	if f, ok := r.(*fileReader); ok {
+		return f.name
+	}
+	return "stdin"
+}
+
 // getReaders returns back io.Readers representing all the YAML and JSON
 // files in a directory. If fileOrDir is a single file, then it
 // returns back the reader for the file.
@@ -81,7 +130,7 @@ func getReaders(fileOrDir string) ([]io.Reader, error) {
 		if err != nil {
 			return nil, fmt.Errorf("opening file: %w", err)
 		}
-		res = append(res, bufio.NewReader(f))
+		res = append(res, &fileReader{Reader: bufio.NewReader(f), name: file})
 	}
 	return res, nil
 }
@@ -101,7 +150,7 @@ func readContent(reader io.Reader) (*Content, error) {
 	if err != nil {
 		return nil, err
 	}
-	renderedContent, err := renderTemplate(string(contentBytes))
+	renderedContent, err := renderTemplate(readerName(reader), string(contentBytes))
 	if err != nil {
 		return nil, fmt.Errorf("parsing file: %w", err)
 	}
@@ -158,8 +207,75 @@ func toFloat(key string) (float64, error) {
 	return strconv.ParseFloat(key, 64)
 }
 
-func renderTemplate(content string) (string, error) {
-	t := template.New("state").Funcs(template.FuncMap{
+// TemplateError is returned when environment variables cannot be
+// substituted in a state file.
+type TemplateError struct {
+	File   string
+	Line   int
+	Column int
+	// Source is the line of the file where the error occurred.
+	Source string
+	Err    error
+}
+
+func (e *TemplateError) Error() string {
+	location := e.File
+	if e.Line > 0 {
+		location += ":" + strconv.Itoa(e.Line)
+		if e.Column > 0 {
+			location += ":" + strconv.Itoa(e.Column)
+		}
+	}
+	msg := fmt.Sprintf("%s: %v", location, e.Err)
+	if e.Source != "" {
+		msg += fmt.Sprintf("\n  %d | %s", e.Line, e.Source)
+	}
+	return msg
+}
+
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// templateErrorLocation matches the location and message of errors
+// returned by text/template after the template name,
+// e.g. `:3:12: executing "kong.yaml" at <env "FOO">: error calling env: ...`.
+var templateErrorLocation = regexp.MustCompile(`^:(\d+)(?::(\d+))?: (.*)$`)
+
+// templateErrorCall matches the error returned by a template function.
+var templateErrorCall = regexp.MustCompile(`error calling \w+: (.*)$`)
+
+// newTemplateError converts an error returned by text/template for the
+// template name into a TemplateError.
+func newTemplateError(name, content string, err error) error {
+	res := &TemplateError{File: name, Err: err}
+	prefix := "template: " + name
+	msg := err.Error()
+	if !strings.HasPrefix(msg, prefix) {
+		return res
+	}
+	m := templateErrorLocation.FindStringSubmatch(strings.TrimPrefix(msg, prefix))
+	if m == nil {
+		return res
+	}
+	res.Line, _ = strconv.Atoi(m[1])
+	res.Column, _ = strconv.Atoi(m[2])
+	msg = m[3]
+	if call := templateErrorCall.FindStringSubmatch(msg); call != nil {
+		msg = call[1]
+	}
+	res.Err = errors.New(msg)
+	lines := strings.Split(content, "\n")
+	if res.Line > 0 && res.Line <= len(lines) {
+		res.Source = strings.TrimRight(lines[res.Line-1], "\r")
+	}
+	return res
+}
+
+// renderTemplate substitutes environment variables in the
+// content of the file name.
+func renderTemplate(name, content string) (string, error) {
+	t := template.New(name).Funcs(template.FuncMap{
 		"env":     getPrefixedEnvVar,
 		"toBool":  toBool,
 		"toInt":   toInt,
@@ -167,12 +283,12 @@ func renderTemplate(content string) (string, error) {
 	}).Delims("${{", "}}")
 	t, err := t.Parse(content)
 	if err != nil {
-		return "", err
+		return "", newTemplateError(name, content, err)
 	}
 	var buffer bytes.Buffer
 	err = t.Execute(&buffer, nil)
 	if err != nil {
-		return "", err
+		return "", newTemplateError(name, content, err)
 	}
 	return buffer.String(), nil
 }
@@ -4,6 +4,7 @@ import (
 	"io"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/kong/deck/utils"
@@ -555,3 +556,68 @@ versions:
 		t.Errorf("yamlUnmarshal() expected type: %T, got: %T", stringToInterfaceMap, element)
 	}
 }
+
+func Test_getContentDuplicates(t *testing.T) {
+	_, err := getContent([]string{"testdata/duplicates"})
+	if err == nil {
+		t.Fatal("getContent() expected an error for conflicting definitions")
+	}
+	for _, want := range []string{
+		"service 'svc1' is defined in both testdata/duplicates/team-a.yaml and testdata/duplicates/team-b.yaml",
+		"route 'r2' is defined more than once in testdata/duplicates/team-b.yaml",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("getContent() error = %v, want it to contain %q", err, want)
+		}
+	}
+
+	// reading the same file twice is a conflict as well
+	_, err = getContent([]string{"testdata/duplicates/team-a.yaml", "testdata/duplicates/team-a.yaml"})
+	if err == nil {
+		t.Fatal("getContent() expected an error for a file read twice")
+	}
+}
+
+func Test_getContentErrorsReferenceFile(t *testing.T) {
+	_, err := getContent([]string{"testdata/badyaml"})
+	if err == nil || !strings.Contains(err.Error(), "testdata/badyaml") {
+		t.Errorf("getContent() error = %v, want it to reference the file", err)
+	}
+}
+
+func Test_getContentTemplateErrors(t *testing.T) {
+	_, err := getContent([]string{"testdata/bad-env-var/file.yaml"})
+	var templateErr *TemplateError
+	require.ErrorAs(t, err, &templateErr)
+	require.Equal(t, "testdata/bad-env-var/file.yaml", templateErr.File)
+	require.Equal(t, 3, templateErr.Line)
+	require.Equal(t, `  host: ${{ env "SVC2_HOST" }}`, templateErr.Source)
+	require.Equal(t, "environment variables in the state file must be prefixed "+
+		"with 'DECK_', found: 'SVC2_HOST'", templateErr.Err.Error())
+	require.Contains(t, err.Error(), "testdata/bad-env-var/file.yaml:3:")
+
+	_, err = renderTemplate("kong.yaml", "services:\n- name: ${{ secret \"DECK_FOO\" }}\n")
+	require.ErrorAs(t, err, &templateErr)
+	require.Equal(t, "kong.yaml", templateErr.File)
+	require.Equal(t, 2, templateErr.Line)
+	require.Equal(t, `function "secret" not defined`, templateErr.Err.Error())
+}
+
+func TestGroupFilesByWorkspace(t *testing.T) {
+	got, err := GroupFilesByWorkspace([]string{"testdata/differentworkspace", "testdata/config.yaml"})
+	if err != nil {
+		t.Fatalf("GroupFilesByWorkspace() unexpected error: %v", err)
+	}
+	want := map[string][]string{
+		"bar": {"testdata/differentworkspace/bar.yaml"},
+		"foo": {"testdata/differentworkspace/foo.yaml"},
+		"":    {"testdata/config.yaml"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupFilesByWorkspace() = %v, want %v", got, want)
+	}
+
+	if _, err := GroupFilesByWorkspace([]string{"-"}); err == nil {
+		t.Error("GroupFilesByWorkspace() expected an error when reading from stdin")
+	}
+}
@@ -0,0 +1,104 @@
+package file
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SplitBy defines how SplitDocument groups entities.
+type SplitBy string
+
+const (
+	// SplitByType groups entities by type: services, routes, plugins...
+	SplitByType SplitBy = "type"
+	// SplitByTag groups entities by tag.
+	SplitByTag SplitBy = "tag"
+)
+
+// UntaggedGroup is the group of entities without tags when splitting
+// by tag.
+const UntaggedGroup = "untagged"
+
+var unsafeGroupNameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// SplitDocument splits a state file into smaller ones, indexed by group
+// name. Group names are safe to use as filenames.
+//
+// When splitting by type, each top-level list of entities, like services
+// or consumers, is written to its own group. Nested entities stay with
+// their parent.
+// When splitting by tag, entities are grouped by their first tag which is
+// not a select tag of the file. Entities without such tag and
+// _plugin_configs are grouped in UntaggedGroup.
+//
+// Other top-level fields, like _format_version or _info, are copied to
+// all groups, so that each group is a valid state file on its own.
+func SplitDocument(doc map[string]interface{}, by SplitBy) (map[string]map[string]interface{}, error) {
+	if by != SplitByType && by != SplitByTag {
+		return nil, fmt.Errorf("invalid split '%s', must be one of: %s, %s",
+			by, SplitByType, SplitByTag)
+	}
+	header := map[string]interface{}{}
+	for key, value := range doc {
+		if strings.HasPrefix(key, "_") && key != "_plugin_configs" {
+			header[key] = value
+		}
+	}
+	selectTags := map[string]bool{}
+	if info, ok := doc["_info"].(map[string]interface{}); ok {
+		tags, _ := info["select_tags"].([]interface{})
+		for _, tag := range tags {
+			if s, ok := tag.(string); ok {
+				selectTags[s] = true
+			}
+		}
+	}
+
+	res := map[string]map[string]interface{}{}
+	group := func(name string) map[string]interface{} {
+		name = unsafeGroupNameChars.ReplaceAllString(name, "_")
+		g, ok := res[name]
+		if !ok {
+			g = make(map[string]interface{}, len(header)+1)
+			for key, value := range header {
+				g[key] = value
+			}
+			res[name] = g
+		}
+		return g
+	}
+	for key, value := range doc {
+		if _, ok := header[key]; ok {
+			continue
+		}
+		if by == SplitByType {
+			group(strings.TrimPrefix(key, "_"))[key] = value
+			continue
+		}
+		items, ok := value.([]interface{})
+		if !ok {
+			group(UntaggedGroup)[key] = value
+			continue
+		}
+		for _, item := range items {
+			g := group(entityGroupTag(item, selectTags))
+			list, _ := g[key].([]interface{})
+			g[key] = append(list, item)
+		}
+	}
+	return res, nil
+}
+
+// entityGroupTag returns the first tag of entity which is not a select
+// tag, or UntaggedGroup.
+func entityGroupTag(entity interface{}, selectTags map[string]bool) string {
+	object, _ := entity.(map[string]interface{})
+	tags, _ := object["tags"].([]interface{})
+	for _, tag := range tags {
+		if s, ok := tag.(string); ok && s != "" && !selectTags[s] {
+			return s
+		}
+	}
+	return UntaggedGroup
+}
@@ -0,0 +1,94 @@
+package file
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const splitTestState = `
+_format_version: "3.0"
+_info:
+  select_tags:
+  - managed
+services:
+- name: svc1
+  tags: [managed, team/a]
+  routes:
+  - name: r1
+- name: svc2
+  tags: [managed]
+consumers:
+- username: alice
+  tags: [team/a]
+_plugin_configs:
+  common:
+    minute: 10
+`
+
+func TestSplitDocument(t *testing.T) {
+	doc := parseDocument(t, "kong.yaml", splitTestState)
+
+	_, err := SplitDocument(doc.Data, "size")
+	assert.Error(t, err)
+
+	groups, err := SplitDocument(doc.Data, SplitByType)
+	require.NoError(t, err)
+	assert.Len(t, groups, 3)
+	assert.Equal(t, parseDocument(t, "", `
+_format_version: "3.0"
+_info:
+  select_tags:
+  - managed
+services:
+- name: svc1
+  tags: [managed, team/a]
+  routes:
+  - name: r1
+- name: svc2
+  tags: [managed]
+`).Data, groups["services"])
+	assert.Contains(t, groups["plugin_configs"], "_plugin_configs")
+	assert.Contains(t, groups["consumers"], "consumers")
+
+	groups, err = SplitDocument(doc.Data, SplitByTag)
+	require.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, parseDocument(t, "", `
+_format_version: "3.0"
+_info:
+  select_tags:
+  - managed
+services:
+- name: svc1
+  tags: [managed, team/a]
+  routes:
+  - name: r1
+consumers:
+- username: alice
+  tags: [team/a]
+`).Data, groups["team_a"])
+	assert.Equal(t, parseDocument(t, "", `
+_format_version: "3.0"
+_info:
+  select_tags:
+  - managed
+services:
+- name: svc2
+  tags: [managed]
+_plugin_configs:
+  common:
+    minute: 10
+`).Data, groups[UntaggedGroup])
+
+	// merging the split files gives back the original state
+	var docs []Document
+	for name, data := range groups {
+		docs = append(docs, Document{Source: name, Data: data})
+	}
+	merged, err := MergeDocuments(docs)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, doc.Data["services"], merged["services"])
+	assert.Equal(t, doc.Data["_plugin_configs"], merged["_plugin_configs"])
+}
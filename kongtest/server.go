@@ -0,0 +1,401 @@
+// Package kongtest provides an in-memory emulation of the core of
+// Kong's Admin API, meant to unit-test code built on go-kong or deck
+// without running Kong.
+//
+// The Server supports CRUD operations on the core entities, nested
+// endpoints such as /services/{service}/routes, pagination, filtering
+// by tags, workspaces, and the 400/404/409 semantics of Kong.
+// It does not validate entities against Kong's schemas nor fill
+// default values.
+package kongtest
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/kong/go-kong/kong"
+)
+
+const (
+	defaultVersion   = "3.4.0"
+	defaultWorkspace = "default"
+	defaultPageSize  = 100
+	maxPageSize      = 1000
+)
+
+// Server is a fake Kong Admin API backed by an httptest.Server.
+type Server struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	version string
+	stores  map[string]*store
+}
+
+// Option customizes a Server.
+type Option func(*Server)
+
+// WithVersion sets the version of Kong reported by the Server.
+func WithVersion(version string) Option {
+	return func(s *Server) {
+		s.version = version
+	}
+}
+
+// NewServer starts a Server.
+// The caller should call Close when finished, to shut it down.
+func NewServer(opts ...Option) *Server {
+	s := &Server{version: defaultVersion}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.Reset()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// KongClient returns a go-kong Client sending requests to the Server.
+func (s *Server) KongClient() (*kong.Client, error) {
+	return kong.NewClient(kong.String(s.URL), s.Client())
+}
+
+// Reset deletes all entities and workspaces.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stores = map[string]*store{defaultWorkspace: newStore()}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *apiError
+	if !errors.As(err, &apiErr) {
+		apiErr = &apiError{status: http.StatusInternalServerError, message: err.Error()}
+	}
+	writeJSON(w, apiErr.status, map[string]interface{}{"message": apiErr.message})
+}
+
+func isKnownRoot(segment string) bool {
+	if _, ok := collections[segment]; ok {
+		return true
+	}
+	return segment == "status"
+}
+
+// workspaceStore returns the store of a workspace, creating it
+// if the workspace exists in the default store.
+func (s *Server) workspaceStore(name string) (*store, error) {
+	if st, ok := s.stores[name]; ok {
+		return st, nil
+	}
+	if _, ws := s.stores[defaultWorkspace].find("workspaces", name); ws != nil {
+		st := newStore()
+		s.stores[name] = st
+		return st, nil
+	}
+	return nil, &apiError{
+		status:  http.StatusNotFound,
+		message: fmt.Sprintf("Workspace '%s' not found", name),
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := strings.Trim(r.URL.Path, "/")
+	var segments []string
+	if path != "" {
+		segments = strings.Split(path, "/")
+	}
+	workspace := defaultWorkspace
+	if len(segments) > 1 && !isKnownRoot(segments[0]) {
+		workspace, segments = segments[0], segments[1:]
+	}
+	st, err := s.workspaceStore(workspace)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	switch {
+	case len(segments) == 0:
+		s.serveRoot(w, r)
+	case len(segments) == 1 && segments[0] == "status":
+		s.serveStatus(w, r)
+	case len(segments) == 1 || len(segments) == 2:
+		if _, ok := collections[segments[0]]; !ok {
+			writeError(w, notFound())
+			return
+		}
+		h := &handler{store: st, collection: segments[0]}
+		if len(segments) == 2 {
+			h.idOrKey = segments[1]
+		}
+		h.serve(w, r)
+	case len(segments) == 3 || len(segments) == 4:
+		h, err := nestedHandler(st, segments)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		h.serve(w, r)
+	default:
+		writeError(w, notFound())
+	}
+}
+
+func (s *Server) serveRoot(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, &apiError{status: http.StatusMethodNotAllowed, message: "Method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"version":     s.version,
+		"tagline":     "Welcome to kong",
+		"node_id":     "00000000-0000-0000-0000-000000000000",
+		"hostname":    "kongtest",
+		"lua_version": "LuaJIT 2.1.0",
+		"configuration": map[string]interface{}{
+			"database": "postgres",
+		},
+		"plugins": map[string]interface{}{
+			"available_on_server": map[string]interface{}{},
+		},
+	})
+}
+
+func (s *Server) serveStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, &apiError{status: http.StatusMethodNotAllowed, message: "Method not allowed"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"database": map[string]interface{}{"reachable": true},
+		"server":   map[string]interface{}{},
+	})
+}
+
+// handler serves the requests against a collection or one of its
+// entities, possibly nested under a parent entity.
+type handler struct {
+	store      *store
+	collection string
+	idOrKey    string
+
+	// foreignField and foreignID restrict the handler to the
+	// entities referencing a parent entity.
+	foreignField string
+	foreignID    string
+}
+
+func nestedHandler(st *store, segments []string) (*handler, error) {
+	parent, parentIDOrKey, child := segments[0], segments[1], segments[2]
+	if _, ok := collections[parent]; !ok {
+		return nil, notFound()
+	}
+	if c, ok := nestedCollections[child]; ok {
+		child = c
+	}
+	var field string
+	for f, foreign := range collections[child].foreignKeys {
+		if foreign == parent {
+			field = f
+		}
+	}
+	if field == "" {
+		return nil, notFound()
+	}
+	_, p := st.find(parent, parentIDOrKey)
+	if p == nil {
+		return nil, notFound()
+	}
+	h := &handler{
+		store:        st,
+		collection:   child,
+		foreignField: field,
+		foreignID:    p["id"].(string),
+	}
+	if len(segments) == 4 {
+		h.idOrKey = segments[3]
+	}
+	return h, nil
+}
+
+func (h *handler) serve(w http.ResponseWriter, r *http.Request) {
+	if h.idOrKey == "" {
+		switch r.Method {
+		case http.MethodGet:
+			h.list(w, r)
+		case http.MethodPost:
+			h.create(w, r)
+		default:
+			writeError(w, &apiError{status: http.StatusMethodNotAllowed, message: "Method not allowed"})
+		}
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		o, err := h.get()
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, o)
+	case http.MethodPatch, http.MethodPut:
+		h.write(w, r)
+	case http.MethodDelete:
+		if _, err := h.get(); err != nil {
+			// deleting a missing entity is not an error in Kong
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if err := h.store.delete(h.collection, h.idOrKey); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, &apiError{status: http.StatusMethodNotAllowed, message: "Method not allowed"})
+	}
+}
+
+// get returns the entity targeted by the request, checking that it
+// belongs to the parent entity for nested endpoints.
+func (h *handler) get() (object, error) {
+	_, o := h.store.find(h.collection, h.idOrKey)
+	if o == nil || !h.belongsToParent(o) {
+		return nil, notFound()
+	}
+	return o, nil
+}
+
+func (h *handler) belongsToParent(o object) bool {
+	if h.foreignField == "" {
+		return true
+	}
+	ref, ok := o[h.foreignField].(map[string]interface{})
+	return ok && ref["id"] == h.foreignID
+}
+
+func (h *handler) decode(r *http.Request) (object, error) {
+	var o object
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		return nil, &apiError{
+			status:  http.StatusBadRequest,
+			message: fmt.Sprintf("Cannot parse JSON body: %v", err),
+		}
+	}
+	if o == nil {
+		o = object{}
+	}
+	if h.foreignField != "" {
+		o[h.foreignField] = map[string]interface{}{"id": h.foreignID}
+	}
+	return o, nil
+}
+
+func (h *handler) create(w http.ResponseWriter, r *http.Request) {
+	o, err := h.decode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	created, err := h.store.insert(h.collection, o)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (h *handler) write(w http.ResponseWriter, r *http.Request) {
+	o, err := h.decode(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if _, current := h.store.find(h.collection, h.idOrKey); current != nil && !h.belongsToParent(current) {
+		writeError(w, notFound())
+		return
+	}
+	var res object
+	if r.Method == http.MethodPut {
+		res, err = h.store.upsert(h.collection, h.idOrKey, o)
+	} else {
+		res, err = h.store.update(h.collection, h.idOrKey, o)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *handler) list(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	size := defaultPageSize
+	if v := q.Get("size"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > maxPageSize {
+			writeError(w, &apiError{
+				status:  http.StatusBadRequest,
+				message: fmt.Sprintf("size must be an integer between 1 and %d", maxPageSize),
+			})
+			return
+		}
+		size = n
+	}
+	offset := 0
+	if v := q.Get("offset"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			writeError(w, &apiError{status: http.StatusBadRequest, message: "invalid offset"})
+			return
+		}
+		offset = n
+	}
+	var (
+		tags     []string
+		matchAll = true
+	)
+	if v := q.Get("tags"); v != "" {
+		if strings.Contains(v, "/") {
+			tags, matchAll = strings.Split(v, "/"), false
+		} else {
+			tags = strings.Split(v, ",")
+		}
+	}
+
+	entities := h.store.list(h.collection, h.foreignField, h.foreignID, tags, matchAll)
+	if offset > len(entities) {
+		offset = len(entities)
+	}
+	end := offset + size
+	if end > len(entities) {
+		end = len(entities)
+	}
+	res := map[string]interface{}{
+		"data": append([]object{}, entities[offset:end]...),
+		"next": nil,
+	}
+	if end < len(entities) {
+		next := strconv.Itoa(end)
+		nextQuery := r.URL.Query()
+		nextQuery.Set("offset", next)
+		res["offset"] = next
+		res["next"] = r.URL.Path + "?" + nextQuery.Encode()
+	}
+	writeJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,170 @@
+package kongtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T, opts ...Option) *kong.Client {
+	t.Helper()
+	server := NewServer(opts...)
+	t.Cleanup(server.Close)
+	client, err := server.KongClient()
+	require.NoError(t, err)
+	return client
+}
+
+func apiErrorCode(t *testing.T, err error) int {
+	t.Helper()
+	var apiErr *kong.APIError
+	require.True(t, errors.As(err, &apiErr))
+	return apiErr.Code()
+}
+
+func TestServerRoot(t *testing.T) {
+	client := newClient(t, WithVersion("3.3.1"))
+	root, err := client.Root(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3.3.1", root["version"])
+}
+
+func TestServerCRUD(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	svc, err := client.Services.Create(ctx, &kong.Service{
+		Name: kong.String("svc1"),
+		Host: kong.String("example.com"),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, svc.ID)
+
+	_, err = client.Services.Create(ctx, &kong.Service{Name: kong.String("svc1")})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusConflict, apiErrorCode(t, err))
+
+	fetched, err := client.Services.Get(ctx, kong.String("svc1"))
+	require.NoError(t, err)
+	assert.Equal(t, *svc.ID, *fetched.ID)
+
+	updated, err := client.Services.Update(ctx, &kong.Service{
+		ID:   svc.ID,
+		Port: kong.Int(8080),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 8080, *updated.Port)
+	assert.Equal(t, "example.com", *updated.Host)
+
+	route, err := client.Routes.CreateInService(ctx, kong.String("svc1"), &kong.Route{
+		Name:  kong.String("r1"),
+		Paths: kong.StringSlice("/r1"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, *svc.ID, *route.Service.ID)
+
+	_, err = client.Routes.Create(ctx, &kong.Route{
+		Name:    kong.String("r2"),
+		Service: &kong.Service{ID: kong.String("does-not-exist")},
+	})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, apiErrorCode(t, err))
+
+	routes, _, err := client.Routes.ListForService(ctx, svc.ID, nil)
+	require.NoError(t, err)
+	require.Len(t, routes, 1)
+
+	// routes prevent their service from being deleted
+	err = client.Services.Delete(ctx, svc.ID)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusBadRequest, apiErrorCode(t, err))
+
+	require.NoError(t, client.Routes.Delete(ctx, kong.String("r1")))
+	require.NoError(t, client.Services.Delete(ctx, svc.ID))
+	_, err = client.Services.Get(ctx, svc.ID)
+	assert.True(t, kong.IsNotFoundErr(err))
+}
+
+func TestServerConsumerCredentials(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	_, err := client.Consumers.Create(ctx, &kong.Consumer{Username: kong.String("alice")})
+	require.NoError(t, err)
+	keyAuth, err := client.KeyAuths.Create(ctx, kong.String("alice"), &kong.KeyAuth{
+		Key: kong.String("secret"),
+	})
+	require.NoError(t, err)
+
+	keyAuths, err := client.KeyAuths.ListAll(ctx)
+	require.NoError(t, err)
+	require.Len(t, keyAuths, 1)
+
+	_, err = client.KeyAuths.Get(ctx, kong.String("alice"), keyAuth.ID)
+	require.NoError(t, err)
+
+	// deleting the consumer deletes its credentials
+	require.NoError(t, client.Consumers.Delete(ctx, kong.String("alice")))
+	keyAuths, err = client.KeyAuths.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, keyAuths)
+}
+
+func TestServerPaginationAndTags(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	for i := 0; i < 25; i++ {
+		tags := []*string{kong.String("all")}
+		if i%5 == 0 {
+			tags = append(tags, kong.String("fifth"))
+		}
+		_, err := client.Consumers.Create(ctx, &kong.Consumer{
+			Username: kong.String("consumer-" + string(rune('a'+i))),
+			Tags:     tags,
+		})
+		require.NoError(t, err)
+	}
+
+	page, next, err := client.Consumers.List(ctx, &kong.ListOpt{Size: 10})
+	require.NoError(t, err)
+	assert.Len(t, page, 10)
+	require.NotNil(t, next)
+
+	all, err := client.Consumers.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Len(t, all, 25)
+
+	tagged, _, err := client.Consumers.List(ctx, &kong.ListOpt{
+		Tags:         kong.StringSlice("all", "fifth"),
+		MatchAllTags: true,
+	})
+	require.NoError(t, err)
+	assert.Len(t, tagged, 5)
+}
+
+func TestServerWorkspaces(t *testing.T) {
+	client := newClient(t)
+	ctx := context.Background()
+
+	_, err := client.Workspaces.Create(ctx, &kong.Workspace{Name: kong.String("ws1")})
+	require.NoError(t, err)
+
+	client.SetWorkspace("ws1")
+	_, err = client.Services.Create(ctx, &kong.Service{Name: kong.String("svc1")})
+	require.NoError(t, err)
+
+	client.SetWorkspace("")
+	services, err := client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, services)
+
+	client.SetWorkspace("missing")
+	_, err = client.Services.ListAll(ctx)
+	assert.True(t, kong.IsNotFoundErr(err))
+}
@@ -0,0 +1,381 @@
+package kongtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// object is an entity as stored by the Server.
+type object map[string]interface{}
+
+// collectionSpec describes how the entities of a collection
+// are identified and related to other entities.
+type collectionSpec struct {
+	// endpointKey is the field which can be used instead
+	// of the ID in URLs, if any.
+	endpointKey string
+	// unique lists fields, other than the ID, whose value
+	// must be unique within the collection.
+	unique []string
+	// foreignKeys maps fields referencing other entities to
+	// the collection of the referenced entity.
+	foreignKeys map[string]string
+}
+
+var collections = map[string]collectionSpec{
+	"services": {endpointKey: "name", unique: []string{"name"}},
+	"routes": {
+		endpointKey: "name", unique: []string{"name"},
+		foreignKeys: map[string]string{"service": "services"},
+	},
+	"consumers":       {endpointKey: "username", unique: []string{"username", "custom_id"}},
+	"consumer_groups": {endpointKey: "name", unique: []string{"name"}},
+	"plugins": {
+		foreignKeys: map[string]string{
+			"service": "services", "route": "routes",
+			"consumer": "consumers", "consumer_group": "consumer_groups",
+		},
+	},
+	"upstreams": {endpointKey: "name", unique: []string{"name"}},
+	"targets": {
+		endpointKey: "target",
+		foreignKeys: map[string]string{"upstream": "upstreams"},
+	},
+	"certificates":    {},
+	"ca_certificates": {},
+	"snis": {
+		endpointKey: "name", unique: []string{"name"},
+		foreignKeys: map[string]string{"certificate": "certificates"},
+	},
+	"vaults":     {endpointKey: "prefix", unique: []string{"prefix"}},
+	"workspaces": {endpointKey: "name", unique: []string{"name"}},
+	"key-auths": {
+		endpointKey: "key", unique: []string{"key"},
+		foreignKeys: map[string]string{"consumer": "consumers"},
+	},
+	"basic-auths": {
+		endpointKey: "username", unique: []string{"username"},
+		foreignKeys: map[string]string{"consumer": "consumers"},
+	},
+	"hmac-auths": {
+		endpointKey: "username", unique: []string{"username"},
+		foreignKeys: map[string]string{"consumer": "consumers"},
+	},
+	"jwts": {
+		endpointKey: "key", unique: []string{"key"},
+		foreignKeys: map[string]string{"consumer": "consumers"},
+	},
+	"acls": {foreignKeys: map[string]string{"consumer": "consumers"}},
+	"oauth2": {
+		endpointKey: "client_id", unique: []string{"client_id"},
+		foreignKeys: map[string]string{"consumer": "consumers"},
+	},
+	"mtls-auths": {foreignKeys: map[string]string{"consumer": "consumers"}},
+}
+
+// nestedCollections maps the names used for collections nested under
+// another entity, like /consumers/{consumer}/key-auth, to the
+// name of the collection.
+var nestedCollections = map[string]string{
+	"key-auth":   "key-auths",
+	"basic-auth": "basic-auths",
+	"hmac-auth":  "hmac-auths",
+	"jwt":        "jwts",
+	"mtls-auth":  "mtls-auths",
+}
+
+// restrictedForeignKeys lists the relations which prevent the
+// referenced entity from being deleted, as opposed to cascading.
+var restrictedForeignKeys = map[string]string{
+	"routes": "service",
+}
+
+// apiError is an error returned by the Admin API.
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+func notFound() error {
+	return &apiError{status: http.StatusNotFound, message: "Not found"}
+}
+
+// store holds the entities of a workspace.
+type store struct {
+	entities map[string][]object
+}
+
+func newStore() *store {
+	return &store{entities: map[string][]object{}}
+}
+
+func stringField(o object, field string) (string, bool) {
+	v, ok := o[field].(string)
+	return v, ok && v != ""
+}
+
+func (s *store) find(collection, idOrKey string) (int, object) {
+	spec := collections[collection]
+	for i, o := range s.entities[collection] {
+		if id, _ := stringField(o, "id"); id == idOrKey {
+			return i, o
+		}
+	}
+	if spec.endpointKey == "" {
+		return -1, nil
+	}
+	for i, o := range s.entities[collection] {
+		if key, _ := stringField(o, spec.endpointKey); key == idOrKey {
+			return i, o
+		}
+	}
+	return -1, nil
+}
+
+// resolveForeignKeys replaces references to other entities by
+// references holding only their ID.
+func (s *store) resolveForeignKeys(collection string, o object) error {
+	for field, foreign := range collections[collection].foreignKeys {
+		ref, ok := o[field]
+		if !ok || ref == nil {
+			continue
+		}
+		refObject, ok := ref.(map[string]interface{})
+		if !ok {
+			return &apiError{
+				status:  http.StatusBadRequest,
+				message: fmt.Sprintf("schema violation (%s: expected a record)", field),
+			}
+		}
+		idOrKey, _ := stringField(refObject, "id")
+		if idOrKey == "" {
+			idOrKey, _ = stringField(refObject, collections[foreign].endpointKey)
+		}
+		_, target := s.find(foreign, idOrKey)
+		if target == nil {
+			return &apiError{
+				status: http.StatusBadRequest,
+				message: fmt.Sprintf("the foreign key '{id=\"%s\"}' does not reference "+
+					"an existing '%s' entity.", idOrKey, foreign),
+			}
+		}
+		o[field] = map[string]interface{}{"id": target["id"]}
+	}
+	return nil
+}
+
+// checkUnique returns a 409 error if o conflicts with another
+// entity than the one at index skip.
+func (s *store) checkUnique(collection string, o object, skip int) error {
+	spec := collections[collection]
+	for i, other := range s.entities[collection] {
+		if i == skip {
+			continue
+		}
+		if id, ok := stringField(o, "id"); ok && other["id"] == id {
+			return uniqueViolation("primary key", "id", id)
+		}
+		for _, field := range spec.unique {
+			if v, ok := stringField(o, field); ok && other[field] == v {
+				return uniqueViolation("UNIQUE", field, v)
+			}
+		}
+		if collection == "plugins" && samePluginScope(o, other) {
+			return uniqueViolation("UNIQUE", "name", o["name"])
+		}
+	}
+	return nil
+}
+
+func samePluginScope(a, b object) bool {
+	if a["name"] != b["name"] {
+		return false
+	}
+	for _, field := range []string{"service", "route", "consumer", "consumer_group"} {
+		if fmt.Sprint(a[field]) != fmt.Sprint(b[field]) {
+			return false
+		}
+	}
+	return true
+}
+
+func uniqueViolation(constraint, field string, value interface{}) error {
+	return &apiError{
+		status: http.StatusConflict,
+		message: fmt.Sprintf("%s violation detected on '{%s=\"%v\"}'",
+			constraint, field, value),
+	}
+}
+
+func (s *store) insert(collection string, o object) (object, error) {
+	if err := s.resolveForeignKeys(collection, o); err != nil {
+		return nil, err
+	}
+	if _, ok := stringField(o, "id"); !ok {
+		o["id"] = uuid.NewString()
+	}
+	if err := s.checkUnique(collection, o, -1); err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	o["created_at"] = now
+	o["updated_at"] = now
+	s.entities[collection] = append(s.entities[collection], o)
+	return o, nil
+}
+
+func (s *store) update(collection, idOrKey string, patch object) (object, error) {
+	i, current := s.find(collection, idOrKey)
+	if current == nil {
+		return nil, notFound()
+	}
+	if err := s.resolveForeignKeys(collection, patch); err != nil {
+		return nil, err
+	}
+	updated := object{}
+	for k, v := range current {
+		updated[k] = v
+	}
+	for k, v := range patch {
+		updated[k] = v
+	}
+	updated["id"] = current["id"]
+	if err := s.checkUnique(collection, updated, i); err != nil {
+		return nil, err
+	}
+	updated["updated_at"] = time.Now().Unix()
+	s.entities[collection][i] = updated
+	return updated, nil
+}
+
+func (s *store) upsert(collection, idOrKey string, o object) (object, error) {
+	i, current := s.find(collection, idOrKey)
+	if current == nil {
+		if _, err := uuid.Parse(idOrKey); err == nil {
+			o["id"] = idOrKey
+		} else if key := collections[collection].endpointKey; key != "" {
+			o[key] = idOrKey
+		} else {
+			return nil, &apiError{
+				status:  http.StatusBadRequest,
+				message: fmt.Sprintf("invalid primary key: '{id=\"%s\"}'", idOrKey),
+			}
+		}
+		return s.insert(collection, o)
+	}
+	if err := s.resolveForeignKeys(collection, o); err != nil {
+		return nil, err
+	}
+	o["id"] = current["id"]
+	o["created_at"] = current["created_at"]
+	o["updated_at"] = time.Now().Unix()
+	if err := s.checkUnique(collection, o, i); err != nil {
+		return nil, err
+	}
+	s.entities[collection][i] = o
+	return o, nil
+}
+
+func (s *store) delete(collection, idOrKey string) error {
+	_, o := s.find(collection, idOrKey)
+	if o == nil {
+		return nil
+	}
+	id := o["id"].(string)
+	for other, field := range restrictedForeignKeys {
+		if collections[other].foreignKeys[field] != collection {
+			continue
+		}
+		if len(s.referencing(other, field, id)) > 0 {
+			return &apiError{
+				status: http.StatusBadRequest,
+				message: fmt.Sprintf("an existing '%s' entity references this '%s' entity",
+					other, collection),
+			}
+		}
+	}
+	s.remove(collection, id)
+	return nil
+}
+
+// remove deletes the entity with id and, recursively,
+// all entities referencing it.
+func (s *store) remove(collection, id string) {
+	entities := s.entities[collection]
+	for i, o := range entities {
+		if o["id"] == id {
+			s.entities[collection] = append(entities[:i:i], entities[i+1:]...)
+			break
+		}
+	}
+	for other, spec := range collections {
+		for field, foreign := range spec.foreignKeys {
+			if foreign != collection {
+				continue
+			}
+			for _, ref := range s.referencing(other, field, id) {
+				s.remove(other, ref["id"].(string))
+			}
+		}
+	}
+}
+
+// referencing returns the entities of collection whose field
+// references the entity with id.
+func (s *store) referencing(collection, field, id string) []object {
+	var res []object
+	for _, o := range s.entities[collection] {
+		if ref, ok := o[field].(map[string]interface{}); ok && ref["id"] == id {
+			res = append(res, o)
+		}
+	}
+	return res
+}
+
+// list returns the entities of collection, optionally filtered
+// by a foreign key and tags, in the order of their creation.
+func (s *store) list(collection, field, foreignID string, tags []string, matchAll bool) []object {
+	var res []object
+	for _, o := range s.entities[collection] {
+		if field != "" {
+			ref, ok := o[field].(map[string]interface{})
+			if !ok || ref["id"] != foreignID {
+				continue
+			}
+		}
+		if len(tags) > 0 && !hasTags(o, tags, matchAll) {
+			continue
+		}
+		res = append(res, o)
+	}
+	return res
+}
+
+func hasTags(o object, tags []string, matchAll bool) bool {
+	entityTags := map[string]bool{}
+	if list, ok := o["tags"].([]interface{}); ok {
+		for _, t := range list {
+			if s, ok := t.(string); ok {
+				entityTags[s] = true
+			}
+		}
+	}
+	for _, t := range tags {
+		found := entityTags[strings.TrimSpace(t)]
+		if matchAll && !found {
+			return false
+		}
+		if !matchAll && found {
+			return true
+		}
+	}
+	return matchAll
+}
@@ -9,6 +9,8 @@ import (
 	"net/http/httputil"
 	"net/url"
 	"os"
+
+	"github.com/kong/deck/utils/httpbody"
 )
 
 var defaultCtx = context.Background()
@@ -80,7 +82,8 @@ func (c *Client) SetRuntimeGroupID(rgID string) {
 	c.common.runtimeGroupID = rgID
 }
 
-// Do executes a HTTP request and returns a response
+// Do executes a HTTP request and returns a response.
+// The body of req is sent again from the start, so req can be reused.
 func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
 	var err error
 	if req == nil {
@@ -89,7 +92,10 @@ func (c *Client) Do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	if ctx == nil {
 		ctx = defaultCtx
 	}
-	req = req.WithContext(ctx)
+	req, err = httpbody.Rewind(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
 
 	// log the request
 	err = c.logRequest(req)
@@ -12,20 +12,23 @@ import (
 
 type PageOpt struct {
 	// request
-	Size   int  `url:"size,omitempty"`
-	Number *int `url:"number,omitempty"`
+	Size   int     `json:"size,omitempty" url:"size,omitempty"`
+	Number *int    `json:"number,omitempty" url:"number,omitempty"`
+	After  *string `json:"-" url:"after,omitempty"`
 
 	// response
-	NextPageNum *int `url:"next_page_num,omitempty"`
-	TotalCount  *int `url:"total_count,omitempty"`
+	NextPageNum *int    `json:"next_page_num,omitempty" url:"-"`
+	NextCursor  *string `json:"next_cursor,omitempty" url:"-"`
+	TotalCount  *int    `json:"total_count,omitempty" url:"-"`
 }
 
 type KonnectListOpt struct { //nolint:revive
-	// Size of the page
-	Page *PageOpt
+	// Size of the page, encoded as page[size] and page[number], or
+	// page[after] for cursor-based pagination.
+	Page *PageOpt `json:"page,omitempty" url:"page,omitempty"`
 
 	// Tags to use for filtering the list.
-	Tags []*string `url:"tags,omitempty"`
+	Tags []*string `json:"-" url:"tags,omitempty"`
 }
 
 type RLAOverride struct {
@@ -396,7 +399,10 @@ func UpdateConsumerGroupMember(
 func list(ctx context.Context,
 	client *kong.Client, endpoint string, opt *KonnectListOpt,
 ) ([]json.RawMessage, *KonnectListOpt, error) {
-	req, err := client.NewRequest("GET", endpoint, nil, nil)
+	if opt == nil {
+		opt = &KonnectListOpt{}
+	}
+	req, err := client.NewRequest("GET", endpoint, opt, nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -410,13 +416,21 @@ func list(ctx context.Context,
 		return nil, nil, err
 	}
 
+	var size int
+	if opt.Page != nil {
+		size = opt.Page.Size
+	}
 	var next *KonnectListOpt
-	if list.Page != nil && list.Page.NextPageNum != nil {
+	switch {
+	case len(list.Items) == 0 || list.Page == nil:
+	case list.Page.NextCursor != nil && *list.Page.NextCursor != "":
+		next = &KonnectListOpt{
+			Page: &PageOpt{Size: size, After: list.Page.NextCursor},
+			Tags: opt.Tags,
+		}
+	case list.Page.NextPageNum != nil && *list.Page.NextPageNum != 0:
 		next = &KonnectListOpt{
-			Page: &PageOpt{
-				Size:   opt.Page.Size,
-				Number: list.Page.NextPageNum,
-			},
+			Page: &PageOpt{Size: size, Number: list.Page.NextPageNum},
 			Tags: opt.Tags,
 		}
 	}
@@ -148,3 +148,55 @@ func (d *DocumentService) ListAllForParent(ctx context.Context, parent ParentInf
 	}
 	return docs, nil
 }
+
+// Upload creates or replaces the Document at path for parent, such as an
+// API spec or a markdown page of a service version, and sets whether it
+// is published in the portal.
+func (d *DocumentService) Upload(ctx context.Context, parent ParentInfoer,
+	path string, content string, published bool,
+) (*Document, error) {
+	if parent == nil {
+		return nil, fmt.Errorf("parent cannot be nil")
+	}
+	if path == "" {
+		return nil, fmt.Errorf("path cannot be empty")
+	}
+	docs, err := d.ListAllForParent(ctx, parent)
+	if err != nil {
+		return nil, err
+	}
+	doc := &Document{
+		Path:      &path,
+		Content:   &content,
+		Published: &published,
+		Parent:    parent,
+	}
+	for _, existing := range docs {
+		if existing.Path != nil && *existing.Path == path {
+			doc.ID = existing.ID
+			return d.Update(ctx, doc)
+		}
+	}
+	return d.Create(ctx, doc)
+}
+
+// Publish publishes doc in the portal.
+func (d *DocumentService) Publish(ctx context.Context, doc *Document) (*Document, error) {
+	return d.setPublished(ctx, doc, true)
+}
+
+// Unpublish removes doc from the portal, without deleting it.
+func (d *DocumentService) Unpublish(ctx context.Context, doc *Document) (*Document, error) {
+	return d.setPublished(ctx, doc, false)
+}
+
+func (d *DocumentService) setPublished(ctx context.Context,
+	doc *Document, published bool,
+) (*Document, error) {
+	if doc == nil {
+		return nil, fmt.Errorf("document cannot be nil")
+	}
+	doc = doc.ShallowCopy()
+	doc.Published = &published
+	return d.Update(ctx, doc)
+}
@@ -0,0 +1,65 @@
+package konnect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentServiceUpload(t *testing.T) {
+	var requests []string
+	var bodies []Document
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{
+				"data": []Document{{ID: stringP("doc-1"), Path: stringP("/spec.yaml")}},
+			})
+			return
+		}
+		var doc Document
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&doc))
+		bodies = append(bodies, doc)
+		if doc.ID == nil {
+			doc.ID = stringP("doc-2")
+		}
+		_ = json.NewEncoder(w).Encode(doc)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	ctx := context.Background()
+	version := &ServiceVersion{ID: stringP("sv-1")}
+
+	spec, err := client.Documents.Upload(ctx, version, "/spec.yaml", "openapi: 3.0.0", true)
+	require.NoError(t, err)
+	assert.Equal(t, "doc-1", *spec.ID)
+	assert.Equal(t, version, spec.Parent)
+
+	doc, err := client.Documents.Upload(ctx, version, "/guide.md", "# Hello", false)
+	require.NoError(t, err)
+	assert.Equal(t, "doc-2", *doc.ID)
+
+	_, err = client.Documents.Publish(ctx, doc)
+	require.NoError(t, err)
+	assert.False(t, *doc.Published)
+
+	assert.Equal(t, []string{
+		"GET /api/service_versions/sv-1/documents",
+		"PUT /api/service_versions/sv-1/documents/doc-1",
+		"GET /api/service_versions/sv-1/documents",
+		"POST /api/service_versions/sv-1/documents/",
+		"PUT /api/service_versions/sv-1/documents/doc-2",
+	}, requests)
+	require.Len(t, bodies, 3)
+	assert.True(t, *bodies[0].Published)
+	assert.False(t, *bodies[1].Published)
+	assert.True(t, *bodies[2].Published)
+	assert.Equal(t, "# Hello", *bodies[2].Content)
+}
@@ -3,36 +3,106 @@ package konnect
 import (
 	"context"
 	"encoding/json"
+	"net/url"
+	"strings"
 )
 
 // ListOpt aids in paginating through list endpoints.
+// The same options work with all the pagination styles of Konnect's APIs:
+// the style is selected from the endpoint, and then from the responses.
 type ListOpt struct {
 	// Size of the page
 	Size int `url:"size,omitempty"`
 	// Page number to fetch
 	Page int `url:"page,omitempty"`
+	// Cursor of the page to fetch, for cursor-based endpoints.
+	// It is set by list from the previous response.
+	Cursor string `url:"-"`
+
+	style paginationStyle
 }
 
+// paginationStyle is the way an endpoint of Konnect paginates.
+type paginationStyle int
+
+const (
+	// paginationLegacy uses the `size` and `page` query parameters,
+	// as the /api endpoints of Konnect do.
+	paginationLegacy paginationStyle = iota
+	// paginationPageNumber uses the `page[size]` and `page[number]`
+	// query parameters, as the versioned endpoints of Konnect do.
+	paginationPageNumber
+	// paginationCursor uses the `page[size]` and `page[after]` query
+	// parameters, with the cursor returned in the metadata of responses.
+	paginationCursor
+)
+
 const (
 	// max page size in Konnect's API is 100
 	pageSize = 100
 )
 
+// paginationStyleFor returns the pagination style of endpoint, before
+// any response is received.
+func paginationStyleFor(endpoint string) paginationStyle {
+	if strings.HasPrefix(endpoint, "/v1/") || strings.HasPrefix(endpoint, "/v2/") ||
+		strings.HasPrefix(endpoint, "/v3/") {
+		return paginationPageNumber
+	}
+	return paginationLegacy
+}
+
+// query returns the query string parameters of opt, for the pagination
+// style in use.
+func (opt *ListOpt) query() interface{} {
+	switch opt.style {
+	case paginationPageNumber:
+		return struct {
+			Size   int `url:"page[size],omitempty"`
+			Number int `url:"page[number],omitempty"`
+		}{opt.Size, opt.Page}
+	case paginationCursor:
+		return struct {
+			Size  int    `url:"page[size],omitempty"`
+			After string `url:"page[after],omitempty"`
+		}{opt.Size, opt.Cursor}
+	default:
+		return opt
+	}
+}
+
+// cursorFromNext returns the cursor of the next page, from the `next`
+// field of the metadata of a response. It is either the cursor itself,
+// or a link to the next page holding the cursor.
+func cursorFromNext(next string) string {
+	if !strings.Contains(next, "?") {
+		return next
+	}
+	u, err := url.Parse(next)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get("page[after]")
+}
+
 // list fetches a list of an entity in Kong.
 // opt can be used to control pagination.
 func (c *Client) list(ctx context.Context,
 	endpoint string, opt *ListOpt,
 ) ([]json.RawMessage, *ListOpt, error) {
 	pageSize := 100
-	if opt != nil {
-		if opt.Size > 100 {
-			opt.Size = pageSize
-		} else {
-			pageSize = opt.Size
-		}
+	if opt == nil {
+		opt = &ListOpt{style: paginationStyleFor(endpoint)}
+	} else if opt.Size > 100 {
+		opt.Size = pageSize
+	} else {
+		pageSize = opt.Size
+	}
+	if opt.style == paginationLegacy && opt.Page == 0 && opt.Cursor == "" {
+		opt.style = paginationStyleFor(endpoint)
 	}
 
-	req, err := c.NewRequest("GET", endpoint, opt, nil)
+	req, err := c.NewRequest("GET", endpoint, opt.query(), nil)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -40,6 +110,15 @@ func (c *Client) list(ctx context.Context,
 		Data      []json.RawMessage `json:"data"`
 		Page      int               `json:"page"`
 		PageCount int               `json:"pageCount"`
+		// newer endpoints report paging in the metadata of the response
+		Meta *struct {
+			Page struct {
+				Number int    `json:"number"`
+				Size   int    `json:"size"`
+				Total  int    `json:"total"`
+				Next   string `json:"next"`
+			} `json:"page"`
+		} `json:"meta"`
 	}
 
 	_, err = c.Do(ctx, req, &list)
@@ -49,10 +128,24 @@ func (c *Client) list(ctx context.Context,
 
 	// convenient for end user to use this opt till it's nil
 	var next *ListOpt
-	if len(list.Data) > 0 && list.Page != list.PageCount {
-		next = &ListOpt{
-			Page: list.Page + 1,
-			Size: pageSize,
+	switch {
+	case list.Meta != nil && list.Meta.Page.Next != "":
+		if cursor := cursorFromNext(list.Meta.Page.Next); cursor != "" {
+			next = &ListOpt{Size: pageSize, Cursor: cursor, style: paginationCursor}
+		}
+	case opt.style == paginationCursor:
+		// the last page of cursor-based endpoints has no next cursor
+	default:
+		if list.Meta != nil && list.Meta.Page.Size > 0 {
+			list.Page = list.Meta.Page.Number
+			list.PageCount = (list.Meta.Page.Total + list.Meta.Page.Size - 1) / list.Meta.Page.Size
+		}
+		if len(list.Data) > 0 && list.Page != list.PageCount {
+			next = &ListOpt{
+				Page:  list.Page + 1,
+				Size:  pageSize,
+				style: opt.style,
+			}
 		}
 	}
 
@@ -0,0 +1,114 @@
+package konnect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func listAll(t *testing.T, client *Client, endpoint string) []json.RawMessage {
+	t.Helper()
+	var res []json.RawMessage
+	opt := &ListOpt{Size: pageSize}
+	for opt != nil {
+		var data []json.RawMessage
+		var err error
+		data, opt, err = client.list(context.Background(), endpoint, opt)
+		require.NoError(t, err)
+		res = append(res, data...)
+	}
+	return res
+}
+
+func TestListPageNumberPagination(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		number, _ := strconv.Atoi(r.URL.Query().Get("page[number]"))
+		if number == 0 {
+			number = 1
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []string{"item-" + strconv.Itoa(number)},
+			"meta": map[string]interface{}{
+				"page": map[string]interface{}{"number": number, "size": 100, "total": 150},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	assert.Len(t, listAll(t, client, "/v2/control-planes"), 2)
+	assert.Equal(t, []string{
+		"page%5Bsize%5D=100",
+		"page%5Bnumber%5D=2&page%5Bsize%5D=100",
+	}, queries)
+}
+
+func TestListCursorPagination(t *testing.T) {
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursor := r.URL.Query().Get("page[after]")
+		cursors = append(cursors, cursor)
+		next := map[string]string{
+			"":   "/v2/control-planes?page%5Bafter%5D=c1&page%5Bsize%5D=100",
+			"c1": "c2",
+			"c2": "",
+		}[cursor]
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []string{"item"},
+			"meta": map[string]interface{}{
+				"page": map[string]interface{}{"size": 100, "next": next},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	assert.Len(t, listAll(t, client, "/v2/control-planes"), 3)
+	assert.Equal(t, []string{"", "c1", "c2"}, cursors)
+}
+
+func TestListConsumerGroupsPagination(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page := map[string]interface{}{"total_count": 3}
+		switch {
+		case r.URL.Query().Get("page[after]") == "c3":
+		case r.URL.Query().Get("page[number]") == "2":
+			page["next_cursor"] = "c3"
+		default:
+			page["next_page_num"] = 2
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"items": []map[string]string{{"name": "cg"}},
+			"page":  page,
+		})
+	}))
+	defer server.Close()
+
+	client, err := kong.NewClient(kong.String(server.URL), server.Client())
+	require.NoError(t, err)
+
+	groups, err := ListAllConsumerGroups(context.Background(), client,
+		[]*string{kong.String("team-a")})
+	require.NoError(t, err)
+	assert.Len(t, groups, 3)
+	assert.Equal(t, []string{
+		"page%5Bsize%5D=100&tags=team-a",
+		"page%5Bnumber%5D=2&page%5Bsize%5D=100&tags=team-a",
+		"page%5Bafter%5D=c3&page%5Bsize%5D=100&tags=team-a",
+	}, queries)
+}
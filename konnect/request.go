@@ -12,7 +12,8 @@ import (
 // NewRequest creates a request based on the inputs.
 // endpoint should be relative to the baseURL specified during
 // client creation.
-// body is always marshaled into JSON.
+// body is always marshaled into JSON, and can be sent again by
+// redirects, retries and Do.
 func (c *Client) NewRequest(method, endpoint string, qs interface{},
 	body interface{},
 ) (*http.Request, error) {
@@ -29,9 +30,9 @@ func (c *Client) NewRequest(method, endpoint string, qs interface{},
 		}
 	}
 
-	// Create a new request
+	// Create a new request; GetBody is set for bytes.Reader bodies
 	req, err := http.NewRequest(method, c.baseURL+endpoint,
-		bytes.NewBuffer(buf))
+		bytes.NewReader(buf))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,42 @@
+package konnect
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDoResendsBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, r.URL.Path+" "+string(b))
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new", http.StatusPermanentRedirect)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	req, err := client.NewRequest(http.MethodPost, "/old", nil, map[string]string{"name": "foo"})
+	require.NoError(t, err)
+	_, err = client.Do(ctx, req, nil)
+	require.NoError(t, err)
+	// the request can be sent again, e.g. after re-authenticating
+	_, err = client.Do(ctx, req, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		`/old {"name":"foo"}`, `/new {"name":"foo"}`,
+		`/old {"name":"foo"}`, `/new {"name":"foo"}`,
+	}, bodies)
+}
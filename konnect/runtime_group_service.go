@@ -3,11 +3,13 @@ package konnect
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strings"
 )
 
 type RuntimeGroupService service
 
-// List fetches a list of Service packages.
+// List fetches a list of Runtime groups.
 func (s *RuntimeGroupService) List(ctx context.Context,
 	opt *ListOpt,
 ) ([]*RuntimeGroup, *ListOpt, error) {
@@ -32,3 +34,43 @@ func (s *RuntimeGroupService) List(ctx context.Context,
 
 	return runtimeGroups, next, nil
 }
+
+// ListAll fetches all Runtime groups.
+func (s *RuntimeGroupService) ListAll(ctx context.Context) ([]*RuntimeGroup, error) {
+	var runtimeGroups, data []*RuntimeGroup
+	var err error
+	opt := &ListOpt{Size: pageSize}
+
+	for opt != nil {
+		data, opt, err = s.List(ctx, opt)
+		if err != nil {
+			return nil, err
+		}
+		runtimeGroups = append(runtimeGroups, data...)
+	}
+	return runtimeGroups, nil
+}
+
+// GetByName fetches the Runtime group identified by name.
+func (s *RuntimeGroupService) GetByName(ctx context.Context,
+	name string,
+) (*RuntimeGroup, error) {
+	if name == "" {
+		return nil, fmt.Errorf("name cannot be empty for GetByName operation")
+	}
+	runtimeGroups, err := s.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(runtimeGroups))
+	for _, rg := range runtimeGroups {
+		if rg.Name != nil && *rg.Name == name {
+			return rg, nil
+		}
+		if rg.Name != nil {
+			names = append(names, *rg.Name)
+		}
+	}
+	return nil, fmt.Errorf("runtime group not found: %s (available: %s)",
+		name, strings.Join(names, ", "))
+}
@@ -0,0 +1,76 @@
+package konnect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuntimeGroupServiceGetByName(t *testing.T) {
+	pages := [][]RuntimeGroup{
+		{{ID: stringP("rg-1"), Name: stringP("default")}},
+		{{ID: stringP("rg-2"), Name: stringP("team-a")}},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data":      pages[page-1],
+			"page":      page,
+			"pageCount": len(pages),
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	rg, err := client.RuntimeGroups.GetByName(context.Background(), "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "rg-2", *rg.ID)
+
+	_, err = client.RuntimeGroups.GetByName(context.Background(), "team-b")
+	assert.EqualError(t, err, "runtime group not found: team-b (available: default, team-a)")
+
+	_, err = client.RuntimeGroups.GetByName(context.Background(), "")
+	assert.Error(t, err)
+}
+
+func TestRuntimeGroupServiceListAllMetaPaging(t *testing.T) {
+	var pagesRequested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pagesRequested = append(pagesRequested, page)
+		number, _ := strconv.Atoi(page)
+		if number == 0 {
+			number = 1
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"data": []RuntimeGroup{{ID: stringP("rg-" + strconv.Itoa(number))}},
+			"meta": map[string]interface{}{
+				"page": map[string]interface{}{"number": number, "size": 100, "total": 201},
+			},
+		})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(nil, ClientOpts{BaseURL: server.URL})
+	require.NoError(t, err)
+
+	runtimeGroups, err := client.RuntimeGroups.ListAll(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, runtimeGroups, 3)
+	assert.Equal(t, []string{"", "2", "3"}, pagesRequested)
+}
+
+func stringP(s string) *string {
+	return &s
+}
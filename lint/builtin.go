@@ -0,0 +1,113 @@
+package lint
+
+import (
+	"github.com/kong/deck/patch"
+)
+
+// rateLimitingPlugins are the plugins limiting the rate of requests.
+var rateLimitingPlugins = map[string]bool{
+	"rate-limiting":                  true,
+	"rate-limiting-advanced":         true,
+	"graphql-rate-limiting-advanced": true,
+}
+
+// BuiltinRules returns the built-in rules.
+func BuiltinRules() []Rule {
+	return []Rule{
+		{
+			Name:        "entity-tags",
+			Description: "entities should be tagged, so that their owner can be identified",
+			Severity:    SeverityWarn,
+			Given: []string{
+				"$.services[*]",
+				"$..routes[*]",
+				"$.consumers[*]",
+				"$.upstreams[*]",
+				"$.certificates[*]",
+				"$.plugins[*]",
+			},
+			Then: Then{Field: "tags", Function: FunctionTruthy},
+		},
+		{
+			Name: "service-rate-limiting",
+			Description: "services should be rate limited, with a rate limiting plugin on the service, " +
+				"on all of its routes or a global one",
+			Severity: SeverityWarn,
+			Given:    []string{"$.services[*]"},
+			check:    serviceRateLimited,
+		},
+		{
+			Name:        "deprecated-format-version",
+			Description: "_format_version should be 3.0, format versions 1.x and 2.x are deprecated",
+			Severity:    SeverityWarn,
+			Given:       []string{"$"},
+			Then: Then{
+				Field:    "_format_version",
+				Function: FunctionPattern,
+				Options:  FunctionOptions{Match: `^3\.`},
+			},
+		},
+		{
+			Name:        "deprecated-konnect-info",
+			Description: "_konnect is deprecated, use the --konnect-control-plane-name flag instead",
+			Severity:    SeverityWarn,
+			Given:       []string{"$"},
+			Then:        Then{Field: "_konnect", Function: FunctionUndefined},
+		},
+		{
+			Name:        "deprecated-plugin-run-on",
+			Description: "the run_on field of plugins was removed in Kong 3.0",
+			Severity:    SeverityError,
+			Given:       []string{"$..plugins[*]"},
+			Then:        Then{Field: "run_on", Function: FunctionUndefined},
+		},
+	}
+}
+
+func serviceRateLimited(doc interface{}, node patch.Match) bool {
+	if hasRateLimiting(patch.MustParseSelector("$.plugins[*]").Select(doc), nil) {
+		return true
+	}
+	service, _ := node.Value.(map[string]interface{})
+	if hasRateLimiting(patch.MustParseSelector("$.plugins[*]").Select(service), nil) {
+		return true
+	}
+	// global plugins scoped to the service
+	if name, ok := service["name"].(string); ok &&
+		hasRateLimiting(patch.MustParseSelector("$.plugins[*]").Select(doc), &name) {
+		return true
+	}
+	routes := patch.MustParseSelector("$.routes[*]").Select(service)
+	if len(routes) == 0 {
+		return false
+	}
+	for _, route := range routes {
+		if !hasRateLimiting(patch.MustParseSelector("$.plugins[*]").Select(route), nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasRateLimiting returns whether plugins hold an enabled rate limiting
+// plugin, applied to the service if set, or to all services otherwise.
+func hasRateLimiting(plugins []interface{}, service *string) bool {
+	for _, p := range plugins {
+		plugin, _ := p.(map[string]interface{})
+		name, _ := plugin["name"].(string)
+		if !rateLimitingPlugins[name] || plugin["enabled"] == false {
+			continue
+		}
+		scope, scoped := plugin["service"]
+		if service == nil {
+			if !scoped && plugin["route"] == nil && plugin["consumer"] == nil {
+				return true
+			}
+			continue
+		}
+		if scope == *service {
+			return true
+		}
+	}
+	return false
+}
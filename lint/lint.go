@@ -0,0 +1,334 @@
+// Package lint checks decK state files against rules.
+//
+// A rule selects nodes of a state file with selectors, as used by
+// patches, then checks a field of the selected nodes with a function.
+// Built-in rules cover common mistakes; users can add their own rules,
+// override the severity of built-in rules or turn them off with rulesets.
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kong/deck/patch"
+	"sigs.k8s.io/yaml"
+)
+
+// Severity is the severity of a rule.
+type Severity int
+
+// Severities of rules, from the least to the most severe.
+const (
+	SeverityOff Severity = iota
+	SeverityInfo
+	SeverityWarn
+	SeverityError
+)
+
+var severityNames = map[Severity]string{
+	SeverityOff:   "off",
+	SeverityInfo:  "info",
+	SeverityWarn:  "warn",
+	SeverityError: "error",
+}
+
+// ParseSeverity parses the name of a severity.
+func ParseSeverity(name string) (Severity, error) {
+	for s, n := range severityNames {
+		if n == strings.ToLower(name) {
+			return s, nil
+		}
+	}
+	return SeverityOff, fmt.Errorf("invalid severity '%s', must be one of: off, info, warn, error", name)
+}
+
+func (s Severity) String() string {
+	return severityNames[s]
+}
+
+// MarshalJSON implements json.Marshaler.
+func (s Severity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Severity) UnmarshalJSON(b []byte) error {
+	var name string
+	if err := json.Unmarshal(b, &name); err != nil {
+		return err
+	}
+	severity, err := ParseSeverity(name)
+	if err != nil {
+		return err
+	}
+	*s = severity
+	return nil
+}
+
+// Functions checking the field of a node.
+const (
+	// FunctionTruthy checks that the field is set and not empty, false or 0.
+	FunctionTruthy = "truthy"
+	// FunctionFalsy checks that the field is not set, or empty, false or 0.
+	FunctionFalsy = "falsy"
+	// FunctionDefined checks that the field is set.
+	FunctionDefined = "defined"
+	// FunctionUndefined checks that the field is not set.
+	FunctionUndefined = "undefined"
+	// FunctionPattern checks that the field, if set, matches the regex
+	// of the `match` option.
+	FunctionPattern = "pattern"
+	// FunctionNotPattern checks that the field, if set, does not match
+	// the regex of the `match` option.
+	FunctionNotPattern = "not-pattern"
+	// FunctionEnumeration checks that the field, if set, is one of the
+	// `values` option.
+	FunctionEnumeration = "enumeration"
+)
+
+// FunctionOptions are the options of the functions checking fields.
+type FunctionOptions struct {
+	// Match is the regex of the pattern and not-pattern functions.
+	Match string `json:"match,omitempty"`
+	// Values are the values of the enumeration function.
+	Values []interface{} `json:"values,omitempty"`
+}
+
+// Then is the check of a rule.
+type Then struct {
+	// Field is the path of the checked field in the selected nodes, with
+	// nested fields separated by dots. The nodes are checked if empty.
+	Field    string          `json:"field,omitempty"`
+	Function string          `json:"function"`
+	Options  FunctionOptions `json:"function_options,omitempty"`
+
+	match *regexp.Regexp
+}
+
+// Rule is a lint rule.
+type Rule struct {
+	Name        string   `json:"-"`
+	Description string   `json:"description,omitempty"`
+	Severity    Severity `json:"severity"`
+	// Given holds the selectors of the nodes checked by the rule.
+	Given []string `json:"given"`
+	Then  Then     `json:"then"`
+	// Message is the message of the findings, Description if empty.
+	Message string `json:"message,omitempty"`
+
+	// check replaces Then for built-in rules which cannot be expressed
+	// with a function. It returns whether the node passes the rule.
+	check func(doc interface{}, node patch.Match) bool
+}
+
+func (r *Rule) validate() error {
+	if len(r.Given) == 0 {
+		return fmt.Errorf("given is required")
+	}
+	for _, given := range r.Given {
+		if _, err := patch.ParseSelector(given); err != nil {
+			return err
+		}
+	}
+	if r.check != nil {
+		return nil
+	}
+	switch r.Then.Function {
+	case FunctionTruthy, FunctionFalsy, FunctionDefined, FunctionUndefined:
+	case FunctionPattern, FunctionNotPattern:
+		match, err := regexp.Compile(r.Then.Options.Match)
+		if err != nil {
+			return fmt.Errorf("invalid match option: %w", err)
+		}
+		r.Then.match = match
+	case FunctionEnumeration:
+		if len(r.Then.Options.Values) == 0 {
+			return fmt.Errorf("the values option is required by the %s function", FunctionEnumeration)
+		}
+	case "":
+		return fmt.Errorf("then.function is required")
+	default:
+		return fmt.Errorf("unknown function '%s'", r.Then.Function)
+	}
+	return nil
+}
+
+// Finding is a failure of a node to pass a rule.
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+	// Path is the path of the node in the state file.
+	Path string `json:"path"`
+}
+
+func (f Finding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.Rule, f.Path, f.Message)
+}
+
+// Lint checks the content of a state file, in YAML or JSON, against rules.
+// Findings are ordered by path, then rule.
+func Lint(content []byte, rules []Rule) ([]Finding, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+	var res []Finding
+	for i := range rules {
+		rule := &rules[i]
+		if rule.Severity == SeverityOff {
+			continue
+		}
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		message := rule.Message
+		if message == "" {
+			message = rule.Description
+		}
+		for _, given := range rule.Given {
+			for _, node := range patch.MustParseSelector(given).Match(doc) {
+				if rule.passes(doc, node) {
+					continue
+				}
+				res = append(res, Finding{
+					Rule:     rule.Name,
+					Severity: rule.Severity,
+					Message:  message,
+					Path:     node.Path,
+				})
+			}
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Path != res[j].Path {
+			return res[i].Path < res[j].Path
+		}
+		return res[i].Rule < res[j].Rule
+	})
+	return res, nil
+}
+
+func (r *Rule) passes(doc interface{}, node patch.Match) bool {
+	if r.check != nil {
+		return r.check(doc, node)
+	}
+	value, ok := node.Value, true
+	if r.Then.Field != "" {
+		value, ok = patch.Lookup(node.Value, strings.Split(r.Then.Field, "."))
+	}
+	switch r.Then.Function {
+	case FunctionTruthy:
+		return ok && truthy(value)
+	case FunctionFalsy:
+		return !ok || !truthy(value)
+	case FunctionDefined:
+		return ok
+	case FunctionUndefined:
+		return !ok
+	case FunctionPattern, FunctionNotPattern:
+		if !ok {
+			return true
+		}
+		matches := r.Then.match.MatchString(fmt.Sprint(value))
+		return matches == (r.Then.Function == FunctionPattern)
+	case FunctionEnumeration:
+		if !ok {
+			return true
+		}
+		for _, v := range r.Then.Options.Values {
+			if fmt.Sprint(v) == fmt.Sprint(value) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func truthy(v interface{}) bool {
+	switch value := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return value
+	case string:
+		return value != ""
+	case float64:
+		return value != 0
+	case []interface{}:
+		return len(value) > 0
+	case map[string]interface{}:
+		return len(value) > 0
+	}
+	return true
+}
+
+// Ruleset is a file holding lint rules, indexed by name.
+// Rules named after built-in rules replace them. A rule which only
+// sets a severity changes the severity of the built-in rule.
+type Ruleset struct {
+	Rules map[string]Rule `json:"rules"`
+}
+
+// ParseRuleset parses a YAML or JSON ruleset.
+func ParseRuleset(content []byte) (*Ruleset, error) {
+	var ruleset Ruleset
+	if err := yaml.UnmarshalStrict(content, &ruleset); err != nil {
+		return nil, fmt.Errorf("parsing ruleset: %w", err)
+	}
+	// rules without a severity are warnings, not turned off
+	var severities struct {
+		Rules map[string]struct {
+			Severity *string `json:"severity"`
+		} `json:"rules"`
+	}
+	if err := yaml.Unmarshal(content, &severities); err != nil {
+		return nil, fmt.Errorf("parsing ruleset: %w", err)
+	}
+	for name, rule := range ruleset.Rules {
+		rule.Name = name
+		if severities.Rules[name].Severity == nil {
+			rule.Severity = SeverityWarn
+		}
+		ruleset.Rules[name] = rule
+	}
+	return &ruleset, nil
+}
+
+// Rules returns base rules, overridden by the rules of rulesets.
+// Rules are ordered by name.
+func Rules(base []Rule, rulesets ...*Ruleset) ([]Rule, error) {
+	byName := map[string]Rule{}
+	for _, rule := range base {
+		byName[rule.Name] = rule
+	}
+	for _, ruleset := range rulesets {
+		for name, rule := range ruleset.Rules {
+			if existing, ok := byName[name]; ok && len(rule.Given) == 0 {
+				existing.Severity = rule.Severity
+				byName[name] = existing
+				continue
+			}
+			if rule.Severity != SeverityOff {
+				if err := rule.validate(); err != nil {
+					return nil, fmt.Errorf("rule %s: %w", name, err)
+				}
+			}
+			byName[name] = rule
+		}
+	}
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := make([]Rule, 0, len(names))
+	for _, name := range names {
+		res = append(res, byName[name])
+	}
+	return res, nil
+}
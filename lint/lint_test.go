@@ -0,0 +1,195 @@
+package lint
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testState = `
+_format_version: "1.1"
+services:
+- name: svc1
+  tags: [team-a]
+  plugins:
+  - name: rate-limiting
+    run_on: first
+    tags: [team-a]
+- name: svc2
+  tags: [team-a]
+  routes:
+  - name: r1
+    tags: [team-a]
+    plugins:
+    - name: rate-limiting
+      tags: [team-a]
+  - name: r2
+- name: svc3
+consumers:
+- username: alice
+  tags: [team-a]
+`
+
+func TestLintBuiltinRules(t *testing.T) {
+	findings, err := Lint([]byte(testState), BuiltinRules())
+	require.NoError(t, err)
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, f.String())
+	}
+	assert.Equal(t, []string{
+		"warn [deprecated-format-version] $: " +
+			"_format_version should be 3.0, format versions 1.x and 2.x are deprecated",
+		"error [deprecated-plugin-run-on] $.services[0].plugins[0]: " +
+			"the run_on field of plugins was removed in Kong 3.0",
+		"warn [service-rate-limiting] $.services[1]: " +
+			"services should be rate limited, with a rate limiting plugin on the service, " +
+			"on all of its routes or a global one",
+		"warn [entity-tags] $.services[1].routes[1]: " +
+			"entities should be tagged, so that their owner can be identified",
+		"warn [entity-tags] $.services[2]: " +
+			"entities should be tagged, so that their owner can be identified",
+		"warn [service-rate-limiting] $.services[2]: " +
+			"services should be rate limited, with a rate limiting plugin on the service, " +
+			"on all of its routes or a global one",
+	}, got)
+}
+
+func TestLintGlobalRateLimiting(t *testing.T) {
+	rules, err := Rules(BuiltinRules(), &Ruleset{Rules: map[string]Rule{
+		"entity-tags": {Severity: SeverityOff},
+	}})
+	require.NoError(t, err)
+
+	findings, err := Lint([]byte(`
+_format_version: "3.0"
+services:
+- name: svc1
+- name: svc2
+plugins:
+- name: rate-limiting-advanced
+  service: svc2
+`), rules)
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, "$.services[0]", findings[0].Path)
+
+	findings, err = Lint([]byte(`
+_format_version: "3.0"
+services:
+- name: svc1
+plugins:
+- name: rate-limiting
+`), rules)
+	require.NoError(t, err)
+	assert.Empty(t, findings)
+}
+
+func TestLintCustomRules(t *testing.T) {
+	ruleset, err := ParseRuleset([]byte(`
+rules:
+  entity-tags:
+    severity: error
+  deprecated-format-version:
+    severity: "off"
+  service-rate-limiting:
+    severity: "off"
+  https-only:
+    description: services must use https
+    given: ["$.services[*]"]
+    then:
+      field: protocol
+      function: enumeration
+      function_options:
+        values: [https]
+  timeouts:
+    description: read timeouts must be set
+    severity: info
+    given: ["$.services[*]"]
+    then:
+      field: read_timeout
+      function: defined
+`))
+	require.NoError(t, err)
+	rules, err := Rules(BuiltinRules(), ruleset)
+	require.NoError(t, err)
+
+	findings, err := Lint([]byte(`
+services:
+- name: svc1
+  protocol: http
+  read_timeout: 1000
+  tags: [a]
+- name: svc2
+  protocol: https
+`), rules)
+	require.NoError(t, err)
+	assert.Equal(t, []Finding{
+		{Rule: "https-only", Severity: SeverityWarn, Path: "$.services[0]", Message: "services must use https"},
+		{
+			Rule: "entity-tags", Severity: SeverityError, Path: "$.services[1]",
+			Message: "entities should be tagged, so that their owner can be identified",
+		},
+		{Rule: "timeouts", Severity: SeverityInfo, Path: "$.services[1]", Message: "read timeouts must be set"},
+	}, findings)
+}
+
+func TestRulesErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		ruleset string
+		wantErr string
+	}{
+		{
+			name: "unknown function",
+			ruleset: `
+rules:
+  r1:
+    given: ["$.services[*]"]
+    then: {function: bogus}`,
+			wantErr: "rule r1: unknown function 'bogus'",
+		},
+		{
+			name: "invalid selector",
+			ruleset: `
+rules:
+  r1:
+    given: ["services"]
+    then: {function: truthy}`,
+			wantErr: "rule r1: invalid selector 'services': must start with '$'",
+		},
+		{
+			name: "invalid pattern",
+			ruleset: `
+rules:
+  r1:
+    given: ["$.services[*]"]
+    then: {field: name, function: pattern, function_options: {match: "("}}`,
+			wantErr: "rule r1: invalid match option: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name: "custom rule without given",
+			ruleset: `
+rules:
+  r1:
+    severity: error`,
+			wantErr: "rule r1: given is required",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ruleset, err := ParseRuleset([]byte(tt.ruleset))
+			require.NoError(t, err)
+			_, err = Rules(BuiltinRules(), ruleset)
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+
+	_, err := ParseRuleset([]byte(`
+rules:
+  r1:
+    severity: critical`))
+	assert.ErrorContains(t, err, "invalid severity 'critical'")
+}
@@ -0,0 +1,469 @@
+// Package openapi2kong converts OpenAPI 3 specifications into decK
+// state files.
+//
+// A specification is converted into a service, with a route per
+// operation. The conversion can be customized with x-kong-* extensions:
+//
+//	x-kong-name               name of the service (document level), or of the
+//	                          routes (path and operation levels)
+//	x-kong-service-defaults   fields of the service (document level)
+//	x-kong-upstream-defaults  fields of the upstream created when the
+//	                          document lists multiple servers (document level)
+//	x-kong-route-defaults     fields of the routes (document, path and
+//	                          operation levels, the most specific wins)
+//	x-kong-plugin-<name>      a plugin, on the service at the document level,
+//	                          on the routes at the path and operation levels
+package openapi2kong
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kong/deck/file"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	extensionPrefix        = "x-kong-"
+	extensionName          = "x-kong-name"
+	extensionServiceFields = "x-kong-service-defaults"
+	extensionUpstreamField = "x-kong-upstream-defaults"
+	extensionRouteFields   = "x-kong-route-defaults"
+	extensionPluginPrefix  = "x-kong-plugin-"
+
+	formatVersion = "3.0"
+
+	// regex priorities of the routes, so that routes without path
+	// parameters win over routes with parameters matching the same path.
+	regexPriorityStatic = 200
+	regexPriorityParams = 100
+)
+
+// operations are the HTTP methods of OpenAPI path items, in the order
+// routes are generated.
+var operations = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Options customizes a conversion.
+type Options struct {
+	// Tags are added to all generated entities.
+	Tags []string
+	// SelectTags are set as the select tags of the generated file,
+	// and added to all generated entities.
+	SelectTags []string
+}
+
+// object is a JSON object of the specification.
+type object = map[string]interface{}
+
+// Convert converts an OpenAPI 3 specification, in YAML or JSON, into
+// the content of a state file.
+func Convert(spec []byte, opts Options) (*file.Content, error) {
+	var doc object
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("parsing OpenAPI specification: %w", err)
+	}
+	version, _ := doc["openapi"].(string)
+	if !strings.HasPrefix(version, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version '%v', only 3.x is supported",
+			doc["openapi"])
+	}
+
+	c := &converter{
+		tags: append(append([]string{}, opts.SelectTags...), opts.Tags...),
+	}
+	res, err := c.convert(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// state files are built as plain JSON objects, then decoded,
+	// so that the x-kong-*-defaults can set any field of the entities
+	b, err := json.Marshal(res)
+	if err != nil {
+		return nil, err
+	}
+	var content file.Content
+	if err := json.Unmarshal(b, &content); err != nil {
+		return nil, fmt.Errorf("building state file: %w", err)
+	}
+	if len(opts.SelectTags) > 0 {
+		content.Info = &file.Info{SelectorTags: opts.SelectTags}
+	}
+	return &content, nil
+}
+
+type converter struct {
+	tags []string
+}
+
+func (c *converter) convert(doc object) (object, error) {
+	serviceName, err := c.serviceName(doc)
+	if err != nil {
+		return nil, err
+	}
+	servers, err := parseServers(doc["servers"])
+	if err != nil {
+		return nil, err
+	}
+
+	service := object{
+		"name":     serviceName,
+		"protocol": servers[0].Scheme,
+		"host":     servers[0].Hostname(),
+		"port":     port(servers[0]),
+		"path":     servicePath(servers[0]),
+	}
+	res := object{"_format_version": formatVersion}
+	if len(servers) > 1 {
+		upstream, err := c.upstream(doc, serviceName+".upstream", servers)
+		if err != nil {
+			return nil, err
+		}
+		service["host"] = upstream["name"]
+		res["upstreams"] = []interface{}{upstream}
+	}
+	defaults, err := extensionObject(doc, extensionServiceFields)
+	if err != nil {
+		return nil, err
+	}
+	merge(service, defaults)
+	c.tag(service)
+
+	plugins, err := c.plugins(doc, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(plugins) > 0 {
+		service["plugins"] = plugins
+	}
+
+	routes, err := c.routes(doc, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if len(routes) > 0 {
+		service["routes"] = routes
+	}
+	res["services"] = []interface{}{service}
+	return res, nil
+}
+
+func (c *converter) serviceName(doc object) (string, error) {
+	if name, ok := doc[extensionName]; ok {
+		s, ok := name.(string)
+		if !ok || s == "" {
+			return "", fmt.Errorf("%s must be a non-empty string", extensionName)
+		}
+		return slugify(s), nil
+	}
+	info, _ := doc["info"].(object)
+	title, _ := info["title"].(string)
+	if slugify(title) == "" {
+		return "", fmt.Errorf("info.title or %s is required to name the service", extensionName)
+	}
+	return slugify(title), nil
+}
+
+func (c *converter) upstream(doc object, name string, servers []*url.URL) (object, error) {
+	var targets []interface{}
+	for _, server := range servers {
+		target := object{"target": net.JoinHostPort(server.Hostname(), strconv.Itoa(port(server)))}
+		c.tag(target)
+		targets = append(targets, target)
+	}
+	upstream := object{"name": name, "targets": targets}
+	defaults, err := extensionObject(doc, extensionUpstreamField)
+	if err != nil {
+		return nil, err
+	}
+	merge(upstream, defaults)
+	c.tag(upstream)
+	return upstream, nil
+}
+
+func (c *converter) routes(doc object, serviceName string) ([]interface{}, error) {
+	docDefaults, err := extensionObject(doc, extensionRouteFields)
+	if err != nil {
+		return nil, err
+	}
+	paths, _ := doc["paths"].(object)
+	sortedPaths := make([]string, 0, len(paths))
+	for path := range paths {
+		sortedPaths = append(sortedPaths, path)
+	}
+	sort.Strings(sortedPaths)
+
+	var res []interface{}
+	names := map[string]string{}
+	for _, path := range sortedPaths {
+		pathItem, ok := paths[path].(object)
+		if !ok {
+			return nil, fmt.Errorf("path '%s' must be an object", path)
+		}
+		pathDefaults, err := extensionObject(pathItem, extensionRouteFields)
+		if err != nil {
+			return nil, fmt.Errorf("path '%s': %w", path, err)
+		}
+		pathPlugins, err := c.plugins(pathItem, nil)
+		if err != nil {
+			return nil, fmt.Errorf("path '%s': %w", path, err)
+		}
+		pathName, _ := pathItem[extensionName].(string)
+
+		for _, method := range operations {
+			operation, ok := pathItem[method].(object)
+			if !ok {
+				continue
+			}
+			name := routeName(serviceName, path, pathName, method, operation)
+			if previous, ok := names[name]; ok {
+				return nil, fmt.Errorf("operations %s and %s %s both generate the route '%s', "+
+					"set %s or operationId to tell them apart",
+					previous, strings.ToUpper(method), path, name, extensionName)
+			}
+			names[name] = strings.ToUpper(method) + " " + path
+
+			regex, hasParams := pathRegex(path)
+			priority := regexPriorityStatic
+			if hasParams {
+				priority = regexPriorityParams
+			}
+			route := object{
+				"name":           name,
+				"paths":          []interface{}{regex},
+				"methods":        []interface{}{strings.ToUpper(method)},
+				"strip_path":     false,
+				"regex_priority": priority,
+			}
+			operationDefaults, err := extensionObject(operation, extensionRouteFields)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			merge(route, docDefaults)
+			merge(route, pathDefaults)
+			merge(route, operationDefaults)
+			c.tag(route)
+
+			plugins, err := c.plugins(operation, pathPlugins)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(method), path, err)
+			}
+			if len(plugins) > 0 {
+				route["plugins"] = plugins
+			}
+			res = append(res, route)
+		}
+	}
+	return res, nil
+}
+
+// plugins returns the plugins defined by the x-kong-plugin-* extensions
+// of o, on top of inherited plugins. Plugins of o replace inherited
+// plugins with the same name.
+func (c *converter) plugins(o object, inherited []interface{}) ([]interface{}, error) {
+	byName := map[string]interface{}{}
+	for _, p := range inherited {
+		byName[p.(object)["name"].(string)] = p
+	}
+	for key, value := range o {
+		if !strings.HasPrefix(key, extensionPluginPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(key, extensionPluginPrefix)
+		config, ok := value.(object)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an object", key)
+		}
+		plugin := object{}
+		merge(plugin, config)
+		if pluginName, ok := plugin["name"]; ok && pluginName != name {
+			return nil, fmt.Errorf("%s has a mismatching name '%v'", key, pluginName)
+		}
+		plugin["name"] = name
+		c.tag(plugin)
+		byName[name] = plugin
+	}
+
+	names := make([]string, 0, len(byName))
+	for name := range byName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	res := make([]interface{}, 0, len(names))
+	for _, name := range names {
+		res = append(res, byName[name])
+	}
+	return res, nil
+}
+
+// tag adds the tags of the conversion to an entity, after its own tags.
+func (c *converter) tag(entity object) {
+	if len(c.tags) == 0 {
+		return
+	}
+	tags, _ := entity["tags"].([]interface{})
+	seen := map[interface{}]bool{}
+	for _, tag := range tags {
+		seen[tag] = true
+	}
+	for _, tag := range c.tags {
+		if !seen[tag] {
+			tags = append(tags, tag)
+			seen[tag] = true
+		}
+	}
+	entity["tags"] = tags
+}
+
+// extensionObject returns the value of extension key in o, which must be
+// an object if set.
+func extensionObject(o object, key string) (object, error) {
+	value, ok := o[key]
+	if !ok {
+		return nil, nil
+	}
+	res, ok := value.(object)
+	if !ok {
+		return nil, fmt.Errorf("%s must be an object", key)
+	}
+	return res, nil
+}
+
+// merge copies the fields of src into dst, recursing into objects.
+func merge(dst, src object) {
+	for k, v := range src {
+		srcObject, srcIsObject := v.(object)
+		dstObject, dstIsObject := dst[k].(object)
+		switch {
+		case srcIsObject && dstIsObject:
+			merge(dstObject, srcObject)
+		case srcIsObject:
+			copied := object{}
+			merge(copied, srcObject)
+			dst[k] = copied
+		default:
+			dst[k] = v
+		}
+	}
+}
+
+var serverVariableRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// parseServers returns the URLs of the servers of the document,
+// with server variables replaced by their default values.
+// Relative URLs are resolved against http://localhost.
+func parseServers(value interface{}) ([]*url.URL, error) {
+	servers, _ := value.([]interface{})
+	if len(servers) == 0 {
+		servers = []interface{}{object{"url": "/"}}
+	}
+	base, _ := url.Parse("http://localhost")
+	var res []*url.URL
+	for i, s := range servers {
+		server, _ := s.(object)
+		rawURL, _ := server["url"].(string)
+		variables, _ := server["variables"].(object)
+		var missing []string
+		rawURL = serverVariableRegex.ReplaceAllStringFunc(rawURL, func(match string) string {
+			name := match[1 : len(match)-1]
+			variable, _ := variables[name].(object)
+			value, ok := variable["default"].(string)
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("servers[%d]: no default value for variables %v", i, missing)
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return nil, fmt.Errorf("servers[%d]: invalid url: %w", i, err)
+		}
+		u = base.ResolveReference(u)
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, fmt.Errorf("servers[%d]: unsupported scheme '%s'", i, u.Scheme)
+		}
+		if i > 0 && (u.Scheme != res[0].Scheme || servicePath(u) != servicePath(res[0])) {
+			return nil, fmt.Errorf("servers[%d]: all servers must share the same scheme and path", i)
+		}
+		res = append(res, u)
+	}
+	return res, nil
+}
+
+func port(u *url.URL) int {
+	if p, err := strconv.Atoi(u.Port()); err == nil {
+		return p
+	}
+	if u.Scheme == "https" {
+		return 443
+	}
+	return 80
+}
+
+func servicePath(u *url.URL) string {
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "" {
+		return "/"
+	}
+	return path
+}
+
+var pathParamRegex = regexp.MustCompile(`{([^}]+)}`)
+
+// pathRegex returns the regex path of a route matching an OpenAPI path,
+// capturing path parameters, and whether the path has parameters.
+func pathRegex(path string) (string, bool) {
+	var sb strings.Builder
+	sb.WriteString("~")
+	last := 0
+	matches := pathParamRegex.FindAllStringSubmatchIndex(path, -1)
+	for _, m := range matches {
+		sb.WriteString(regexp.QuoteMeta(path[last:m[0]]))
+		sb.WriteString("(?<" + captureName(path[m[2]:m[3]]) + ">[^#?/]+)")
+		last = m[1]
+	}
+	sb.WriteString(regexp.QuoteMeta(path[last:]))
+	sb.WriteString("$")
+	return sb.String(), len(matches) > 0
+}
+
+var invalidCaptureChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// captureName returns a valid regex group name for a path parameter.
+func captureName(param string) string {
+	name := invalidCaptureChars.ReplaceAllString(param, "_")
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		name = "_" + name
+	}
+	return name
+}
+
+// routeName returns the name of the route of an operation.
+func routeName(serviceName, path, pathName, method string, operation object) string {
+	if name, ok := operation[extensionName].(string); ok && name != "" {
+		return serviceName + "_" + slugify(name)
+	}
+	if id, ok := operation["operationId"].(string); ok && id != "" {
+		return serviceName + "_" + slugify(id)
+	}
+	if pathName != "" {
+		return serviceName + "_" + slugify(pathName) + "_" + method
+	}
+	return serviceName + "_" + slugify(path) + "_" + method
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9.~_-]+`)
+
+// slugify turns s into a valid Kong entity name.
+func slugify(s string) string {
+	s = invalidNameChars.ReplaceAllString(strings.ToLower(s), "-")
+	return strings.Trim(s, "-")
+}
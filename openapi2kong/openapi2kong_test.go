@@ -0,0 +1,134 @@
+package openapi2kong
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+func TestConvert(t *testing.T) {
+	spec, err := os.ReadFile("testdata/petstore.yaml")
+	require.NoError(t, err)
+	expected, err := os.ReadFile("testdata/petstore.expected.yaml")
+	require.NoError(t, err)
+
+	content, err := Convert(spec, Options{SelectTags: []string{"pets"}})
+	require.NoError(t, err)
+	output, err := yaml.Marshal(content)
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), string(output))
+}
+
+func TestConvertUpstream(t *testing.T) {
+	spec := []byte(`
+openapi: 3.1.0
+x-kong-name: Users API
+x-kong-upstream-defaults:
+  algorithm: least-connections
+servers:
+- url: http://users-1.internal:8080/api/
+- url: http://users-2.internal:8080/api
+paths:
+  /users:
+    get: {}
+`)
+	content, err := Convert(spec, Options{})
+	require.NoError(t, err)
+	require.Len(t, content.Upstreams, 1)
+	assert.Equal(t, "users-api.upstream", *content.Upstreams[0].Name)
+	assert.Equal(t, "least-connections", *content.Upstreams[0].Algorithm)
+	require.Len(t, content.Upstreams[0].Targets, 2)
+	assert.Equal(t, "users-1.internal:8080", *content.Upstreams[0].Targets[0].Target.Target)
+	assert.Equal(t, "users-2.internal:8080", *content.Upstreams[0].Targets[1].Target.Target)
+
+	require.Len(t, content.Services, 1)
+	service := content.Services[0]
+	assert.Equal(t, "users-api.upstream", *service.Host)
+	assert.Equal(t, 8080, *service.Port)
+	assert.Equal(t, "/api", *service.Path)
+	require.Len(t, service.Routes, 1)
+	assert.Equal(t, "users-api_users_get", *service.Routes[0].Name)
+}
+
+func TestConvertErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantErr string
+	}{
+		{
+			name:    "swagger 2",
+			spec:    `swagger: "2.0"`,
+			wantErr: "unsupported OpenAPI version '<nil>', only 3.x is supported",
+		},
+		{
+			name:    "no name",
+			spec:    `openapi: 3.0.0`,
+			wantErr: "info.title or x-kong-name is required to name the service",
+		},
+		{
+			name: "missing server variable",
+			spec: `
+openapi: 3.0.0
+info: {title: api}
+servers: [{url: "https://{region}.example.com"}]`,
+			wantErr: "servers[0]: no default value for variables [region]",
+		},
+		{
+			name: "servers with different paths",
+			spec: `
+openapi: 3.0.0
+info: {title: api}
+servers: [{url: "https://a.example.com/v1"}, {url: "https://b.example.com/v2"}]`,
+			wantErr: "servers[1]: all servers must share the same scheme and path",
+		},
+		{
+			name: "invalid plugin",
+			spec: `
+openapi: 3.0.0
+info: {title: api}
+x-kong-plugin-cors: true`,
+			wantErr: "x-kong-plugin-cors must be an object",
+		},
+		{
+			name: "conflicting route names",
+			spec: `
+openapi: 3.0.0
+info: {title: api}
+paths:
+  /a:
+    get: {operationId: same}
+  /b:
+    get: {operationId: same}`,
+			wantErr: "operations GET /a and GET /b both generate the route 'api_same', " +
+				"set x-kong-name or operationId to tell them apart",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Convert([]byte(tt.spec), Options{})
+			assert.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func Test_pathRegex(t *testing.T) {
+	tests := []struct {
+		path       string
+		want       string
+		wantParams bool
+	}{
+		{"/", "~/$", false},
+		{"/users.json", `~/users\.json$`, false},
+		{"/users/{id}", "~/users/(?<id>[^#?/]+)$", true},
+		{"/a/{1st}/b/{x.y}", "~/a/(?<_1st>[^#?/]+)/b/(?<x_y>[^#?/]+)$", true},
+	}
+	for _, tt := range tests {
+		got, params := pathRegex(tt.path)
+		assert.Equal(t, tt.want, got)
+		assert.Equal(t, tt.wantParams, params)
+	}
+}
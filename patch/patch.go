@@ -0,0 +1,165 @@
+// Package patch applies declarative patches to decK state files.
+//
+// A patch selects objects of a state file with selectors, and sets
+// or removes fields of the selected objects. Patches are typically
+// used to apply environment-specific changes to a shared base file.
+package patch
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Patch sets and removes fields of the objects selected by Selectors.
+type Patch struct {
+	Selectors []string `json:"selectors" yaml:"selectors"`
+	// Values holds the fields to set, replacing existing values.
+	// A null value removes the field.
+	Values map[string]interface{} `json:"values,omitempty" yaml:"values,omitempty"`
+	// Remove holds the names of the fields to remove.
+	Remove []string `json:"remove,omitempty" yaml:"remove,omitempty"`
+}
+
+// File is a file holding patches.
+type File struct {
+	FormatVersion string  `json:"_format_version,omitempty" yaml:"_format_version,omitempty"`
+	Patches       []Patch `json:"patches" yaml:"patches"`
+}
+
+// ParseFile parses a YAML or JSON file holding patches.
+func ParseFile(content []byte) (*File, error) {
+	var f File
+	if err := yaml.UnmarshalStrict(content, &f); err != nil {
+		return nil, fmt.Errorf("parsing patch file: %w", err)
+	}
+	for i, p := range f.Patches {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("patch #%d: %w", i+1, err)
+		}
+	}
+	return &f, nil
+}
+
+func (p *Patch) validate() error {
+	if len(p.Selectors) == 0 {
+		return fmt.Errorf("at least one selector is required")
+	}
+	if len(p.Values) == 0 && len(p.Remove) == 0 {
+		return fmt.Errorf("values or remove must be set")
+	}
+	for _, s := range p.Selectors {
+		if _, err := ParseSelector(s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Apply applies the patch to doc, in place, and returns the number of
+// objects patched. doc must be a document as produced by unmarshaling
+// JSON into an interface{}.
+// An error is returned if a selector matches anything but objects.
+func (p *Patch) Apply(doc interface{}) (int, error) {
+	if err := p.validate(); err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, expr := range p.Selectors {
+		selector, err := ParseSelector(expr)
+		if err != nil {
+			return count, err
+		}
+		for _, node := range selector.Select(doc) {
+			object, ok := node.(map[string]interface{})
+			if !ok {
+				return count, fmt.Errorf("selector '%s' matched %s, only objects can be patched",
+					expr, typeName(node))
+			}
+			for field, value := range p.Values {
+				if value == nil {
+					delete(object, field)
+					continue
+				}
+				// copy values so that patched objects do not share them
+				object[field] = deepCopy(value)
+			}
+			for _, field := range p.Remove {
+				delete(object, field)
+			}
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ApplyAll applies patches in order to doc.
+func ApplyAll(doc interface{}, patches []Patch) error {
+	for i := range patches {
+		if _, err := patches[i].Apply(doc); err != nil {
+			return fmt.Errorf("applying patch #%d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// ApplyToState applies patches to the content of a YAML or JSON state
+// file and returns the patched content, as YAML or JSON if asJSON is set.
+func ApplyToState(content []byte, patches []Patch, asJSON bool) ([]byte, error) {
+	var doc interface{}
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("parsing state file: %w", err)
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	if err := ApplyAll(doc, patches); err != nil {
+		return nil, err
+	}
+	if asJSON {
+		b, err := json.MarshalIndent(doc, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(b, '\n'), nil
+	}
+	return yaml.Marshal(doc)
+}
+
+func deepCopy(v interface{}) interface{} {
+	switch value := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(value))
+		for k, item := range value {
+			res[k] = deepCopy(item)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(value))
+		for i, item := range value {
+			res[i] = deepCopy(item)
+		}
+		return res
+	default:
+		return v
+	}
+}
+
+// typeName returns the JSON type of v, with an article.
+func typeName(v interface{}) string {
+	switch v.(type) {
+	case []interface{}:
+		return "an array"
+	case string:
+		return "a string"
+	case float64, int, int64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("a %T", v)
+	}
+}
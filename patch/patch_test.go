@@ -0,0 +1,88 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchApply(t *testing.T) {
+	doc := testDoc(t)
+	p := Patch{
+		Selectors: []string{"$..plugins[?(@.name == 'rate-limiting')].config"},
+		Values: map[string]interface{}{
+			"minute": float64(100),
+			"policy": map[string]interface{}{"name": "redis"},
+		},
+	}
+	count, err := p.Apply(doc)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	configs := MustParseSelector("$..plugins[?(@.name == 'rate-limiting')].config").Select(doc)
+	require.Len(t, configs, 2)
+	for _, c := range configs {
+		assert.Equal(t, map[string]interface{}{
+			"minute": float64(100),
+			"policy": map[string]interface{}{"name": "redis"},
+		}, c)
+	}
+	// values are copied for each object
+	configs[0].(map[string]interface{})["policy"].(map[string]interface{})["name"] = "local"
+	assert.Equal(t, "redis", configs[1].(map[string]interface{})["policy"].(map[string]interface{})["name"])
+
+	count, err = (&Patch{
+		Selectors: []string{"$.services[?('team-b' in @.tags)]"},
+		Values:    map[string]interface{}{"port": nil, "retries": float64(3)},
+		Remove:    []string{"plugins"},
+	}).Apply(doc)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	svc2 := MustParseSelector("$.services[1]").Select(doc)[0]
+	assert.Equal(t, map[string]interface{}{
+		"name":    "svc2",
+		"tags":    []interface{}{"team-b"},
+		"retries": float64(3),
+	}, svc2)
+
+	_, err = (&Patch{
+		Selectors: []string{"$.services"},
+		Remove:    []string{"name"},
+	}).Apply(doc)
+	assert.EqualError(t, err, "selector '$.services' matched an array, only objects can be patched")
+}
+
+func TestParseFile(t *testing.T) {
+	f, err := ParseFile([]byte(`
+_format_version: "1.0"
+patches:
+- selectors:
+  - $.services[*]
+  values:
+    read_timeout: 30000
+`))
+	require.NoError(t, err)
+	require.Len(t, f.Patches, 1)
+	assert.Equal(t, float64(30000), f.Patches[0].Values["read_timeout"])
+
+	_, err = ParseFile([]byte(`patches: [{selectors: ["$.services[*]"]}]`))
+	assert.EqualError(t, err, "patch #1: values or remove must be set")
+	_, err = ParseFile([]byte(`patches: [{selectors: ["services"], remove: [a]}]`))
+	assert.Error(t, err)
+	_, err = ParseFile([]byte(`patches: [{selector: ["$"], remove: [a]}]`))
+	assert.Error(t, err)
+}
+
+func TestApplyToState(t *testing.T) {
+	out, err := ApplyToState([]byte(testState), []Patch{{
+		Selectors: []string{"$.consumers[?(@.username == 'alice')]"},
+		Values:    map[string]interface{}{"custom_id": "user-1"},
+	}}, false)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), "custom_id: user-1")
+
+	out, err = ApplyToState([]byte(`{"services": []}`), nil, true)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"services": []}`, string(out))
+}
@@ -0,0 +1,484 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Selector selects nodes of a document, using a subset of JSONPath:
+//
+//	$                    the root of the document
+//	.name or ['name']    the field name of an object
+//	..name               the field name of an object at any depth
+//	.* or [*]            all elements of an array or values of an object
+//	[n]                  the n-th element of an array, negative n counts from the end
+//	[?(@.a.b == 'v')]    the elements whose field matches, also with !=
+//	[?('v' in @.tags)]   the elements whose array field contains a value
+//	[?(@.a)]             the elements whose field is set
+//
+// Literals in filters are strings in single or double quotes, numbers,
+// booleans or null.
+type Selector struct {
+	expr  string
+	steps []step
+}
+
+type stepKind int
+
+const (
+	stepChild stepKind = iota
+	stepWildcard
+	stepIndex
+	stepFilter
+)
+
+type step struct {
+	kind      stepKind
+	recursive bool
+	name      string
+	index     int
+	filter    *filter
+}
+
+type filterOp int
+
+const (
+	opExists filterOp = iota
+	opEqual
+	opNotEqual
+	opIn
+)
+
+type filter struct {
+	op    filterOp
+	path  []string
+	value interface{}
+}
+
+// ParseSelector parses a selector expression.
+func ParseSelector(expr string) (*Selector, error) {
+	s := strings.TrimSpace(expr)
+	if !strings.HasPrefix(s, "$") {
+		return nil, fmt.Errorf("invalid selector '%s': must start with '$'", expr)
+	}
+	p := &selectorParser{expr: expr, s: s[1:]}
+	steps, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector '%s': %w", expr, err)
+	}
+	return &Selector{expr: expr, steps: steps}, nil
+}
+
+// MustParseSelector is like ParseSelector but panics on errors.
+func MustParseSelector(expr string) *Selector {
+	s, err := ParseSelector(expr)
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// String returns the expression of the selector.
+func (s *Selector) String() string {
+	return s.expr
+}
+
+type selectorParser struct {
+	expr string
+	s    string
+}
+
+func (p *selectorParser) parse() ([]step, error) {
+	var steps []step
+	for p.s != "" {
+		recursive := false
+		switch {
+		case strings.HasPrefix(p.s, ".."):
+			recursive = true
+			p.s = p.s[2:]
+		case strings.HasPrefix(p.s, "."):
+			p.s = p.s[1:]
+		case strings.HasPrefix(p.s, "["):
+		default:
+			return nil, fmt.Errorf("unexpected '%s'", p.s)
+		}
+		st, err := p.parseStep()
+		if err != nil {
+			return nil, err
+		}
+		st.recursive = recursive
+		steps = append(steps, st)
+	}
+	return steps, nil
+}
+
+func (p *selectorParser) parseStep() (step, error) {
+	if strings.HasPrefix(p.s, "*") {
+		p.s = p.s[1:]
+		return step{kind: stepWildcard}, nil
+	}
+	if !strings.HasPrefix(p.s, "[") {
+		end := strings.IndexAny(p.s, ".[")
+		if end < 0 {
+			end = len(p.s)
+		}
+		name := p.s[:end]
+		if name == "" {
+			return step{}, fmt.Errorf("missing field name")
+		}
+		p.s = p.s[end:]
+		return step{kind: stepChild, name: name}, nil
+	}
+
+	end, err := closingBracket(p.s)
+	if err != nil {
+		return step{}, err
+	}
+	inner := strings.TrimSpace(p.s[1:end])
+	p.s = p.s[end+1:]
+	switch {
+	case inner == "*":
+		return step{kind: stepWildcard}, nil
+	case strings.HasPrefix(inner, "?(") && strings.HasSuffix(inner, ")"):
+		f, err := parseFilter(strings.TrimSpace(inner[2 : len(inner)-1]))
+		if err != nil {
+			return step{}, err
+		}
+		return step{kind: stepFilter, filter: f}, nil
+	case isQuoted(inner):
+		return step{kind: stepChild, name: inner[1 : len(inner)-1]}, nil
+	default:
+		index, err := strconv.Atoi(inner)
+		if err != nil {
+			return step{}, fmt.Errorf("invalid index '%s'", inner)
+		}
+		return step{kind: stepIndex, index: index}, nil
+	}
+}
+
+// closingBracket returns the index of the bracket closing the one
+// starting s, ignoring brackets in quoted strings.
+func closingBracket(s string) (int, error) {
+	depth := 0
+	var q quoteTracker
+	for i := 0; i < len(s); i++ {
+		if q.quoted(s[i]) {
+			continue
+		}
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("missing closing bracket")
+}
+
+// quoteTracker tracks whether the bytes of an expression scanned in
+// order are part of a quoted literal.
+type quoteTracker struct {
+	quote byte
+}
+
+// quoted reports whether c, the next byte of the expression, is part
+// of a quoted literal, quotes included.
+func (q *quoteTracker) quoted(c byte) bool {
+	switch {
+	case q.quote != 0:
+		if c == q.quote {
+			q.quote = 0
+		}
+		return true
+	case c == '\'' || c == '"':
+		q.quote = c
+		return true
+	}
+	return false
+}
+
+// indexUnquoted returns the index in s of the first of tokens found
+// outside quoted literals, and the token, or -1 if there is none.
+func indexUnquoted(s string, tokens ...string) (int, string) {
+	var q quoteTracker
+	for i := 0; i < len(s); i++ {
+		if q.quoted(s[i]) {
+			continue
+		}
+		for _, token := range tokens {
+			if strings.HasPrefix(s[i:], token) {
+				return i, token
+			}
+		}
+	}
+	return -1, ""
+}
+
+func isQuoted(s string) bool {
+	return len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0]
+}
+
+func parseFilter(expr string) (*filter, error) {
+	// filters start with the path, except for `'value' in @.path`
+	if i, _ := indexUnquoted(expr, " in "); i >= 0 && !strings.HasPrefix(expr, "@") {
+		value, err := parseLiteral(strings.TrimSpace(expr[:i]))
+		if err != nil {
+			return nil, err
+		}
+		path, err := parseFilterPath(strings.TrimSpace(expr[i+len(" in "):]))
+		if err != nil {
+			return nil, err
+		}
+		return &filter{op: opIn, path: path, value: value}, nil
+	}
+	if i, token := indexUnquoted(expr, "==", "!="); i >= 0 {
+		path, err := parseFilterPath(strings.TrimSpace(expr[:i]))
+		if err != nil {
+			return nil, err
+		}
+		value, err := parseLiteral(strings.TrimSpace(expr[i+len(token):]))
+		if err != nil {
+			return nil, err
+		}
+		op := opEqual
+		if token == "!=" {
+			op = opNotEqual
+		}
+		return &filter{op: op, path: path, value: value}, nil
+	}
+	path, err := parseFilterPath(expr)
+	if err != nil {
+		return nil, err
+	}
+	return &filter{op: opExists, path: path}, nil
+}
+
+func parseFilterPath(s string) ([]string, error) {
+	if s == "@" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(s, "@.") {
+		return nil, fmt.Errorf("invalid filter path '%s': must start with '@.'", s)
+	}
+	path := strings.Split(s[2:], ".")
+	for _, field := range path {
+		if field == "" {
+			return nil, fmt.Errorf("invalid filter path '%s'", s)
+		}
+	}
+	return path, nil
+}
+
+func parseLiteral(s string) (interface{}, error) {
+	switch {
+	case isQuoted(s):
+		return s[1 : len(s)-1], nil
+	case s == "true":
+		return true, nil
+	case s == "false":
+		return false, nil
+	case s == "null":
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid literal '%s'", s)
+	}
+	return f, nil
+}
+
+// Match is a node selected by a Selector.
+type Match struct {
+	// Path is the path of the node in the document, e.g. `$.services[0].name`.
+	Path  string
+	Value interface{}
+}
+
+// Select returns the nodes of doc matched by the selector.
+// doc must be made of maps, slices and scalars as produced by
+// unmarshaling JSON into an interface{}.
+func (s *Selector) Select(doc interface{}) []interface{} {
+	matches := s.Match(doc)
+	res := make([]interface{}, len(matches))
+	for i, m := range matches {
+		res[i] = m.Value
+	}
+	return res
+}
+
+// Match is like Select but also returns the paths of the selected nodes.
+func (s *Selector) Match(doc interface{}) []Match {
+	nodes := []Match{{Path: "$", Value: doc}}
+	for _, st := range s.steps {
+		if st.recursive {
+			var all []Match
+			for _, node := range nodes {
+				all = appendDescendants(all, node)
+			}
+			nodes = all
+		}
+		var next []Match
+		for _, node := range nodes {
+			next = append(next, st.apply(node)...)
+		}
+		nodes = dedupe(next)
+	}
+	return nodes
+}
+
+func (st step) apply(node Match) []Match {
+	switch st.kind {
+	case stepChild:
+		if m, ok := node.Value.(map[string]interface{}); ok {
+			if v, ok := m[st.name]; ok {
+				return []Match{{Path: childPath(node.Path, st.name), Value: v}}
+			}
+		}
+	case stepWildcard:
+		return children(node)
+	case stepIndex:
+		if a, ok := node.Value.([]interface{}); ok {
+			i := st.index
+			if i < 0 {
+				i += len(a)
+			}
+			if i >= 0 && i < len(a) {
+				return []Match{{Path: fmt.Sprintf("%s[%d]", node.Path, i), Value: a[i]}}
+			}
+		}
+	case stepFilter:
+		var res []Match
+		for _, child := range children(node) {
+			if st.filter.match(child.Value) {
+				res = append(res, child)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+var identifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+func childPath(path, name string) string {
+	if identifierRegex.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "['" + name + "']"
+}
+
+// children returns the elements of an array or the values of an
+// object, ordered by key.
+func children(node Match) []Match {
+	switch v := node.Value.(type) {
+	case []interface{}:
+		res := make([]Match, len(v))
+		for i, item := range v {
+			res[i] = Match{Path: fmt.Sprintf("%s[%d]", node.Path, i), Value: item}
+		}
+		return res
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		res := make([]Match, 0, len(v))
+		for _, k := range keys {
+			res = append(res, Match{Path: childPath(node.Path, k), Value: v[k]})
+		}
+		return res
+	}
+	return nil
+}
+
+func appendDescendants(res []Match, node Match) []Match {
+	res = append(res, node)
+	for _, child := range children(node) {
+		res = appendDescendants(res, child)
+	}
+	return res
+}
+
+// dedupe removes objects selected more than once,
+// e.g. through recursive descent.
+func dedupe(nodes []Match) []Match {
+	seen := map[uintptr]bool{}
+	res := nodes[:0]
+	for _, node := range nodes {
+		if m, ok := node.Value.(map[string]interface{}); ok {
+			ptr := reflect.ValueOf(m).Pointer()
+			if seen[ptr] {
+				continue
+			}
+			seen[ptr] = true
+		}
+		res = append(res, node)
+	}
+	return res
+}
+
+func (f *filter) match(node interface{}) bool {
+	value, ok := Lookup(node, f.path)
+	switch f.op {
+	case opExists:
+		return ok && value != nil
+	case opEqual:
+		return ok && equal(value, f.value)
+	case opNotEqual:
+		return !ok || !equal(value, f.value)
+	case opIn:
+		list, _ := value.([]interface{})
+		for _, item := range list {
+			if equal(item, f.value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Lookup returns the value at path in node, a document decoded from JSON
+// or YAML, following the fields of nested objects. It returns false if a
+// field is missing or is not an object.
+func Lookup(node interface{}, path []string) (interface{}, bool) {
+	for _, field := range path {
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if node, ok = m[field]; !ok {
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+func equal(a, b interface{}) bool {
+	// numbers are float64 in documents and literals,
+	// but may be ints in documents built in Go
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,142 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const testState = `
+services:
+- name: svc1
+  port: 80
+  tags: [team-a]
+  routes:
+  - name: r1
+    plugins:
+    - name: rate-limiting
+      config:
+        minute: 10
+- name: svc2
+  port: 443
+  tags: [team-b]
+  plugins:
+  - name: rate-limiting
+    config:
+      minute: 20
+  - name: cors
+consumers:
+- username: alice
+`
+
+func testDoc(t *testing.T) interface{} {
+	t.Helper()
+	var doc interface{}
+	require.NoError(t, yaml.Unmarshal([]byte(testState), &doc))
+	return doc
+}
+
+func names(nodes []interface{}) []string {
+	var res []string
+	for _, node := range nodes {
+		if m, ok := node.(map[string]interface{}); ok {
+			if name, ok := m["name"].(string); ok {
+				res = append(res, name)
+				continue
+			}
+			if name, ok := m["username"].(string); ok {
+				res = append(res, name)
+			}
+		}
+	}
+	return res
+}
+
+func TestSelectorSelect(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"$.services[*]", []string{"svc1", "svc2"}},
+		{"$.services.*", []string{"svc1", "svc2"}},
+		{"$.services[0]", []string{"svc1"}},
+		{"$.services[-1]", []string{"svc2"}},
+		{"$['consumers'][0]", []string{"alice"}},
+		{"$.services[?(@.name == 'svc2')]", []string{"svc2"}},
+		{`$.services[?(@.name != "svc2")]`, []string{"svc1"}},
+		{"$.services[?(@.port == 443)]", []string{"svc2"}},
+		{"$.services[?('team-a' in @.tags)]", []string{"svc1"}},
+		{"$.services[?(@.plugins)]", []string{"svc2"}},
+		{"$..plugins[?(@.name == 'rate-limiting')]", []string{"rate-limiting", "rate-limiting"}},
+		{"$..plugins[?(@.config.minute == 10)]", []string{"rate-limiting"}},
+		{"$.services[?(@.name == 'svc2')].plugins[*]", []string{"rate-limiting", "cors"}},
+		{"$.services[?(@.name == 'missing')]", nil},
+		{"$.services[?(@.name != 'x==y')]", []string{"svc1", "svc2"}},
+		{"$.services[?(@.name == 'svc1 != svc2')]", nil},
+		{"$.services[?('team-a in' in @.tags)]", nil},
+		{"$.upstreams[*]", nil},
+	}
+	doc := testDoc(t)
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			s, err := ParseSelector(tt.selector)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, names(s.Select(doc)))
+		})
+	}
+}
+
+func TestParseFilterQuotedOperators(t *testing.T) {
+	tests := []struct {
+		expr string
+		want *filter
+	}{
+		{"@.a != 'x==y'", &filter{op: opNotEqual, path: []string{"a"}, value: "x==y"}},
+		{`@.a == "x!=y"`, &filter{op: opEqual, path: []string{"a"}, value: "x!=y"}},
+		{"'x in y' in @.a", &filter{op: opIn, path: []string{"a"}, value: "x in y"}},
+		{"@.a == ']'", &filter{op: opEqual, path: []string{"a"}, value: "]"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			f, err := parseFilter(tt.expr)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f)
+		})
+	}
+}
+
+func TestParseSelectorErrors(t *testing.T) {
+	for _, expr := range []string{
+		"services",
+		"$.services[",
+		"$.services[abc]",
+		"$.services[?(name == 'x')]",
+		"$.services[?(@.name == x)]",
+		"$services",
+		"$.services.",
+	} {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseSelector(expr)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSelectorMatchPaths(t *testing.T) {
+	doc := testDoc(t)
+	var paths []string
+	for _, m := range MustParseSelector("$..plugins[?(@.name == 'rate-limiting')]").Match(doc) {
+		paths = append(paths, m.Path)
+	}
+	assert.Equal(t, []string{
+		"$.services[0].routes[0].plugins[0]",
+		"$.services[1].plugins[0]",
+	}, paths)
+
+	matches := MustParseSelector("$.services[-1]['port']").Match(doc)
+	require.Len(t, matches, 1)
+	assert.Equal(t, "$.services[1].port", matches[0].Path)
+	assert.Equal(t, float64(443), matches[0].Value)
+}
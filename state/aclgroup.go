@@ -30,7 +30,8 @@ var aclGroupTableSchema = &memdb.TableSchema{
 			Name:    "group",
 			Indexer: &memdb.StringFieldIndex{Field: "Group"},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 		// foreign
 		aclGroupsByConsumerID: {
 			Name: aclGroupsByConsumerID,
@@ -259,3 +260,25 @@ func (k *ACLGroupsCollection) GetAll() ([]*ACLGroup, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the acl-groups holding all of tags,
+// or all the acl-groups if tags is empty.
+func (k *ACLGroupsCollection) GetAllByTags(tags ...string) ([]*ACLGroup, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, aclGroupTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ACLGroup, 0, len(entities))
+	for _, el := range entities {
+		r, ok := el.(*ACLGroup)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &ACLGroup{ACLGroup: *r.DeepCopy()})
+	}
+	return res, nil
+}
@@ -182,6 +182,31 @@ func TestACLGroupGetByConsumer(t *testing.T) {
 	assert.Equal(3, len(aclGroups))
 }
 
+func TestACLGroupGetAllByTags(t *testing.T) {
+	assert := assert.New(t)
+	collection := aclGroupsCollection()
+
+	populateWithACLGroupFixtures(assert, collection)
+
+	aclGroup, err := collection.GetByID("second")
+	assert.Nil(err)
+	aclGroup.Tags = kong.StringSlice("team-a")
+	assert.Nil(collection.Update(*aclGroup))
+
+	aclGroups, err := collection.GetAllByTags("team-a")
+	assert.Nil(err)
+	assert.Equal(1, len(aclGroups))
+	assert.Equal("my-group12", *aclGroups[0].Group)
+
+	aclGroups, err = collection.GetAllByTags("team-b")
+	assert.Nil(err)
+	assert.Empty(aclGroups)
+
+	aclGroups, err = collection.GetAllByTags()
+	assert.Nil(err)
+	assert.Equal(5, len(aclGroups))
+}
+
 func populateWithACLGroupFixtures(assert *assert.Assertions,
 	collection *ACLGroupsCollection,
 ) {
@@ -31,7 +31,7 @@ func (k *BasicAuthsCollection) Get(keyOrID string) (*BasicAuth, error) {
 	if !ok {
 		panic(unexpectedType)
 	}
-	return &BasicAuth{BasicAuth: *basicAuth.DeepCopy()}, nil
+	return basicAuth, nil
 }
 
 // GetAllByConsumerID returns all basic-auth credentials
@@ -50,7 +50,7 @@ func (k *BasicAuthsCollection) GetAllByConsumerID(id string) ([]*BasicAuth,
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &BasicAuth{BasicAuth: *r.DeepCopy()})
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -79,7 +79,26 @@ func (k *BasicAuthsCollection) GetAll() ([]*BasicAuth, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &BasicAuth{BasicAuth: *r.DeepCopy()})
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// GetAllByTags returns the basic-auth credentials holding all of tags,
+// or all the basic-auth credentials if tags is empty.
+func (k *BasicAuthsCollection) GetAllByTags(tags ...string) ([]*BasicAuth, error) {
+	creds, err := k.credentialsCollection.GetAllByTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*BasicAuth, 0, len(creds))
+	for _, cred := range creds {
+		r, ok := cred.(*BasicAuth)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -27,7 +27,8 @@ var caCertTableSchema = &memdb.TableSchema{
 				Fields: []string{"Cert"},
 			},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -170,3 +171,25 @@ func (k *CACertificatesCollection) GetAll() ([]*CACertificate, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the CA certificates holding all of tags,
+// or all the CA certificates if tags is empty.
+func (k *CACertificatesCollection) GetAllByTags(tags ...string) ([]*CACertificate, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, caCertTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*CACertificate, 0, len(entities))
+	for _, el := range entities {
+		c, ok := el.(*CACertificate)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &CACertificate{CACertificate: *c.DeepCopy()})
+	}
+	return res, nil
+}
@@ -27,7 +27,8 @@ var certificateTableSchema = &memdb.TableSchema{
 				Fields: []string{"Cert", "Key"},
 			},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -232,3 +233,25 @@ func (k *CertificatesCollection) GetAll() ([]*Certificate, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the certificates holding all of tags,
+// or all the certificates if tags is empty.
+func (k *CertificatesCollection) GetAllByTags(tags ...string) ([]*Certificate, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, certificateTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Certificate, 0, len(entities))
+	for _, el := range entities {
+		c, ok := el.(*Certificate)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Certificate{Certificate: *c.DeepCopy()})
+	}
+	return res, nil
+}
@@ -31,7 +31,8 @@ var consumerTableSchema = &memdb.TableSchema{
 			Indexer:      &memdb.StringFieldIndex{Field: "CustomID"},
 			AllowMissing: true,
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -178,3 +179,25 @@ func (k *ConsumersCollection) GetAll() ([]*Consumer, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the consumers holding all of tags,
+// or all the consumers if tags is empty.
+func (k *ConsumersCollection) GetAllByTags(tags ...string) ([]*Consumer, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, consumerTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Consumer, 0, len(entities))
+	for _, el := range entities {
+		c, ok := el.(*Consumer)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Consumer{Consumer: *c.DeepCopy()})
+	}
+	return res, nil
+}
@@ -24,7 +24,8 @@ var consumerGroupTableSchema = &memdb.TableSchema{
 			Unique:  true,
 			Indexer: &memdb.StringFieldIndex{Field: "Name"},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -173,3 +174,25 @@ func (k *ConsumerGroupsCollection) GetAll() ([]*ConsumerGroup, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the consumer groups holding all of tags,
+// or all the consumer groups if tags is empty.
+func (k *ConsumerGroupsCollection) GetAllByTags(tags ...string) ([]*ConsumerGroup, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, consumerGroupTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*ConsumerGroup, 0, len(entities))
+	for _, el := range entities {
+		c, ok := el.(*ConsumerGroup)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &ConsumerGroup{ConsumerGroup: *c.DeepCopy()})
+	}
+	return res, nil
+}
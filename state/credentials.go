@@ -43,7 +43,8 @@ func (k *credentialsCollection) Schema() *memdb.TableSchema {
 					Method: "GetID2",
 				},
 			},
-			all: allIndex,
+			all:  allIndex,
+			tags: tagsIndex,
 		},
 	}
 }
@@ -92,7 +93,7 @@ func (k *credentialsCollection) Add(cred entity) error {
 	return nil
 }
 
-// Get gets a credential by ID or endpoint key.
+// Get gets a copy of a credential by ID or endpoint key.
 func (k *credentialsCollection) Get(id string) (entity, error) {
 	if id == "" {
 		return nil, errIDRequired
@@ -100,7 +101,11 @@ func (k *credentialsCollection) Get(id string) (entity, error) {
 
 	txn := k.db.Txn(false)
 	defer txn.Abort()
-	return k.getCred(txn, id)
+	cred, err := k.getCred(txn, id)
+	if err != nil {
+		return nil, err
+	}
+	return cred.copy(), nil
 }
 
 // Update updates an existing key-auth credential.
@@ -173,7 +178,7 @@ func (k *credentialsCollection) GetAll() ([]entity, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, r)
+		res = append(res, r.copy())
 	}
 	return res, nil
 }
@@ -184,6 +189,8 @@ func (k *credentialsCollection) GetAllByConsumerID(id string) ([]entity,
 	error,
 ) {
 	txn := k.db.Txn(false)
+	defer txn.Abort()
+
 	iter, err := txn.Get(k.CredType, byConsumerID, id)
 	if err != nil {
 		return nil, err
@@ -194,7 +201,29 @@ func (k *credentialsCollection) GetAllByConsumerID(id string) ([]entity,
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, r)
+		res = append(res, r.copy())
+	}
+	return res, nil
+}
+
+// GetAllByTags returns copies of the credentials holding all of tags,
+// or all the credentials if tags is empty.
+func (k *credentialsCollection) GetAllByTags(tags ...string) ([]entity, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, k.CredType, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]entity, 0, len(entities))
+	for _, el := range entities {
+		r, ok := el.(entity)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r.copy())
 	}
 	return res, nil
 }
@@ -31,7 +31,7 @@ func (k *HMACAuthsCollection) Get(keyOrID string) (*HMACAuth, error) {
 	if !ok {
 		panic(unexpectedType)
 	}
-	return &HMACAuth{HMACAuth: *hmacAuth.DeepCopy()}, nil
+	return hmacAuth, nil
 }
 
 // GetAllByConsumerID returns all hmac-auth credentials
@@ -50,7 +50,7 @@ func (k *HMACAuthsCollection) GetAllByConsumerID(id string) ([]*HMACAuth,
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &HMACAuth{HMACAuth: *r.DeepCopy()})
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -79,7 +79,26 @@ func (k *HMACAuthsCollection) GetAll() ([]*HMACAuth, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &HMACAuth{HMACAuth: *r.DeepCopy()})
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// GetAllByTags returns the hmac-auth credentials holding all of tags,
+// or all the hmac-auth credentials if tags is empty.
+func (k *HMACAuthsCollection) GetAllByTags(tags ...string) ([]*HMACAuth, error) {
+	creds, err := k.credentialsCollection.GetAllByTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*HMACAuth, 0, len(creds))
+	for _, cred := range creds {
+		r, ok := cred.(*HMACAuth)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -0,0 +1,58 @@
+package indexers
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TagsIndexer is used to create a multi-value index on the tags of an
+// object, stored in a []*string field.
+type TagsIndexer struct {
+	// Field is the name of the field holding the tags.
+	Field string
+}
+
+// FromObject takes Obj and returns one index key per tag.
+func (s *TagsIndexer) FromObject(obj interface{}) (bool, [][]byte, error) {
+	v := reflect.ValueOf(obj)
+	v = reflect.Indirect(v) // Dereference the pointer if any
+
+	fv := v.FieldByName(s.Field)
+	if !fv.IsValid() {
+		return false, nil,
+			fmt.Errorf("field '%s' for %#v is invalid", s.Field, obj)
+	}
+	tags, ok := fv.Interface().([]*string)
+	if !ok {
+		return false, nil,
+			fmt.Errorf("field '%s' for %#v must be a []*string", s.Field, obj)
+	}
+	var res [][]byte
+	for _, tag := range tags {
+		if tag == nil || *tag == "" {
+			continue
+		}
+		// Add the null character as a terminator
+		res = append(res, []byte(*tag+"\x00"))
+	}
+	if len(res) == 0 {
+		return false, nil, nil
+	}
+	return true, res, nil
+}
+
+// FromArgs takes in a tag and returns its byte form.
+func (s *TagsIndexer) FromArgs(args ...interface{}) ([]byte, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("must provide only a single argument")
+	}
+	tag, ok := args[0].(string)
+	if !ok {
+		return nil, fmt.Errorf("argument must be a string: %#v", args[0])
+	}
+	if tag == "" {
+		return nil, fmt.Errorf("empty tag is not a valid value")
+	}
+	// Add the null character as a terminator
+	return []byte(tag + "\x00"), nil
+}
@@ -0,0 +1,38 @@
+package indexers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagsIndexer(t *testing.T) {
+	assert := assert.New(t)
+
+	type Foo struct {
+		Tags []*string
+	}
+	in := &TagsIndexer{Field: "Tags"}
+
+	t1, t2, empty := "foo", "bar", ""
+	ok, res, err := in.FromObject(&Foo{Tags: []*string{&t1, nil, &empty, &t2}})
+	assert.True(ok)
+	assert.Nil(err)
+	assert.Equal([][]byte{[]byte("foo\x00"), []byte("bar\x00")}, res)
+
+	ok, res, err = in.FromObject(Foo{})
+	assert.False(ok)
+	assert.Nil(res)
+	assert.Nil(err)
+
+	_, _, err = (&TagsIndexer{Field: "Missing"}).FromObject(Foo{})
+	assert.NotNil(err)
+
+	key, err := in.FromArgs("foo")
+	assert.Nil(err)
+	assert.Equal([]byte("foo\x00"), key)
+	_, err = in.FromArgs("")
+	assert.NotNil(err)
+	_, err = in.FromArgs(1)
+	assert.NotNil(err)
+}
@@ -31,7 +31,7 @@ func (k *JWTAuthsCollection) Get(keyOrID string) (*JWTAuth, error) {
 	if !ok {
 		panic(unexpectedType)
 	}
-	return &JWTAuth{JWTAuth: *jwtAuth.DeepCopy()}, nil
+	return jwtAuth, nil
 }
 
 // GetAllByConsumerID returns all jwt-auth credentials
@@ -50,7 +50,7 @@ func (k *JWTAuthsCollection) GetAllByConsumerID(id string) ([]*JWTAuth,
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &JWTAuth{JWTAuth: *r.DeepCopy()})
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -79,7 +79,26 @@ func (k *JWTAuthsCollection) GetAll() ([]*JWTAuth, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &JWTAuth{JWTAuth: *r.DeepCopy()})
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// GetAllByTags returns the jwt credentials holding all of tags,
+// or all the jwt credentials if tags is empty.
+func (k *JWTAuthsCollection) GetAllByTags(tags ...string) ([]*JWTAuth, error) {
+	creds, err := k.credentialsCollection.GetAllByTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*JWTAuth, 0, len(creds))
+	for _, cred := range creds {
+		r, ok := cred.(*JWTAuth)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -31,7 +31,7 @@ func (k *KeyAuthsCollection) Get(keyOrID string) (*KeyAuth, error) {
 	if !ok {
 		panic(unexpectedType)
 	}
-	return &KeyAuth{KeyAuth: *keyAuth.DeepCopy()}, nil
+	return keyAuth, nil
 }
 
 // GetAllByConsumerID returns all key-auth credentials
@@ -50,7 +50,7 @@ func (k *KeyAuthsCollection) GetAllByConsumerID(id string) ([]*KeyAuth,
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &KeyAuth{KeyAuth: *r.DeepCopy()})
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -79,7 +79,26 @@ func (k *KeyAuthsCollection) GetAll() ([]*KeyAuth, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &KeyAuth{KeyAuth: *r.DeepCopy()})
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// GetAllByTags returns the key-auth credentials holding all of tags,
+// or all the key-auth credentials if tags is empty.
+func (k *KeyAuthsCollection) GetAllByTags(tags ...string) ([]*KeyAuth, error) {
+	creds, err := k.credentialsCollection.GetAllByTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*KeyAuth, 0, len(creds))
+	for _, cred := range creds {
+		r, ok := cred.(*KeyAuth)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r)
 	}
 	return res, nil
 }
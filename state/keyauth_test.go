@@ -173,6 +173,62 @@ func TestKeyAuthGetByConsumer(t *testing.T) {
 	assert.Equal(3, len(keyAuths))
 }
 
+func TestKeyAuthCopies(t *testing.T) {
+	assert := assert.New(t)
+	collection := keyAuthsCollection()
+
+	populateWithKeyAuthFixtures(assert, collection)
+
+	keyAuth, err := collection.Get("first")
+	assert.Nil(err)
+	keyAuth.Key = kong.String("changed")
+	keyAuths, err := collection.GetAll()
+	assert.Nil(err)
+	keyAuths[0].Consumer.ID = kong.String("changed")
+	keyAuths, err = collection.GetAllByConsumerID("consumer1-id")
+	assert.Nil(err)
+	keyAuths[0].Tags = kong.StringSlice("changed")
+
+	keyAuth, err = collection.Get("first")
+	assert.Nil(err)
+	assert.Equal("my-apikey11", *keyAuth.Key)
+	keyAuths, err = collection.GetAllByConsumerID("consumer1-id")
+	assert.Nil(err)
+	assert.Equal(3, len(keyAuths))
+	keyAuths, err = collection.GetAllByTags("changed")
+	assert.Nil(err)
+	assert.Empty(keyAuths)
+}
+
+func TestKeyAuthGetAllByTags(t *testing.T) {
+	assert := assert.New(t)
+	collection := keyAuthsCollection()
+
+	populateWithKeyAuthFixtures(assert, collection)
+
+	keyAuth, err := collection.Get("first")
+	assert.Nil(err)
+	keyAuth.Tags = kong.StringSlice("team-a", "prod")
+	assert.Nil(collection.Update(*keyAuth))
+	keyAuth, err = collection.Get("fourth")
+	assert.Nil(err)
+	keyAuth.Tags = kong.StringSlice("team-a")
+	assert.Nil(collection.Update(*keyAuth))
+
+	keyAuths, err := collection.GetAllByTags("team-a")
+	assert.Nil(err)
+	assert.Equal(2, len(keyAuths))
+
+	keyAuths, err = collection.GetAllByTags("team-a", "prod")
+	assert.Nil(err)
+	assert.Equal(1, len(keyAuths))
+	assert.Equal("first", *keyAuths[0].ID)
+
+	keyAuths, err = collection.GetAllByTags()
+	assert.Nil(err)
+	assert.Equal(5, len(keyAuths))
+}
+
 func populateWithKeyAuthFixtures(assert *assert.Assertions,
 	collection *KeyAuthsCollection,
 ) {
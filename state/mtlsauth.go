@@ -31,7 +31,7 @@ func (k *MTLSAuthsCollection) Get(ID string) (*MTLSAuth, error) {
 	if !ok {
 		panic(unexpectedType)
 	}
-	return &MTLSAuth{MTLSAuth: *mtlsAuth.DeepCopy()}, nil
+	return mtlsAuth, nil
 }
 
 // GetAllByConsumerID returns all mtls-auth credentials
@@ -50,7 +50,7 @@ func (k *MTLSAuthsCollection) GetAllByConsumerID(id string) ([]*MTLSAuth,
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &MTLSAuth{MTLSAuth: *r.DeepCopy()})
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -79,7 +79,26 @@ func (k *MTLSAuthsCollection) GetAll() ([]*MTLSAuth, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &MTLSAuth{MTLSAuth: *r.DeepCopy()})
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// GetAllByTags returns the mtls-auth credentials holding all of tags,
+// or all the mtls-auth credentials if tags is empty.
+func (k *MTLSAuthsCollection) GetAllByTags(tags ...string) ([]*MTLSAuth, error) {
+	creds, err := k.credentialsCollection.GetAllByTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*MTLSAuth, 0, len(creds))
+	for _, cred := range creds {
+		r, ok := cred.(*MTLSAuth)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r)
 	}
 	return res, nil
 }
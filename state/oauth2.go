@@ -31,7 +31,7 @@ func (k *Oauth2CredsCollection) Get(keyOrID string) (*Oauth2Credential, error) {
 	if !ok {
 		panic(unexpectedType)
 	}
-	return &Oauth2Credential{Oauth2Credential: *keyAuth.DeepCopy()}, nil
+	return keyAuth, nil
 }
 
 // GetAllByConsumerID returns all oauth2 credentials
@@ -50,7 +50,7 @@ func (k *Oauth2CredsCollection) GetAllByConsumerID(id string) ([]*Oauth2Credenti
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &Oauth2Credential{Oauth2Credential: *r.DeepCopy()})
+		res = append(res, r)
 	}
 	return res, nil
 }
@@ -79,7 +79,26 @@ func (k *Oauth2CredsCollection) GetAll() ([]*Oauth2Credential, error) {
 		if !ok {
 			panic(unexpectedType)
 		}
-		res = append(res, &Oauth2Credential{Oauth2Credential: *r.DeepCopy()})
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+// GetAllByTags returns the oauth2 credentials holding all of tags,
+// or all the oauth2 credentials if tags is empty.
+func (k *Oauth2CredsCollection) GetAllByTags(tags ...string) ([]*Oauth2Credential, error) {
+	creds, err := k.credentialsCollection.GetAllByTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Oauth2Credential, 0, len(creds))
+	for _, cred := range creds {
+		r, ok := cred.(*Oauth2Credential)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, r)
 	}
 	return res, nil
 }
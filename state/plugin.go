@@ -29,7 +29,8 @@ var pluginTableSchema = &memdb.TableSchema{
 			Name:    "name",
 			Indexer: &memdb.StringFieldIndex{Field: "Name"},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 		// foreign
 		pluginsByServiceID: {
 			Name: pluginsByServiceID,
@@ -349,3 +350,25 @@ func (k *PluginsCollection) GetAll() ([]*Plugin, error) {
 	}
 	return res, nil
 }
+
+// GetAllByTags returns the plugins holding all of tags,
+// or all the plugins if tags is empty.
+func (k *PluginsCollection) GetAllByTags(tags ...string) ([]*Plugin, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, pluginTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Plugin, 0, len(entities))
+	for _, el := range entities {
+		p, ok := el.(*Plugin)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Plugin{Plugin: *p.DeepCopy()})
+	}
+	return res, nil
+}
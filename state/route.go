@@ -27,7 +27,8 @@ var routeTableSchema = &memdb.TableSchema{
 			Indexer:      &memdb.StringFieldIndex{Field: "Name"},
 			AllowMissing: true,
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 		// foreign
 		routesByServiceID: {
 			Name: routesByServiceID,
@@ -190,6 +191,28 @@ func (k *RoutesCollection) GetAll() ([]*Route, error) {
 	return res, nil
 }
 
+// GetAllByTags returns the routes holding all of tags,
+// or all the routes if tags is empty.
+func (k *RoutesCollection) GetAllByTags(tags ...string) ([]*Route, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, routeTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Route, 0, len(entities))
+	for _, el := range entities {
+		r, ok := el.(*Route)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Route{Route: *r.DeepCopy()})
+	}
+	return res, nil
+}
+
 // GetAllByServiceID returns all routes referencing a service
 // by its id.
 func (k *RoutesCollection) GetAllByServiceID(id string) ([]*Route,
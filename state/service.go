@@ -25,7 +25,8 @@ var serviceTableSchema = &memdb.TableSchema{
 			Indexer:      &memdb.StringFieldIndex{Field: "Name"},
 			AllowMissing: true,
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -169,3 +170,25 @@ func (k *ServicesCollection) GetAll() ([]*Service, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the services holding all of tags,
+// or all the services if tags is empty.
+func (k *ServicesCollection) GetAllByTags(tags ...string) ([]*Service, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, serviceTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Service, 0, len(entities))
+	for _, el := range entities {
+		s, ok := el.(*Service)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Service{Service: *s.DeepCopy()})
+	}
+	return res, nil
+}
@@ -424,3 +424,67 @@ func TestServiceGetAllMemoryReference(t *testing.T) {
 	assert.NotNil(service)
 	assert.Equal("example.com", *service.Host)
 }
+
+func TestServiceGetAllByTags(t *testing.T) {
+	assert := assert.New(t)
+	collection := servicesCollection()
+
+	services := []Service{
+		{
+			Service: kong.Service{
+				ID:   kong.String("first"),
+				Name: kong.String("my-service1"),
+				Tags: kong.StringSlice("team-a", "prod"),
+			},
+		},
+		{
+			Service: kong.Service{
+				ID:   kong.String("second"),
+				Name: kong.String("my-service2"),
+				Tags: kong.StringSlice("team-a"),
+			},
+		},
+		{
+			Service: kong.Service{
+				ID:   kong.String("third"),
+				Name: kong.String("my-service3"),
+			},
+		},
+	}
+	for _, s := range services {
+		assert.Nil(collection.Add(s))
+	}
+
+	res, err := collection.GetAllByTags("team-a")
+	assert.Nil(err)
+	assert.Len(res, 2)
+
+	res, err = collection.GetAllByTags("team-a", "prod")
+	assert.Nil(err)
+	assert.Len(res, 1)
+	assert.Equal("first", *res[0].ID)
+
+	res, err = collection.GetAllByTags("team-b")
+	assert.Nil(err)
+	assert.Empty(res)
+
+	res, err = collection.GetAllByTags()
+	assert.Nil(err)
+	assert.Len(res, 3)
+
+	// the index follows updates, and results are copies
+	res, err = collection.GetAllByTags("prod")
+	assert.Nil(err)
+	res[0].Tags = kong.StringSlice("team-b")
+	res, err = collection.GetAllByTags("team-b")
+	assert.Nil(err)
+	assert.Empty(res)
+	svc, err := collection.Get("second")
+	assert.Nil(err)
+	svc.Tags = kong.StringSlice("team-b")
+	assert.Nil(collection.Update(*svc))
+	res, err = collection.GetAllByTags("team-b")
+	assert.Nil(err)
+	assert.Len(res, 1)
+	assert.Equal("second", *res[0].ID)
+}
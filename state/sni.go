@@ -29,7 +29,8 @@ var sniTableSchema = &memdb.TableSchema{
 			Indexer:      &memdb.StringFieldIndex{Field: "Name"},
 			AllowMissing: true,
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 		// foreign
 		snisByCertID: {
 			Name: snisByCertID,
@@ -203,6 +204,28 @@ func (k *SNIsCollection) GetAll() ([]*SNI, error) {
 	return res, nil
 }
 
+// GetAllByTags returns the SNIs holding all of tags,
+// or all the SNIs if tags is empty.
+func (k *SNIsCollection) GetAllByTags(tags ...string) ([]*SNI, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, sniTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*SNI, 0, len(entities))
+	for _, el := range entities {
+		s, ok := el.(*SNI)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &SNI{SNI: *s.DeepCopy()})
+	}
+	return res, nil
+}
+
 // GetAllByCertID returns all routes referencing a service
 // by its id.
 func (k *SNIsCollection) GetAllByCertID(id string) ([]*SNI,
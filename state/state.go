@@ -12,6 +12,11 @@ type collection struct {
 
 // KongState is an in-memory database representation
 // of Kong's configuration.
+// Entities are indexed by ID, by name when they have one, and by tags
+// when they carry tags: RBAC roles, consumer-group members and plugins,
+// and Konnect service packages and versions have none.
+// It is safe for concurrent use, and entities returned by collections
+// are copies, which can be modified without affecting the state.
 type KongState struct {
 	common                 collection
 	Services               *ServicesCollection
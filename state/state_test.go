@@ -1,9 +1,14 @@
 package state
 
 import (
+	"fmt"
+	"sync"
 	"testing"
 
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewState(t *testing.T) {
@@ -20,3 +25,45 @@ func state() *KongState {
 	}
 	return s
 }
+
+func TestStateConcurrentAccess(t *testing.T) {
+	raw := &utils.KongRawState{}
+	for i := 0; i < 10; i++ {
+		raw.Services = append(raw.Services, &kong.Service{
+			ID:   kong.String(fmt.Sprintf("s%d", i)),
+			Name: kong.String(fmt.Sprintf("svc%d", i)),
+			Tags: kong.StringSlice("team-a"),
+		})
+	}
+	s, err := Get(raw)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			svc, err := s.Services.Get(fmt.Sprintf("svc%d", i))
+			assert.NoError(t, err)
+			svc.Tags = kong.StringSlice("team-b")
+			assert.NoError(t, s.Services.Update(*svc))
+		}(i)
+		go func() {
+			defer wg.Done()
+			services, err := s.Services.GetAllByTags("team-a")
+			assert.NoError(t, err)
+			for _, svc := range services {
+				// results are copies, safe to modify
+				svc.Tags = nil
+			}
+		}()
+	}
+	wg.Wait()
+
+	services, err := s.Services.GetAllByTags("team-b")
+	require.NoError(t, err)
+	assert.Len(t, services, 10)
+	services, err = s.Services.GetAllByTags("team-a")
+	require.NoError(t, err)
+	assert.Empty(t, services)
+}
@@ -23,7 +23,8 @@ var targetTableSchema = &memdb.TableSchema{
 			Unique:  true,
 			Indexer: &memdb.StringFieldIndex{Field: "ID"},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 		// foreign
 		targetsByUpstreamID: {
 			Name: targetsByUpstreamID,
@@ -225,6 +226,28 @@ func (k *TargetsCollection) GetAll() ([]*Target, error) {
 	return res, nil
 }
 
+// GetAllByTags returns the targets holding all of tags,
+// or all the targets if tags is empty.
+func (k *TargetsCollection) GetAllByTags(tags ...string) ([]*Target, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, targetTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Target, 0, len(entities))
+	for _, el := range entities {
+		t, ok := el.(*Target)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Target{Target: *t.DeepCopy()})
+	}
+	return res, nil
+}
+
 // GetAllByUpstreamID returns all targets referencing a Upstream
 // by its ID.
 func (k *TargetsCollection) GetAllByUpstreamID(id string) ([]*Target,
@@ -17,6 +17,8 @@ type entity interface {
 	GetID2() string
 	// Consumer returns consumer ID associated with the cred.
 	GetConsumer() string
+	// copy returns a deep copy of the cred.
+	copy() entity
 }
 
 // ConsoleString contains methods to be used to print
@@ -829,6 +831,10 @@ func (k1 *KeyAuth) GetConsumer() string {
 	return *k1.Consumer.ID
 }
 
+func (k1 *KeyAuth) copy() entity {
+	return &KeyAuth{KeyAuth: *k1.DeepCopy()}
+}
+
 // HMACAuth represents a key-auth credential in Kong.
 // It adds some helper methods along with Meta to the original HMACAuth object.
 type HMACAuth struct {
@@ -907,6 +913,10 @@ func (h1 *HMACAuth) GetConsumer() string {
 	return *h1.Consumer.ID
 }
 
+func (h1 *HMACAuth) copy() entity {
+	return &HMACAuth{HMACAuth: *h1.DeepCopy()}
+}
+
 // JWTAuth represents a jwt credential in Kong.
 // It adds some helper methods along with Meta to the original JWTAuth object.
 type JWTAuth struct {
@@ -985,6 +995,10 @@ func (j1 *JWTAuth) GetConsumer() string {
 	return *j1.Consumer.ID
 }
 
+func (j1 *JWTAuth) copy() entity {
+	return &JWTAuth{JWTAuth: *j1.DeepCopy()}
+}
+
 // BasicAuth represents a basic-auth credential in Kong.
 // It adds some helper methods along with Meta to the original BasicAuth object.
 type BasicAuth struct {
@@ -1067,6 +1081,10 @@ func (b1 *BasicAuth) GetConsumer() string {
 	return *b1.Consumer.ID
 }
 
+func (b1 *BasicAuth) copy() entity {
+	return &BasicAuth{BasicAuth: *b1.DeepCopy()}
+}
+
 // ACLGroup represents an ACL group for a consumer in Kong.
 // It adds some helper methods along with Meta to the original ACLGroup object.
 type ACLGroup struct {
@@ -1246,6 +1264,10 @@ func (k1 *Oauth2Credential) GetConsumer() string {
 	return *k1.Consumer.ID
 }
 
+func (k1 *Oauth2Credential) copy() entity {
+	return &Oauth2Credential{Oauth2Credential: *k1.DeepCopy()}
+}
+
 // MTLSAuth represents an mtls-auth credential in Kong.
 // It adds some helper methods along with Meta to the original MTLSAuth object.
 type MTLSAuth struct {
@@ -1420,6 +1442,10 @@ func (b1 *MTLSAuth) GetConsumer() string {
 	return *b1.Consumer.ID
 }
 
+func (b1 *MTLSAuth) copy() entity {
+	return &MTLSAuth{MTLSAuth: *b1.DeepCopy()}
+}
+
 // Vault represents a vault in Kong.
 // It adds some helper methods along with Meta to the original Vault object.
 type Vault struct {
@@ -24,7 +24,8 @@ var upstreamTableSchema = &memdb.TableSchema{
 			Unique:  true,
 			Indexer: &memdb.StringFieldIndex{Field: "Name"},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -175,3 +176,25 @@ func (k *UpstreamsCollection) GetAll() ([]*Upstream, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the upstreams holding all of tags,
+// or all the upstreams if tags is empty.
+func (k *UpstreamsCollection) GetAllByTags(tags ...string) ([]*Upstream, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, upstreamTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Upstream, 0, len(entities))
+	for _, el := range entities {
+		u, ok := el.(*Upstream)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Upstream{Upstream: *u.DeepCopy()})
+	}
+	return res, nil
+}
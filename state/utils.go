@@ -2,12 +2,15 @@ package state
 
 import (
 	"fmt"
+	"reflect"
 
 	memdb "github.com/hashicorp/go-memdb"
+	"github.com/kong/deck/state/indexers"
 )
 
 const (
-	all = "all"
+	all  = "all"
+	tags = "tags"
 )
 
 // ErrNotFound is an error type that is
@@ -34,6 +37,58 @@ var allIndex = &memdb.IndexSchema{
 	},
 }
 
+var tagsIndex = &memdb.IndexSchema{
+	Name:         tags,
+	Indexer:      &indexers.TagsIndexer{Field: "Tags"},
+	AllowMissing: true,
+}
+
+// getAllByTags returns the entities of tableName holding all of
+// tagList, or all the entities if tagList is empty.
+// The table must have a tags index.
+func getAllByTags(txn *memdb.Txn, tableName string, tagList []string) ([]interface{}, error) {
+	var iter memdb.ResultIterator
+	var err error
+	var rest []string
+	if len(tagList) == 0 {
+		iter, err = txn.Get(tableName, all, true)
+	} else {
+		iter, err = txn.Get(tableName, tags, tagList[0])
+		rest = tagList[1:]
+	}
+	if err != nil {
+		return nil, err
+	}
+	var res []interface{}
+	for el := iter.Next(); el != nil; el = iter.Next() {
+		if hasTags(el, rest...) {
+			res = append(res, el)
+		}
+	}
+	return res, nil
+}
+
+// hasTags returns whether the Tags field of entity holds all of tagList.
+func hasTags(entity interface{}, tagList ...string) bool {
+	if len(tagList) == 0 {
+		return true
+	}
+	v := reflect.Indirect(reflect.ValueOf(entity)).FieldByName("Tags")
+	entityTags, _ := v.Interface().([]*string)
+	set := make(map[string]bool, len(entityTags))
+	for _, tag := range entityTags {
+		if tag != nil {
+			set[*tag] = true
+		}
+	}
+	for _, tag := range tagList {
+		if !set[tag] {
+			return false
+		}
+	}
+	return true
+}
+
 // multiIndexLookupUsingTxn can be used to search for an entity
 // based on search on multiple indexes with same key.
 func multiIndexLookupUsingTxn(txn *memdb.Txn, tableName string,
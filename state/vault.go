@@ -24,7 +24,8 @@ var vaultTableSchema = &memdb.TableSchema{
 			Unique:  true,
 			Indexer: &memdb.StringFieldIndex{Field: "Prefix"},
 		},
-		all: allIndex,
+		all:  allIndex,
+		tags: tagsIndex,
 	},
 }
 
@@ -173,3 +174,25 @@ func (k *VaultsCollection) GetAll() ([]*Vault, error) {
 	txn.Commit()
 	return res, nil
 }
+
+// GetAllByTags returns the vaults holding all of tags,
+// or all the vaults if tags is empty.
+func (k *VaultsCollection) GetAllByTags(tags ...string) ([]*Vault, error) {
+	txn := k.db.Txn(false)
+	defer txn.Abort()
+
+	entities, err := getAllByTags(txn, vaultTableName, tags)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Vault, 0, len(entities))
+	for _, el := range entities {
+		v, ok := el.(*Vault)
+		if !ok {
+			panic(unexpectedType)
+		}
+		res = append(res, &Vault{Vault: *v.DeepCopy()})
+	}
+	return res, nil
+}
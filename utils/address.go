@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// AddressError is returned when the address of Kong's Admin API
+// or Konnect is malformed.
+type AddressError struct {
+	Address string
+	Reason  string
+}
+
+func (e *AddressError) Error() string {
+	return fmt.Sprintf("invalid address '%s': %s", e.Address, e.Reason)
+}
+
+// ParseAddress parses and normalizes the address of Kong's Admin API
+// or Konnect.
+//
+// The address must be an absolute http(s) URL, or a unix domain socket
+// address like `unix:///run/kong/admin.sock`. It may contain a path
+// prefix, e.g. when the Admin API is served behind a reverse proxy at
+// `https://example.com/admin-api`; duplicate and trailing slashes are
+// removed from the prefix so that endpoints can be appended to it.
+// Query strings and fragments are rejected.
+func ParseAddress(address string) (*url.URL, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, &AddressError{Address: address, Reason: "address cannot be empty"}
+	}
+	if socketPath, ok := unixSocketPath(address); ok {
+		if socketPath == "" {
+			return nil, &AddressError{
+				Address: address,
+				Reason:  "unix socket address must include the path of the socket",
+			}
+		}
+		return &url.URL{Scheme: "unix", Path: socketPath}, nil
+	}
+
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, &AddressError{Address: address, Reason: err.Error()}
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, &AddressError{Address: address, Reason: "scheme must be http or https"}
+	}
+	if u.Host == "" {
+		return nil, &AddressError{Address: address, Reason: "host cannot be empty"}
+	}
+	if u.RawQuery != "" || u.ForceQuery || u.Fragment != "" {
+		return nil, &AddressError{Address: address, Reason: "query strings and fragments are not supported"}
+	}
+	u.Host = strings.ToLower(u.Host)
+	u.Path = cleanPathPrefix(u.Path)
+	u.RawPath = ""
+	return u, nil
+}
+
+// cleanPathPrefix removes empty segments from path, so that joining
+// it with an endpoint starting with a slash yields a valid path.
+func cleanPathPrefix(path string) string {
+	var segments []string
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	if len(segments) == 0 {
+		return ""
+	}
+	return "/" + strings.Join(segments, "/")
+}
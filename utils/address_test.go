@@ -0,0 +1,81 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"http://localhost:8001", "http://localhost:8001"},
+		{"http://localhost:8001/", "http://localhost:8001"},
+		{" HTTPS://Kong.Example.com ", "https://kong.example.com"},
+		{"https://example.com/admin-api/", "https://example.com/admin-api"},
+		{"https://example.com//admin-api//v1///", "https://example.com/admin-api/v1"},
+		{"unix:///run/kong/admin.sock", "unix:///run/kong/admin.sock"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			u, err := ParseAddress(tt.address)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, u.String())
+		})
+	}
+}
+
+func TestParseAddressErrors(t *testing.T) {
+	for _, address := range []string{
+		"",
+		"localhost:8001",
+		"ftp://localhost",
+		"http://",
+		"/admin-api",
+		"http://localhost:8001?foo=bar",
+		"http://localhost:8001/#fragment",
+		"http://local host",
+		"unix://",
+	} {
+		t.Run(address, func(t *testing.T) {
+			_, err := ParseAddress(address)
+			var addrErr *AddressError
+			require.True(t, errors.As(err, &addrErr), "unexpected error: %v", err)
+			assert.Equal(t, address, addrErr.Address)
+		})
+	}
+}
+
+func TestGetKongClientPathPrefix(t *testing.T) {
+	var paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		_, _ = w.Write([]byte(`{"id":"s1"}`))
+	}))
+	defer server.Close()
+
+	client, err := GetKongClient(KongClientConfig{
+		Address:   server.URL + "/admin-api//",
+		Workspace: "team-a",
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = client.Services.Get(ctx, kong.String("s1"))
+	require.NoError(t, err)
+	_, err = client.Root(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/admin-api/team-a/services/s1", "/admin-api/team-a/kong"}, paths)
+
+	_, err = GetKongClient(KongClientConfig{Address: "localhost:8001"})
+	var addrErr *AddressError
+	assert.True(t, errors.As(err, &addrErr))
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of sending requests while the
+// circuit of a CircuitBreaker is open.
+type ErrCircuitOpen struct {
+	// Failures is the number of consecutive failures which opened
+	// the circuit.
+	Failures int
+	// Until is the time at which a request is let through again to probe
+	// the Admin API.
+	Until time.Time
+}
+
+// Error returns a description of the error.
+func (e ErrCircuitOpen) Error() string {
+	return fmt.Sprintf("circuit open after %d consecutive failures of Kong's Admin API, "+
+		"retrying after %s", e.Failures, e.Until.Format(time.RFC3339))
+}
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// CircuitBreaker stops sending requests to an Admin API which keeps
+// failing. After threshold consecutive failures, the circuit opens and
+// requests fail fast with ErrCircuitOpen for the cool-down period.
+// Then, the circuit is half-open: a single request is let through to
+// probe the Admin API, which closes the circuit if it succeeds and opens
+// it again otherwise.
+// Failures are transport errors and 5xx responses.
+// It is safe for concurrent use, so a single CircuitBreaker can be shared
+// across all clients talking to the same Admin API.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	state     circuitState
+	failures  int
+	openUntil time.Time
+
+	now func() time.Time
+}
+
+// NewCircuitBreaker returns a CircuitBreaker opening after threshold
+// consecutive failures, for cooldown.
+// It returns nil if threshold is not positive, meaning no circuit breaker.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{threshold: threshold, cooldown: cooldown, now: time.Now}
+}
+
+// allow returns an error if a request cannot be sent.
+// In the half-open state, it lets through a single probe and reports
+// whether the request is that probe.
+func (b *CircuitBreaker) allow() (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case circuitOpen:
+		if b.now().Before(b.openUntil) {
+			return false, ErrCircuitOpen{Failures: b.failures, Until: b.openUntil}
+		}
+		b.state = circuitHalfOpen
+		return true, nil
+	case circuitHalfOpen:
+		// a probe is in flight
+		return false, ErrCircuitOpen{Failures: b.failures, Until: b.openUntil}
+	default:
+		return false, nil
+	}
+}
+
+// record updates the state of the circuit with the outcome of a request.
+func (b *CircuitBreaker) record(probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.state = circuitClosed
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if probe || (b.state == circuitClosed && b.failures >= b.threshold) {
+		b.state = circuitOpen
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// cancel releases the probe of a half-open circuit whose outcome
+// is unknown, e.g. because the request was canceled.
+func (b *CircuitBreaker) cancel(probe bool) {
+	if !probe {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == circuitHalfOpen {
+		b.state = circuitOpen
+	}
+}
+
+// circuitBreakerTransport sends requests through a CircuitBreaker.
+type circuitBreakerTransport struct {
+	breaker *CircuitBreaker
+	next    http.RoundTripper
+}
+
+func (t *circuitBreakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	probe, err := t.breaker.allow()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil && errors.Is(err, context.Canceled) {
+		t.breaker.cancel(probe)
+		return resp, err
+	}
+	t.breaker.record(probe, err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	return resp, err
+}
+
+// withCircuitBreaker wraps transport so that requests fail fast
+// while the circuit of breaker is open.
+func withCircuitBreaker(transport http.RoundTripper, breaker *CircuitBreaker) http.RoundTripper {
+	if breaker == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &circuitBreakerTransport{breaker: breaker, next: transport}
+}
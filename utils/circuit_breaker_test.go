@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCircuitBreaker(t *testing.T) {
+	assert.Nil(t, NewCircuitBreaker(0, time.Second))
+	assert.NotNil(t, NewCircuitBreaker(1, time.Second))
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	var status, requests atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	breaker := NewCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+	client, err := GetKongClient(KongClientConfig{Address: server.URL, CircuitBreaker: breaker})
+	require.NoError(t, err)
+	ctx := context.Background()
+	get := func() error {
+		_, err := client.Services.ListAll(ctx)
+		return err
+	}
+
+	// failures below the threshold are returned as is
+	for i := 0; i < 3; i++ {
+		err := get()
+		require.Error(t, err)
+		assert.False(t, errors.As(err, &ErrCircuitOpen{}))
+	}
+	assert.Equal(t, int32(3), requests.Load())
+
+	// the circuit is open: requests fail fast
+	err = get()
+	var circuitErr ErrCircuitOpen
+	require.ErrorAs(t, err, &circuitErr)
+	assert.Equal(t, 3, circuitErr.Failures)
+	assert.Equal(t, now.Add(time.Minute), circuitErr.Until)
+	assert.Equal(t, int32(3), requests.Load())
+
+	// a failed probe opens the circuit again
+	now = now.Add(time.Minute)
+	require.Error(t, get())
+	assert.Equal(t, int32(4), requests.Load())
+	require.ErrorAs(t, get(), &circuitErr)
+	assert.Equal(t, int32(4), requests.Load())
+
+	// a successful probe closes the circuit
+	now = now.Add(time.Minute)
+	status.Store(http.StatusOK)
+	require.NoError(t, get())
+	require.NoError(t, get())
+	assert.Equal(t, int32(6), requests.Load())
+
+	// client errors are not failures
+	status.Store(http.StatusNotFound)
+	for i := 0; i < 5; i++ {
+		require.Error(t, get())
+	}
+	assert.Equal(t, int32(11), requests.Load())
+}
+
+func TestCircuitBreakerHalfOpenSingleProbe(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 0)
+	breaker.record(false, true)
+	probe, err := breaker.allow()
+	require.NoError(t, err)
+	assert.True(t, probe)
+	_, err = breaker.allow()
+	assert.ErrorAs(t, err, &ErrCircuitOpen{})
+
+	// a canceled probe leaves the circuit open for the next probe
+	breaker.cancel(probe)
+	probe, err = breaker.allow()
+	require.NoError(t, err)
+	assert.True(t, probe)
+}
@@ -0,0 +1,108 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// compressionTransport compresses large request bodies with gzip and
+// decompresses gzipped responses.
+type compressionTransport struct {
+	// minSize is the size from which request bodies are compressed.
+	// Request bodies are never compressed if it is not positive.
+	minSize int
+	next    http.RoundTripper
+}
+
+func (t *compressionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, err := t.compressRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	// the standard transport only decompresses responses transparently
+	// if it asked for gzip itself, which it doesn't if the header is set
+	// e.g. with --headers.
+	if req.Header.Get("Accept-Encoding") == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip")
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") ||
+		resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return resp, nil
+	}
+	body, err := gzip.NewReader(resp.Body)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	resp.Body = &gzipResponseBody{Reader: body, body: resp.Body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// compressRequest returns a copy of req with its body compressed, if
+// it is large enough and not already encoded.
+func (t *compressionTransport) compressRequest(req *http.Request) (*http.Request, error) {
+	if t.minSize <= 0 || req.Header.Get("Content-Encoding") != "" {
+		return req, nil
+	}
+	if req.ContentLength >= 0 && req.ContentLength < int64(t.minSize) {
+		return req, nil
+	}
+	body, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < t.minSize {
+		return req, nil
+	}
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(body); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	compressed := buf.Bytes()
+	req = req.Clone(req.Context())
+	req.Header.Set("Content-Encoding", "gzip")
+	req.ContentLength = int64(len(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	req.Body, _ = req.GetBody()
+	return req, nil
+}
+
+// gzipResponseBody closes both the gzip reader and the
+// underlying response body.
+type gzipResponseBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipResponseBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// withCompression wraps transport so that request bodies of at least
+// minSize bytes are compressed with gzip, and gzipped responses are
+// decompressed.
+func withCompression(transport http.RoundTripper, minSize int) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &compressionTransport{minSize: minSize, next: transport}
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKongClientCompression(t *testing.T) {
+	var encodings, bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		var body io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(r.Body)
+			require.NoError(t, err)
+			body = gz
+		}
+		b, err := io.ReadAll(body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+
+		assert.Equal(t, "gzip", r.Header.Get("Accept-Encoding"))
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		gz := gzip.NewWriter(w)
+		_, _ = gz.Write(b)
+		_ = gz.Close()
+	}))
+	defer server.Close()
+
+	client, err := GetKongClient(KongClientConfig{
+		Address:            server.URL,
+		CompressionMinSize: 100,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	small, err := client.Services.Create(ctx, &kong.Service{Name: kong.String("small")})
+	require.NoError(t, err)
+	assert.Equal(t, "small", *small.Name)
+
+	name := strings.Repeat("x", 200)
+	large, err := client.Services.Create(ctx, &kong.Service{Name: kong.String(name)})
+	require.NoError(t, err)
+	assert.Equal(t, name, *large.Name)
+
+	assert.Equal(t, []string{"", "gzip"}, encodings)
+	assert.Contains(t, bodies[1], name)
+}
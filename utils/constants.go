@@ -74,6 +74,13 @@ var (
 			"window_type": "sliding",
 		},
 	}
+	jwtAuthDefaults = kong.JWTAuth{
+		Algorithm: kong.String("HS256"),
+	}
+	oauth2CredDefaults = kong.Oauth2Credential{
+		ClientType: kong.String("confidential"),
+		HashSecret: kong.Bool(false),
+	}
 	defaultsRestrictedFields = map[string][]string{
 		"Service":  {"ID", "Name"},
 		"Route":    {"ID", "Name"},
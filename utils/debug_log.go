@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const redactedValue = "[REDACTED]"
+
+// DebugLevel controls how much of the requests and responses exchanged
+// with Kong or Konnect is logged.
+type DebugLevel int
+
+const (
+	// DebugLevelNone disables debug logging.
+	DebugLevelNone DebugLevel = iota
+	// DebugLevelHeaders logs the method, URL, status and headers of
+	// requests and responses, and the size of their bodies.
+	DebugLevelHeaders
+	// DebugLevelFullBody also logs the bodies of requests and responses.
+	DebugLevelFullBody
+)
+
+func (l DebugLevel) String() string {
+	switch l {
+	case DebugLevelNone:
+		return "none"
+	case DebugLevelHeaders:
+		return "headers"
+	case DebugLevelFullBody:
+		return "full-body"
+	default:
+		return fmt.Sprintf("DebugLevel(%d)", int(l))
+	}
+}
+
+// Logger is the structured logger used to print debug information
+// about requests sent to Kong or Konnect.
+// It is satisfied by *slog.Logger.
+type Logger interface {
+	Debug(msg string, args ...any)
+}
+
+// sensitiveHeaders are request and response headers whose
+// value is never printed in debug output.
+var sensitiveHeaders = []string{
+	"Authorization",
+	"Proxy-Authorization",
+	"Kong-Admin-Token",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// sensitiveFields are fields of JSON and form-encoded bodies
+// whose value is never printed in debug output.
+var sensitiveFields = map[string]struct{}{
+	"password":      {},
+	"secret":        {},
+	"client_secret": {},
+	"key":           {},
+	"key_alt":       {},
+	"private_key":   {},
+	"jwk":           {},
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"cert_key":      {},
+}
+
+// writerLogger is a Logger printing one logfmt-like line
+// per message to an io.Writer.
+type writerLogger struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterLogger returns a Logger writing messages to w.
+func NewWriterLogger(w io.Writer) Logger {
+	return &writerLogger{w: w}
+}
+
+func (l *writerLogger) Debug(msg string, args ...any) {
+	var b strings.Builder
+	b.WriteString(msg)
+	for i := 0; i+1 < len(args); i += 2 {
+		fmt.Fprintf(&b, " %v=", args[i])
+		switch v := args[i+1].(type) {
+		case string:
+			fmt.Fprintf(&b, "%q", v)
+		default:
+			fmt.Fprintf(&b, "%v", v)
+		}
+	}
+	b.WriteByte('\n')
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, b.String())
+}
+
+// RedactHeaders returns a copy of headers where the values
+// of sensitive headers are replaced.
+func RedactHeaders(headers http.Header) http.Header {
+	res := headers.Clone()
+	for _, name := range sensitiveHeaders {
+		if values := res.Values(name); len(values) > 0 {
+			res.Set(name, redactedValue)
+		}
+	}
+	return res
+}
+
+// RedactBody returns a copy of a JSON or form-encoded body where
+// the values of sensitive fields such as credential secrets and
+// certificate keys are replaced.
+// Bodies which can't be parsed are returned as is.
+func RedactBody(contentType string, body []byte) []byte {
+	if len(body) == 0 {
+		return body
+	}
+	if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return body
+		}
+		for field := range values {
+			if _, ok := sensitiveFields[field]; ok {
+				values.Set(field, redactedValue)
+			}
+		}
+		return []byte(values.Encode())
+	}
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return body
+	}
+	res, err := json.Marshal(redactJSON(v))
+	if err != nil {
+		return body
+	}
+	return res
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for field, value := range v {
+			if _, ok := sensitiveFields[field]; ok && value != nil {
+				v[field] = redactedValue
+				continue
+			}
+			v[field] = redactJSON(value)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactJSON(v[i])
+		}
+	}
+	return v
+}
+
+// debugTransport logs requests and responses
+// with sensitive information redacted.
+type debugTransport struct {
+	logger Logger
+	level  DebugLevel
+	// sampleRate logs 1 in sampleRate requests, if greater than 1.
+	sampleRate uint64
+	count      uint64
+	next       http.RoundTripper
+}
+
+// sampled reports whether the next request must be logged.
+// The first request is always logged.
+func (t *debugTransport) sampled() bool {
+	if t.sampleRate <= 1 {
+		return true
+	}
+	return (atomic.AddUint64(&t.count, 1)-1)%t.sampleRate == 0
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.sampled() {
+		return t.next.RoundTrip(req)
+	}
+	if t.level < DebugLevelFullBody {
+		return t.roundTripHeaders(req)
+	}
+	reqBody, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	t.logger.Debug("request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"headers", RedactHeaders(req.Header),
+		"body", string(RedactBody(req.Header.Get("Content-Type"), reqBody)),
+	)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.Debug("request failed",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"duration", time.Since(start),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	t.logger.Debug("response",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"duration", time.Since(start),
+		"headers", RedactHeaders(resp.Header),
+		"body", string(RedactBody(resp.Header.Get("Content-Type"), respBody)),
+	)
+	return resp, nil
+}
+
+// roundTripHeaders sends req, logging everything but the bodies of the
+// request and response, which are neither read nor buffered.
+func (t *debugTransport) roundTripHeaders(req *http.Request) (*http.Response, error) {
+	t.logger.Debug("request",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"headers", RedactHeaders(req.Header),
+		"content_length", req.ContentLength,
+	)
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		t.logger.Debug("request failed",
+			"method", req.Method,
+			"url", req.URL.String(),
+			"duration", time.Since(start),
+			"error", err.Error(),
+		)
+		return nil, err
+	}
+	t.logger.Debug("response",
+		"method", req.Method,
+		"url", req.URL.String(),
+		"status", resp.StatusCode,
+		"duration", time.Since(start),
+		"headers", RedactHeaders(resp.Header),
+		"content_length", resp.ContentLength,
+	)
+	return resp, nil
+}
+
+// peekRequestBody reads the body of req without consuming it.
+func peekRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		return io.ReadAll(body)
+	}
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// withDebugLogging wraps transport so that requests and responses are
+// logged to logger, with the details of level.
+// If sampleRate is greater than 1, only 1 in sampleRate requests is logged.
+func withDebugLogging(transport http.RoundTripper, logger Logger,
+	level DebugLevel, sampleRate int,
+) http.RoundTripper {
+	if logger == nil || level <= DebugLevelNone {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if sampleRate < 1 {
+		sampleRate = 1
+	}
+	return &debugTransport{
+		logger:     logger,
+		level:      level,
+		sampleRate: uint64(sampleRate),
+		next:       transport,
+	}
+}
@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactHeaders(t *testing.T) {
+	headers := http.Header{}
+	headers.Set("Kong-Admin-Token", "s3cr3t")
+	headers.Set("Authorization", "Bearer kpat_foo")
+	headers.Set("Content-Type", "application/json")
+
+	redacted := RedactHeaders(headers)
+	assert.Equal(t, redactedValue, redacted.Get("Kong-Admin-Token"))
+	assert.Equal(t, redactedValue, redacted.Get("Authorization"))
+	assert.Equal(t, "application/json", redacted.Get("Content-Type"))
+	assert.Equal(t, "s3cr3t", headers.Get("Kong-Admin-Token"))
+}
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{
+			name:        "json credential",
+			contentType: "application/json",
+			body:        `{"key":"my-api-key","consumer":{"id":"c1"}}`,
+			expected:    `{"key":"[REDACTED]","consumer":{"id":"c1"}}`,
+		},
+		{
+			name:        "nested list",
+			contentType: "application/json",
+			body:        `{"data":[{"username":"foo","password":"bar"},{"cert":"pem","key":"pem"}]}`,
+			expected:    `{"data":[{"username":"foo","password":"[REDACTED]"},{"cert":"pem","key":"[REDACTED]"}]}`,
+		},
+		{
+			name:        "certificate",
+			contentType: "application/json",
+			body:        `{"cert":"pem","key":"pem","cert_alt":"pem","key_alt":"pem"}`,
+			expected:    `{"cert":"pem","key":"[REDACTED]","cert_alt":"pem","key_alt":"[REDACTED]"}`,
+		},
+		{
+			name:        "form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        "client_id=foo&client_secret=bar",
+			expected:    "client_id=foo&client_secret=%5BREDACTED%5D",
+		},
+		{
+			name:        "unparsable",
+			contentType: "text/plain",
+			body:        "not json",
+			expected:    "not json",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := RedactBody(tt.contentType, []byte(tt.body))
+			if tt.contentType == "application/json" {
+				assert.JSONEq(t, tt.expected, string(res))
+				return
+			}
+			assert.Equal(t, tt.expected, string(res))
+		})
+	}
+}
+
+func TestGetKongClientDebugLogging(t *testing.T) {
+	var receivedBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := new(bytes.Buffer)
+		_, _ = buf.ReadFrom(r.Body)
+		receivedBody = buf.String()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"id":"k1","key":"my-api-key"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client, err := GetKongClient(KongClientConfig{
+		Address:    server.URL,
+		Headers:    []string{"Kong-Admin-Token:s3cr3t"},
+		DebugLevel: DebugLevelFullBody,
+		Logger:     NewWriterLogger(&out),
+	})
+	require.NoError(t, err)
+
+	keyAuth, err := client.KeyAuths.Create(context.Background(), kong.String("c1"),
+		&kong.KeyAuth{Key: kong.String("my-api-key")})
+	require.NoError(t, err)
+	assert.Equal(t, "my-api-key", *keyAuth.Key)
+	assert.Contains(t, receivedBody, "my-api-key")
+
+	assert.Contains(t, out.String(), "request method=\"POST\"")
+	assert.Contains(t, out.String(), "response method=\"POST\"")
+	assert.Contains(t, out.String(), redactedValue)
+	assert.NotContains(t, out.String(), "s3cr3t")
+	assert.NotContains(t, out.String(), "my-api-key")
+}
+
+func TestGetKongClientDebugLevelHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"s1","name":"response-body"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client, err := GetKongClient(KongClientConfig{
+		Address:    server.URL,
+		DebugLevel: DebugLevelHeaders,
+		Logger:     NewWriterLogger(&out),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Services.Create(context.Background(),
+		&kong.Service{Name: kong.String("request-body"), Host: kong.String("example.com")})
+	require.NoError(t, err)
+
+	assert.Contains(t, out.String(), "request method=\"POST\"")
+	assert.Contains(t, out.String(), "response method=\"POST\"")
+	assert.Contains(t, out.String(), "content_length=")
+	assert.NotContains(t, out.String(), "request-body")
+	assert.NotContains(t, out.String(), "response-body")
+}
+
+func TestGetKongClientDebugSampleRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client, err := GetKongClient(KongClientConfig{
+		Address:         server.URL,
+		DebugLevel:      DebugLevelHeaders,
+		DebugSampleRate: 3,
+		Logger:          NewWriterLogger(&out),
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 7; i++ {
+		_, _, err := client.Services.List(context.Background(), nil)
+		require.NoError(t, err)
+	}
+	// requests 1, 4 and 7 are logged
+	assert.Equal(t, 3, strings.Count(out.String(), "response method="))
+}
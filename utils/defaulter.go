@@ -2,6 +2,7 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -32,6 +33,8 @@ type Defaulter struct {
 	upstream            *kong.Upstream
 	target              *kong.Target
 	consumerGroupPlugin *kong.ConsumerGroupPlugin
+	jwtAuth             *kong.JWTAuth
+	oauth2Cred          *kong.Oauth2Credential
 }
 
 type DefaulterOpts struct {
@@ -49,6 +52,8 @@ func NewDefaulter() *Defaulter {
 		upstream:            &kong.Upstream{},
 		target:              &kong.Target{},
 		consumerGroupPlugin: &kong.ConsumerGroupPlugin{},
+		jwtAuth:             &kong.JWTAuth{},
+		oauth2Cred:          &kong.Oauth2Credential{},
 	}
 }
 
@@ -84,6 +89,14 @@ func getKongDefaulter(opts DefaulterOpts) (*Defaulter, error) {
 	if err != nil {
 		return nil, fmt.Errorf("registering consumer-group-plugin with defaulter: %w", err)
 	}
+	err = d.Register(d.jwtAuth)
+	if err != nil {
+		return nil, fmt.Errorf("registering jwt-auth with defaulter: %w", err)
+	}
+	err = d.Register(d.oauth2Cred)
+	if err != nil {
+		return nil, fmt.Errorf("registering oauth2-cred with defaulter: %w", err)
+	}
 	return d, nil
 }
 
@@ -234,9 +247,39 @@ func (d *Defaulter) addEntityDefaults(entityType string, entity interface{}) err
 	if err != nil {
 		return fmt.Errorf("retrieve schema for %v from Kong: %v", entityType, err)
 	}
+	switch entity.(type) {
+	case *kong.JWTAuth, *kong.Oauth2Credential:
+		return fillCredentialDefaults(entity, schema)
+	}
 	return kong.FillEntityDefaults(entity, schema)
 }
 
+// fillCredentialDefaults fills in the fields of a credential, which are
+// not supported by kong.FillEntityDefaults, with the defaults of its schema.
+// Credentials have no nested records, only top-level fields are considered.
+func fillCredentialDefaults(entity interface{}, schema map[string]interface{}) error {
+	defaults := map[string]interface{}{}
+	fields, _ := schema["fields"].([]interface{})
+	for _, f := range fields {
+		field, _ := f.(map[string]interface{})
+		for name, v := range field {
+			def, _ := v.(map[string]interface{})
+			if value, ok := def["default"]; ok && value != nil && def["type"] != "foreign" {
+				defaults[name] = value
+			}
+		}
+	}
+	b, err := json.Marshal(defaults)
+	if err != nil {
+		return err
+	}
+	tmpEntity := reflect.New(reflect.Indirect(reflect.ValueOf(entity)).Type()).Interface()
+	if err := json.Unmarshal(b, tmpEntity); err != nil {
+		return fmt.Errorf("unmarshal entity with defaults: %w", err)
+	}
+	return mergo.Merge(entity, tmpEntity)
+}
+
 func getKongDefaulterWithClient(ctx context.Context, opts DefaulterOpts) (*Defaulter, error) {
 	// fills defaults from input
 	d, err := getKongDefaulter(opts)
@@ -287,6 +330,9 @@ func getKongDefaulterWithClient(ctx context.Context, opts DefaulterOpts) (*Defau
 		); err != nil {
 			return nil, fmt.Errorf("merging consumer-group-plugin static defaults: %w", err)
 		}
+		if err := d.populateStaticCredentialDefaults(); err != nil {
+			return nil, err
+		}
 	} else {
 		if err := d.addEntityDefaults("consumer_group_plugins", d.consumerGroupPlugin); err != nil {
 			return nil, fmt.Errorf("get defaults for consumer-group-plugin: %v", err)
@@ -294,6 +340,14 @@ func getKongDefaulterWithClient(ctx context.Context, opts DefaulterOpts) (*Defau
 		if err := d.Register(d.consumerGroupPlugin); err != nil {
 			return nil, fmt.Errorf("registering consumer-group-plugin with defaulter: %w", err)
 		}
+		// credentials of plugins without a schema endpoint, in older
+		// versions of Kong, have no defaults
+		if err := d.addEntityDefaults("jwt_secrets", d.jwtAuth); err != nil {
+			return nil, fmt.Errorf("get defaults for jwt-auth: %v", err)
+		}
+		if err := d.addEntityDefaults("oauth2_credentials", d.oauth2Cred); err != nil {
+			return nil, fmt.Errorf("get defaults for oauth2-cred: %v", err)
+		}
 	}
 	return d, nil
 }
@@ -405,6 +459,15 @@ func (d *Defaulter) populateStaticDefaultsForKonnect() error {
 	); err != nil {
 		return fmt.Errorf("merging target static defaults: %w", err)
 	}
+	return d.populateStaticCredentialDefaults()
+}
 
+func (d *Defaulter) populateStaticCredentialDefaults() error {
+	if err := mergo.Merge(d.jwtAuth, &jwtAuthDefaults); err != nil {
+		return fmt.Errorf("merging jwt-auth static defaults: %w", err)
+	}
+	if err := mergo.Merge(d.oauth2Cred, &oauth2CredDefaults); err != nil {
+		return fmt.Errorf("merging oauth2-cred static defaults: %w", err)
+	}
 	return nil
 }
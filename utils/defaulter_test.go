@@ -7,6 +7,7 @@ import (
 
 	"github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 type kongDefaultForTesting struct {
@@ -735,3 +736,41 @@ func TestKongDefaultsRestrictedFields(t *testing.T) {
 		})
 	}
 }
+
+func TestCredentialDefaults(t *testing.T) {
+	ctx := context.Background()
+	d, err := GetDefaulter(ctx, DefaulterOpts{
+		KongDefaults:           &kongDefaultForTesting{},
+		DisableDynamicDefaults: true,
+	})
+	require.NoError(t, err)
+
+	jwt := &kong.JWTAuth{Key: kong.String("key")}
+	require.NoError(t, d.Set(jwt))
+	assert.Equal(t, &kong.JWTAuth{Key: kong.String("key"), Algorithm: kong.String("HS256")}, jwt)
+
+	oauth2 := &kong.Oauth2Credential{ClientType: kong.String("public")}
+	require.NoError(t, d.Set(oauth2))
+	assert.Equal(t, &kong.Oauth2Credential{
+		ClientType: kong.String("public"),
+		HashSecret: kong.Bool(false),
+	}, oauth2)
+}
+
+func TestFillCredentialDefaults(t *testing.T) {
+	schema := map[string]interface{}{
+		"fields": []interface{}{
+			map[string]interface{}{"id": map[string]interface{}{"type": "string", "auto": true}},
+			map[string]interface{}{"consumer": map[string]interface{}{"type": "foreign", "default": nil}},
+			map[string]interface{}{"algorithm": map[string]interface{}{"type": "string", "default": "RS256"}},
+			map[string]interface{}{"tags": map[string]interface{}{"type": "set"}},
+		},
+	}
+	jwt := &kong.JWTAuth{}
+	require.NoError(t, fillCredentialDefaults(jwt, schema))
+	assert.Equal(t, &kong.JWTAuth{Algorithm: kong.String("RS256")}, jwt)
+
+	jwt = &kong.JWTAuth{Algorithm: kong.String("ES256")}
+	require.NoError(t, fillCredentialDefaults(jwt, schema))
+	assert.Equal(t, "ES256", *jwt.Algorithm)
+}
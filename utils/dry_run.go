@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// Mutation is a request which would have modified
+// the configuration of Kong.
+type Mutation struct {
+	Method string
+	// Path is the path of the request, including the workspace if any.
+	Path string
+	// Query is the encoded query string of the request.
+	Query string
+	Body  []byte
+}
+
+// MutationRecorder captures the mutations sent through a client in
+// dry-run mode. It is safe for concurrent use.
+type MutationRecorder struct {
+	mu        sync.Mutex
+	mutations []Mutation
+}
+
+// Mutations returns the mutations recorded so far, in the order
+// in which they were sent.
+func (r *MutationRecorder) Mutations() []Mutation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	res := make([]Mutation, len(r.mutations))
+	copy(res, r.mutations)
+	return res
+}
+
+// Reset discards all recorded mutations.
+func (r *MutationRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mutations = nil
+}
+
+func (r *MutationRecorder) record(m Mutation) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.mutations = append(r.mutations, m)
+}
+
+// dryRunTransport records mutating requests instead of sending them.
+// Read-only requests are sent as usual.
+type dryRunTransport struct {
+	recorder *MutationRecorder
+	next     http.RoundTripper
+}
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *dryRunTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if isReadOnlyMethod(req.Method) {
+		return t.next.RoundTrip(req)
+	}
+	body, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	t.recorder.record(Mutation{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.RawQuery,
+		Body:   body,
+	})
+
+	// Echo the body back so that callers decoding the entity
+	// from the response keep working.
+	status := http.StatusOK
+	switch req.Method {
+	case http.MethodPost:
+		status = http.StatusCreated
+	case http.MethodDelete:
+		status = http.StatusNoContent
+		body = nil
+	}
+	header := http.Header{}
+	if len(body) > 0 {
+		header.Set("Content-Type", "application/json")
+	}
+	return &http.Response{
+		Status:        http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// withDryRun wraps transport so that mutating requests are
+// captured by recorder instead of being sent.
+func withDryRun(transport http.RoundTripper, recorder *MutationRecorder) http.RoundTripper {
+	if recorder == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &dryRunTransport{
+		recorder: recorder,
+		next:     transport,
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKongClientDryRun(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		methods = append(methods, r.Method)
+		_, _ = w.Write([]byte(`{"id":"s1","name":"svc1","host":"example.com"}`))
+	}))
+	defer server.Close()
+
+	recorder := &MutationRecorder{}
+	client, err := GetKongClient(KongClientConfig{
+		Address:        server.URL,
+		Workspace:      "ws1",
+		DryRunRecorder: recorder,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	svc, err := client.Services.Get(ctx, kong.String("svc1"))
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", *svc.Host)
+
+	created, err := client.Services.Create(ctx, &kong.Service{
+		Name: kong.String("svc2"),
+		Host: kong.String("example.org"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "svc2", *created.Name)
+
+	require.NoError(t, client.Services.Delete(ctx, kong.String("svc1")))
+
+	assert.Equal(t, []string{http.MethodGet}, methods)
+	mutations := recorder.Mutations()
+	require.Len(t, mutations, 2)
+	assert.Equal(t, http.MethodPost, mutations[0].Method)
+	assert.Equal(t, "/ws1/services", mutations[0].Path)
+	assert.JSONEq(t, `{"name":"svc2","host":"example.org"}`, string(mutations[0].Body))
+	assert.Equal(t, http.MethodDelete, mutations[1].Method)
+	assert.Equal(t, "/ws1/services/svc1", mutations[1].Path)
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Mutations())
+}
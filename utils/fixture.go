@@ -0,0 +1,254 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// FixtureMode selects how a Fixture handles requests.
+type FixtureMode int
+
+const (
+	// FixtureModeRecord sends requests to Kong and records the
+	// interactions, to be written by Save.
+	FixtureModeRecord FixtureMode = iota + 1
+	// FixtureModeReplay answers requests with the recorded interactions,
+	// without sending them to Kong.
+	FixtureModeReplay
+	// FixtureModeAuto replays the fixture file if it exists,
+	// and records it otherwise.
+	FixtureModeAuto
+)
+
+// RecordedRequest is a request of an Interaction.
+type RecordedRequest struct {
+	Method string `json:"method"`
+	// URL is the path and the encoded query string of the request,
+	// without the address of Kong, which may change across runs.
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response of an Interaction.
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Interaction is a request sent to Kong and its response.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// Fixture records the interactions of a client with the Admin API to a
+// file, and replays them later, so that tests run without Kong.
+// Sensitive headers and fields of bodies, like credentials and keys,
+// are redacted before being recorded, as in debug output.
+//
+// Requests are replayed by matching their method, path, query and
+// redacted body with the recorded interactions not replayed yet, in
+// order: repeated requests get the successive recorded responses.
+// Entities created by a sync get random IDs, which are part of its
+// requests; use SetUUIDSeed with the same seed when recording and
+// replaying to get the same IDs.
+// It is safe for concurrent use.
+type Fixture struct {
+	// Scrub, if set, is called on each interaction before it is
+	// recorded, after the default redaction, to remove other data
+	// specific to the environment. When replaying, it is called on
+	// the request of each interaction before it is matched.
+	Scrub func(*Interaction)
+
+	mu           sync.Mutex
+	filename     string
+	mode         FixtureMode
+	interactions []Interaction
+	replayed     []bool
+}
+
+// NewFixture returns a Fixture recording to or replaying from filename,
+// depending on mode.
+func NewFixture(filename string, mode FixtureMode) (*Fixture, error) {
+	f := &Fixture{filename: filename, mode: mode}
+	switch mode {
+	case FixtureModeRecord:
+		return f, nil
+	case FixtureModeReplay, FixtureModeAuto:
+	default:
+		return nil, fmt.Errorf("invalid fixture mode %d", mode)
+	}
+	b, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) && mode == FixtureModeAuto {
+		f.mode = FixtureModeRecord
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading fixture: %w", err)
+	}
+	if err := json.Unmarshal(b, &f.interactions); err != nil {
+		return nil, fmt.Errorf("parsing fixture %s: %w", filename, err)
+	}
+	f.mode = FixtureModeReplay
+	f.replayed = make([]bool, len(f.interactions))
+	return f, nil
+}
+
+// Recording returns whether requests are sent to Kong and recorded,
+// as opposed to being replayed.
+func (f *Fixture) Recording() bool {
+	return f.mode == FixtureModeRecord
+}
+
+// Interactions returns the interactions recorded or loaded so far.
+func (f *Fixture) Interactions() []Interaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	res := make([]Interaction, len(f.interactions))
+	copy(res, f.interactions)
+	return res
+}
+
+// Save writes the recorded interactions to the file of the fixture.
+// It does nothing when replaying.
+func (f *Fixture) Save() error {
+	if !f.Recording() {
+		return nil
+	}
+	f.mu.Lock()
+	b, err := json.MarshalIndent(f.interactions, "", "  ")
+	f.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(f.filename, append(b, '\n'), 0o600); err != nil {
+		return fmt.Errorf("writing fixture: %w", err)
+	}
+	return nil
+}
+
+func (f *Fixture) record(i Interaction) {
+	if f.Scrub != nil {
+		f.Scrub(&i)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.interactions = append(f.interactions, i)
+}
+
+// scrubRequest returns req scrubbed like recorded requests.
+func (f *Fixture) scrubRequest(req RecordedRequest) RecordedRequest {
+	if f.Scrub == nil {
+		return req
+	}
+	i := Interaction{Request: req}
+	f.Scrub(&i)
+	return i.Request
+}
+
+// replay returns the response of the first interaction matching req
+// which was not replayed yet.
+func (f *Fixture) replay(req RecordedRequest) (RecordedResponse, bool) {
+	req = f.scrubRequest(req)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, interaction := range f.interactions {
+		recorded := interaction.Request
+		if f.replayed[i] || recorded.Method != req.Method || recorded.URL != req.URL ||
+			recorded.Body != req.Body {
+			continue
+		}
+		f.replayed[i] = true
+		return interaction.Response, true
+	}
+	return RecordedResponse{}, false
+}
+
+type fixtureTransport struct {
+	transport http.RoundTripper
+	fixture   *Fixture
+}
+
+func (t *fixtureTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recordedReq := RecordedRequest{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Body:   string(RedactBody(req.Header.Get("Content-Type"), body)),
+	}
+
+	if !t.fixture.Recording() {
+		recorded, ok := t.fixture.replay(recordedReq)
+		if !ok {
+			return nil, fmt.Errorf("no recorded interaction for %s %s",
+				recordedReq.Method, recordedReq.URL)
+		}
+		header := recorded.Header.Clone()
+		if header == nil {
+			header = http.Header{}
+		}
+		header.Set("Content-Length", strconv.Itoa(len(recorded.Body)))
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+			StatusCode:    recorded.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader([]byte(recorded.Body))),
+			ContentLength: int64(len(recorded.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	recordedReq.Header = RedactHeaders(req.Header)
+	header := RedactHeaders(resp.Header)
+	header.Del("Content-Length")
+	t.fixture.record(Interaction{
+		Request: recordedReq,
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       string(RedactBody(resp.Header.Get("Content-Type"), respBody)),
+		},
+	})
+	return resp, nil
+}
+
+// withFixture records the requests sent through transport to fixture,
+// or replays them from it. It returns transport as is if fixture is nil.
+func withFixture(transport http.RoundTripper, fixture *Fixture) http.RoundTripper {
+	if fixture == nil {
+		return transport
+	}
+	return &fixtureTransport{transport: transport, fixture: fixture}
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureRecordReplay(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet:
+			_, _ = w.Write([]byte(`{"id":"s1","name":"svc1","host":"example.com"}`))
+		default:
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id":"k1","key":"my-secret-key","consumer":{"id":"c1"}}`))
+		}
+	}))
+	filename := filepath.Join(t.TempDir(), "fixture.json")
+	ctx := context.Background()
+	run := func(fixture *Fixture) error {
+		client, err := GetKongClient(KongClientConfig{
+			Address: server.URL,
+			Headers: []string{"Kong-Admin-Token:my-token"},
+			Fixture: fixture,
+		})
+		require.NoError(t, err)
+		svc, err := client.Services.Get(ctx, kong.String("svc1"))
+		if err != nil {
+			return err
+		}
+		assert.Equal(t, "example.com", *svc.Host)
+		_, err = client.KeyAuths.Create(ctx, kong.String("c1"), &kong.KeyAuth{
+			Key: kong.String("my-secret-key"),
+		})
+		return err
+	}
+
+	fixture, err := NewFixture(filename, FixtureModeAuto)
+	require.NoError(t, err)
+	require.True(t, fixture.Recording())
+	require.NoError(t, run(fixture))
+	require.NoError(t, fixture.Save())
+	assert.Equal(t, 2, requests)
+
+	b, err := os.ReadFile(filename)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "my-secret-key")
+	assert.NotContains(t, string(b), "my-token")
+
+	// replayed without Kong
+	server.Close()
+	fixture, err = NewFixture(filename, FixtureModeAuto)
+	require.NoError(t, err)
+	require.False(t, fixture.Recording())
+	require.NoError(t, run(fixture))
+	assert.Equal(t, 2, requests)
+
+	// all the interactions were replayed
+	err = run(fixture)
+	require.ErrorContains(t, err, "no recorded interaction for GET /services/svc1")
+}
+
+func TestFixtureReplayScrubbedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"name":"svc1","host":"example.com"}`))
+	}))
+	filename := filepath.Join(t.TempDir(), "fixture.json")
+	ctx := context.Background()
+	defer SetUUIDSeed(0)
+	// run creates a service with a new ID, tagged with the run
+	run := func(fixture *Fixture, runTag string) error {
+		fixture.Scrub = func(i *Interaction) {
+			i.Request.Body = strings.ReplaceAll(i.Request.Body, runTag, "run")
+		}
+		client, err := GetKongClient(KongClientConfig{
+			Address: server.URL,
+			Fixture: fixture,
+		})
+		require.NoError(t, err)
+		SetUUIDSeed(1)
+		_, err = client.Services.Create(ctx, &kong.Service{
+			ID:   kong.String(UUID()),
+			Name: kong.String("svc1"),
+			Host: kong.String("example.com"),
+			Tags: kong.StringSlice(runTag),
+		})
+		return err
+	}
+
+	fixture, err := NewFixture(filename, FixtureModeRecord)
+	require.NoError(t, err)
+	require.NoError(t, run(fixture, "run-1"))
+	require.NoError(t, fixture.Save())
+	server.Close()
+
+	fixture, err = NewFixture(filename, FixtureModeReplay)
+	require.NoError(t, err)
+	require.NoError(t, run(fixture, "run-2"))
+}
+
+func TestNewFixtureErrors(t *testing.T) {
+	_, err := NewFixture(filepath.Join(t.TempDir(), "missing.json"), FixtureModeReplay)
+	require.ErrorContains(t, err, "reading fixture")
+	_, err = NewFixture("fixture.json", FixtureMode(0))
+	require.ErrorContains(t, err, "invalid fixture mode")
+}
@@ -0,0 +1,25 @@
+// Package httpbody holds helpers for the bodies of HTTP requests.
+// It is separate from utils, which depends on the konnect package,
+// so that the admin and konnect clients can share it.
+package httpbody
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// Rewind returns a copy of req with a fresh body, so that a request
+// can be sent again, e.g. after re-authenticating.
+// Requests without a body, or without GetBody, are returned as is.
+func Rewind(req *http.Request) (*http.Request, error) {
+	if req.GetBody == nil || req.Body == nil || req.Body == http.NoBody {
+		return req, nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, fmt.Errorf("rewinding request body: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Body = body
+	return req, nil
+}
@@ -0,0 +1,42 @@
+package httpbody
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRewind(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://localhost/services",
+		strings.NewReader(`{"name":"svc1"}`))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		rewound, err := Rewind(req)
+		require.NoError(t, err)
+		assert.NotSame(t, req, rewound)
+		body, err := io.ReadAll(rewound.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"name":"svc1"}`, string(body))
+	}
+
+	req, err = http.NewRequest(http.MethodGet, "http://localhost/services", nil)
+	require.NoError(t, err)
+	rewound, err := Rewind(req)
+	require.NoError(t, err)
+	assert.Same(t, req, rewound)
+
+	req, err = http.NewRequest(http.MethodPost, "http://localhost/services",
+		strings.NewReader(`{}`))
+	require.NoError(t, err)
+	req.GetBody = func() (io.ReadCloser, error) {
+		return nil, errors.New("body consumed")
+	}
+	_, err = Rewind(req)
+	assert.EqualError(t, err, "rewinding request body: body consumed")
+}
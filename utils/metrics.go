@@ -0,0 +1,87 @@
+package utils
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestMetrics describes a single request sent to the Admin API.
+type RequestMetrics struct {
+	// Method is the HTTP verb of the request.
+	Method string
+	// Path is the low cardinality template of the request path,
+	// e.g. `/services/{id}/routes`.
+	Path string
+	// EntityType is the type of the entity targeted by the request,
+	// e.g. `routes`. It is empty for non-entity endpoints.
+	EntityType string
+	// StatusCode is the HTTP status code of the response,
+	// or 0 if no response was received.
+	StatusCode int
+	// Duration is the time spent waiting for the response headers.
+	Duration time.Duration
+	// Err is the transport error, if any.
+	Err error
+}
+
+// Failed returns true if the request failed, either because no
+// response was received or because Kong returned a server error.
+func (m RequestMetrics) Failed() bool {
+	return m.Err != nil || m.StatusCode >= http.StatusInternalServerError
+}
+
+// MetricsCollector is notified of every request sent to the Admin API.
+// It can be used to record request counts, latencies and error rates
+// per entity type and verb, for example as Prometheus metrics.
+// Implementations must be safe for concurrent use.
+type MetricsCollector interface {
+	ObserveRequest(RequestMetrics)
+}
+
+// MetricsCollectorFunc is an adapter to use an ordinary function
+// as a MetricsCollector.
+type MetricsCollectorFunc func(RequestMetrics)
+
+// ObserveRequest calls f(m).
+func (f MetricsCollectorFunc) ObserveRequest(m RequestMetrics) {
+	f(m)
+}
+
+// metricsTransport reports every request to a MetricsCollector.
+type metricsTransport struct {
+	collector MetricsCollector
+	next      http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	template, entityType := pathTemplate(req.URL.Path)
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	m := RequestMetrics{
+		Method:     req.Method,
+		Path:       template,
+		EntityType: entityType,
+		Duration:   time.Since(start),
+		Err:        err,
+	}
+	if resp != nil {
+		m.StatusCode = resp.StatusCode
+	}
+	t.collector.ObserveRequest(m)
+	return resp, err
+}
+
+// withMetrics wraps transport so that every request
+// is reported to collector.
+func withMetrics(transport http.RoundTripper, collector MetricsCollector) http.RoundTripper {
+	if collector == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &metricsTransport{
+		collector: collector,
+		next:      transport,
+	}
+}
@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKongClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`{"message":"An unexpected error occurred"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"id":"foo","name":"foo"}`))
+	}))
+	defer server.Close()
+
+	var (
+		mu       sync.Mutex
+		observed []RequestMetrics
+	)
+	client, err := GetKongClient(KongClientConfig{
+		Address: server.URL,
+		MetricsCollector: MetricsCollectorFunc(func(m RequestMetrics) {
+			mu.Lock()
+			defer mu.Unlock()
+			observed = append(observed, m)
+		}),
+	})
+	require.NoError(t, err)
+
+	_, err = client.Routes.Get(context.Background(), kong.String("foo"))
+	require.NoError(t, err)
+	err = client.Routes.Delete(context.Background(), kong.String("foo"))
+	require.Error(t, err)
+
+	require.Len(t, observed, 2)
+	assert.Equal(t, http.MethodGet, observed[0].Method)
+	assert.Equal(t, "/routes/{id}", observed[0].Path)
+	assert.Equal(t, "routes", observed[0].EntityType)
+	assert.Equal(t, http.StatusOK, observed[0].StatusCode)
+	assert.False(t, observed[0].Failed())
+
+	assert.Equal(t, http.MethodDelete, observed[1].Method)
+	assert.Equal(t, http.StatusInternalServerError, observed[1].StatusCode)
+	assert.True(t, observed[1].Failed())
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"net/http"
+	"sync"
+)
+
+// RoundTripperFunc is an adapter to use an ordinary function
+// as an http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(req).
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware intercepts the requests sent to Kong. It wraps next and can
+// inspect or modify the request before calling it, and the response
+// after, e.g. to sign requests or inject audit headers.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// MiddlewareChain holds the middlewares installed on a client.
+// Middlewares can be registered at any time, including while requests
+// are in flight; they apply to requests sent after registration.
+// The zero value is an empty chain ready to use.
+type MiddlewareChain struct {
+	mu          sync.RWMutex
+	middlewares []Middleware
+}
+
+// Register appends middlewares to the chain.
+// Middlewares registered first see requests first and responses last.
+func (c *MiddlewareChain) Register(middlewares ...Middleware) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+func (c *MiddlewareChain) wrap(transport http.RoundTripper) http.RoundTripper {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		transport = c.middlewares[i](transport)
+	}
+	return transport
+}
+
+// middlewareTransport sends requests through the middlewares
+// registered in a chain at the time of the request.
+type middlewareTransport struct {
+	chain *MiddlewareChain
+	next  http.RoundTripper
+}
+
+func (t *middlewareTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.chain.wrap(t.next).RoundTrip(req)
+}
+
+// withMiddlewares wraps transport so that every request goes
+// through the middlewares of chain.
+func withMiddlewares(transport http.RoundTripper, chain *MiddlewareChain) http.RoundTripper {
+	if chain == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &middlewareTransport{
+		chain: chain,
+		next:  transport,
+	}
+}
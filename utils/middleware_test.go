@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKongClientMiddlewares(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"id":"s1"}`))
+	}))
+	defer server.Close()
+
+	chain := &MiddlewareChain{}
+	client, err := GetKongClient(KongClientConfig{
+		Address:     server.URL,
+		Headers:     []string{"Kong-Admin-Token:s3cr3t"},
+		Middlewares: chain,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	var order []string
+	chain.Register(
+		func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, "first")
+				// headers set on the client are visible to middlewares
+				req.Header.Set("X-Signature", "signed:"+req.Header.Get("Kong-Admin-Token"))
+				resp, err := next.RoundTrip(req)
+				order = append(order, "first-response")
+				return resp, err
+			})
+		},
+		func(next http.RoundTripper) http.RoundTripper {
+			return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+				order = append(order, "second")
+				resp, err := next.RoundTrip(req)
+				if resp != nil {
+					resp.Header.Set("X-Audited", "true")
+				}
+				order = append(order, "second-response")
+				return resp, err
+			})
+		},
+	)
+
+	_, err = client.Services.Get(ctx, kong.String("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, "signed:s3cr3t", received.Get("X-Signature"))
+	assert.Equal(t, []string{"first", "second", "second-response", "first-response"}, order)
+
+	// middlewares registered later apply to subsequent requests
+	chain.Register(func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Audit-User", "alice")
+			return next.RoundTrip(req)
+		})
+	})
+	_, err = client.Services.Get(ctx, kong.String("s1"))
+	require.NoError(t, err)
+	assert.Equal(t, "alice", received.Get("X-Audit-User"))
+}
@@ -0,0 +1,74 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRateLimiter(t *testing.T) {
+	assert.Nil(t, NewRateLimiter(0, 10))
+	assert.Nil(t, NewRateLimiter(-1, 10))
+
+	var l *RateLimiter
+	assert.Nil(t, l.Wait(context.Background()))
+}
+
+func TestRateLimiterWait(t *testing.T) {
+	l := NewRateLimiter(100, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Nil(t, l.Wait(ctx))
+		}()
+	}
+	wg.Wait()
+	// 2 requests are allowed by the burst, 4 more need 40ms at 100 qps
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+}
+
+func TestRateLimiterWaitContextCanceled(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	assert.Nil(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, l.Wait(ctx), context.DeadlineExceeded)
+}
+
+func TestGetKongClientRateLimit(t *testing.T) {
+	var count int
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		count++
+		mu.Unlock()
+		_, _ = w.Write([]byte(`{"version":"3.2.0"}`))
+	}))
+	defer server.Close()
+
+	client, err := GetKongClient(KongClientConfig{
+		Address:     server.URL,
+		RateLimiter: NewRateLimiter(50, 1),
+	})
+	require.NoError(t, err)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		_, err := client.Root(context.Background())
+		require.NoError(t, err)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	assert.Equal(t, 3, count)
+}
@@ -0,0 +1,177 @@
+package utils
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// ResponseCache stores the responses to GET requests carrying an ETag
+// header, and revalidates them with If-None-Match on the next request
+// to the same URL. When Kong answers with 304 Not Modified, the
+// cached response is returned instead.
+// It holds at most a fixed number of responses, evicting the least
+// recently used ones first. It is safe for concurrent use.
+type ResponseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	entries    map[string]*list.Element
+	lru        *list.List
+}
+
+type cachedResponse struct {
+	key        string
+	path       string
+	etag       string
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+// NewResponseCache returns a ResponseCache holding at most maxEntries
+// responses. It returns nil if maxEntries is not positive,
+// which disables caching.
+func NewResponseCache(maxEntries int) *ResponseCache {
+	if maxEntries <= 0 {
+		return nil
+	}
+	return &ResponseCache{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+// Len returns the number of cached responses.
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+func (c *ResponseCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cachedResponse)
+}
+
+func (c *ResponseCache) add(entry *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[entry.key]; ok {
+		e.Value = entry
+		c.lru.MoveToFront(e)
+		return
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+	for c.lru.Len() > c.maxEntries {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedResponse).key)
+	}
+}
+
+func (c *ResponseCache) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[key]; ok {
+		c.lru.Remove(e)
+		delete(c.entries, key)
+	}
+}
+
+// responseCacheKey identifies a request by its URL and credentials,
+// since RBAC may give different users different views of the same URL.
+func responseCacheKey(req *http.Request) string {
+	h := sha256.New()
+	for _, name := range sensitiveHeaders {
+		for _, value := range req.Header.Values(name) {
+			_, _ = io.WriteString(h, name+":"+value+"\n")
+		}
+	}
+	return req.URL.String() + "#" + hex.EncodeToString(h.Sum(nil))
+}
+
+// cachingTransport revalidates GET requests with the ETag of
+// previously cached responses.
+type cachingTransport struct {
+	cache *ResponseCache
+	next  http.RoundTripper
+}
+
+func (t *cachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.next.RoundTrip(req)
+	}
+	key := responseCacheKey(req)
+	cached := t.cache.get(key)
+	if cached != nil {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached != nil:
+		resp.Body.Close()
+		return cached.response(req), nil
+	case resp.StatusCode == http.StatusOK && resp.Header.Get("ETag") != "":
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		t.cache.add(&cachedResponse{
+			key:        key,
+			etag:       resp.Header.Get("ETag"),
+			statusCode: resp.StatusCode,
+			header:     resp.Header.Clone(),
+			body:       body,
+		})
+	case cached != nil:
+		t.cache.remove(key)
+	}
+	return resp, nil
+}
+
+func (r *cachedResponse) response(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(r.statusCode),
+		StatusCode:    r.statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// withResponseCache wraps transport so that GET requests are
+// revalidated against the responses stored in cache.
+func withResponseCache(transport http.RoundTripper, cache *ResponseCache) http.RoundTripper {
+	if cache == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &cachingTransport{
+		cache: cache,
+		next:  transport,
+	}
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewResponseCache(t *testing.T) {
+	assert.Nil(t, NewResponseCache(0))
+	assert.NotNil(t, NewResponseCache(1))
+}
+
+func TestGetKongClientResponseCache(t *testing.T) {
+	var ifNoneMatch []string
+	etag := `"v1"`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ifNoneMatch = append(ifNoneMatch, r.Header.Get("If-None-Match"))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(`{"id":"s1","name":"` + r.URL.Path[len("/services/"):] + `"}`))
+	}))
+	defer server.Close()
+
+	cache := NewResponseCache(1)
+	client, err := GetKongClient(KongClientConfig{
+		Address:       server.URL,
+		ResponseCache: cache,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		svc, err := client.Services.Get(ctx, kong.String("foo"))
+		require.NoError(t, err)
+		assert.Equal(t, "foo", *svc.Name)
+	}
+	assert.Equal(t, []string{"", `"v1"`}, ifNoneMatch)
+	assert.Equal(t, 1, cache.Len())
+
+	// a different URL evicts the least recently used entry
+	svc, err := client.Services.Get(ctx, kong.String("bar"))
+	require.NoError(t, err)
+	assert.Equal(t, "bar", *svc.Name)
+	assert.Equal(t, 1, cache.Len())
+
+	// a new version of the entity replaces the cached one
+	etag = `"v2"`
+	_, err = client.Services.Get(ctx, kong.String("bar"))
+	require.NoError(t, err)
+	_, err = client.Services.Get(ctx, kong.String("bar"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"", `"v1"`, "", `"v1"`, `"v2"`}, ifNoneMatch)
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SchemaCache stores the responses of the schema endpoints of Kong and
+// Konnect, so that schema-driven features like defaults filling don't
+// fetch the same schemas on every run of a long-lived process.
+// Entries are keyed by the host and the version of Kong it runs, as
+// reported in the Server header of any of its responses, e.g. of the
+// root endpoint fetched at the start of each run: an upgrade can change
+// schemas, and clients of different versions of Kong can share a cache.
+// Entries expire after a TTL, and can also be invalidated explicitly,
+// e.g. after installing a new version of a custom plugin.
+// It is safe for concurrent use.
+type SchemaCache struct {
+	mu  sync.Mutex
+	ttl time.Duration
+	// versions holds the last version of Kong seen for each host.
+	versions map[string]string
+	entries  map[string]*schemaCacheEntry
+
+	now func() time.Time
+}
+
+type schemaCacheEntry struct {
+	response *cachedResponse
+	expires  time.Time
+}
+
+// NewSchemaCache returns a SchemaCache whose entries expire after ttl.
+// Entries never expire if ttl is not positive.
+func NewSchemaCache(ttl time.Duration) *SchemaCache {
+	return &SchemaCache{
+		ttl:      ttl,
+		versions: map[string]string{},
+		entries:  map[string]*schemaCacheEntry{},
+		now:      time.Now,
+	}
+}
+
+// Len returns the number of cached schemas.
+func (c *SchemaCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Invalidate drops all cached schemas.
+func (c *SchemaCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*schemaCacheEntry{}
+}
+
+// InvalidatePath drops the cached schemas whose URL path ends with path,
+// e.g. "/schemas/plugins/my-plugin".
+func (c *SchemaCache) InvalidatePath(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if strings.HasSuffix(e.response.path, path) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *SchemaCache) get(key string) *cachedResponse {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if c.ttl > 0 && !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return nil
+	}
+	return e.response
+}
+
+func (c *SchemaCache) add(response *cachedResponse) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[response.key] = &schemaCacheEntry{
+		response: response,
+		expires:  c.now().Add(c.ttl),
+	}
+}
+
+// key returns the key of the cached response to req, for the version
+// of Kong last seen on its host.
+func (c *SchemaCache) key(req *http.Request) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.versions[req.URL.Host] + " " + req.URL.String()
+}
+
+// observeVersion records the Kong version of resp as the one of host.
+func (c *SchemaCache) observeVersion(host string, resp *http.Response) {
+	server := resp.Header.Get("Server")
+	if !strings.HasPrefix(server, "kong/") {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.versions[host] = server
+}
+
+// isSchemaRequest reports whether req fetches a schema.
+func isSchemaRequest(req *http.Request) bool {
+	if req.Method != http.MethodGet {
+		return false
+	}
+	path := req.URL.Path
+	return strings.Contains(path, "/schemas/") && !strings.HasSuffix(path, "/validate")
+}
+
+// schemaCachingTransport answers schema requests from a SchemaCache.
+type schemaCachingTransport struct {
+	cache *SchemaCache
+	next  http.RoundTripper
+}
+
+func (t *schemaCachingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	isSchema := isSchemaRequest(req)
+	if isSchema {
+		if cached := t.cache.get(t.cache.key(req)); cached != nil {
+			return cached.response(req), nil
+		}
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.cache.observeVersion(req.URL.Host, resp)
+	if !isSchema || resp.StatusCode != http.StatusOK {
+		return resp, nil
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	t.cache.add(&cachedResponse{
+		key:        t.cache.key(req),
+		path:       req.URL.Path,
+		statusCode: resp.StatusCode,
+		header:     resp.Header.Clone(),
+		body:       body,
+	})
+	return resp, nil
+}
+
+// withSchemaCache wraps transport so that schemas are fetched
+// from cache when possible.
+func withSchemaCache(transport http.RoundTripper, cache *SchemaCache) http.RoundTripper {
+	if cache == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &schemaCachingTransport{cache: cache, next: transport}
+}
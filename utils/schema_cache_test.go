@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKongClientSchemaCache(t *testing.T) {
+	var requests int32
+	var version atomic.Value
+	version.Store("kong/3.4.0")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Server", version.Load().(string))
+		_, _ = w.Write([]byte(`{"fields":[]}`))
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	cache := NewSchemaCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	client, err := GetKongClient(KongClientConfig{
+		Address:     server.URL,
+		SchemaCache: cache,
+	})
+	require.NoError(t, err)
+	ctx := context.Background()
+	get := func(entity string) {
+		_, err := client.Schemas.Get(ctx, entity)
+		require.NoError(t, err)
+	}
+
+	base := atomic.LoadInt32(&requests)
+	get("services")
+	get("services")
+	get("plugins/key-auth")
+	assert.Equal(t, base+2, atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, cache.Len())
+
+	// other endpoints are not cached
+	_, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	_, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, base+4, atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, cache.Len())
+
+	cache.InvalidatePath("/schemas/plugins/key-auth")
+	assert.Equal(t, 1, cache.Len())
+	get("plugins/key-auth")
+	assert.Equal(t, base+5, atomic.LoadInt32(&requests))
+
+	// entries expire
+	now = now.Add(time.Minute)
+	get("services")
+	assert.Equal(t, base+6, atomic.LoadInt32(&requests))
+
+	// schemas of an upgraded Kong are fetched again once its new version
+	// is seen in any response
+	version.Store("kong/3.5.0")
+	get("services")
+	assert.Equal(t, base+6, atomic.LoadInt32(&requests))
+	_, err = client.Services.ListAll(ctx)
+	require.NoError(t, err)
+	get("services")
+	assert.Equal(t, base+8, atomic.LoadInt32(&requests))
+	assert.Equal(t, 3, cache.Len())
+
+	cache.Invalidate()
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestSchemaCacheSharedAcrossVersions(t *testing.T) {
+	var requests int32
+	newServer := func(version string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			w.Header().Set("Server", version)
+			_, _ = w.Write([]byte(`{"fields":[]}`))
+		}))
+	}
+	old := newServer("kong/2.8.0")
+	defer old.Close()
+	current := newServer("kong/3.4.0")
+	defer current.Close()
+
+	cache := NewSchemaCache(0)
+	ctx := context.Background()
+	var getters []func()
+	for _, server := range []*httptest.Server{old, current} {
+		client, err := GetKongClient(KongClientConfig{
+			Address:     server.URL,
+			SchemaCache: cache,
+		})
+		require.NoError(t, err)
+		getters = append(getters, func() {
+			_, err := client.Schemas.Get(ctx, "services")
+			require.NoError(t, err)
+		})
+	}
+
+	base := atomic.LoadInt32(&requests)
+	for i := 0; i < 2; i++ {
+		for _, get := range getters {
+			get()
+		}
+	}
+	assert.Equal(t, base+2, atomic.LoadInt32(&requests))
+	assert.Equal(t, 2, cache.Len())
+}
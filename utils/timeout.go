@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+type requestTimeoutKey struct{}
+
+// WithRequestTimeout returns a copy of ctx which overrides the default
+// request timeout of the client for requests sent with it.
+// A timeout of 0 disables the default timeout for these requests.
+// A deadline set on ctx itself always applies.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout)
+}
+
+func requestTimeout(ctx context.Context, defaultTimeout time.Duration) time.Duration {
+	if timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration); ok {
+		return timeout
+	}
+	if _, ok := ctx.Deadline(); ok {
+		return 0
+	}
+	return defaultTimeout
+}
+
+// timeoutTransport bounds the duration of requests, including the
+// time spent reading the response body.
+type timeoutTransport struct {
+	defaultTimeout time.Duration
+	next           http.RoundTripper
+}
+
+func (t *timeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	timeout := requestTimeout(req.Context(), t.defaultTimeout)
+	if timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the context of a request
+// once its response has been consumed.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// withRequestTimeout wraps transport so that requests whose context
+// has no deadline time out after defaultTimeout, unless overridden
+// with WithRequestTimeout.
+func withRequestTimeout(transport http.RoundTripper, defaultTimeout time.Duration) http.RoundTripper {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &timeoutTransport{
+		defaultTimeout: defaultTimeout,
+		next:           transport,
+	}
+}
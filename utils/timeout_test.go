@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeout(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, time.Second, requestTimeout(ctx, time.Second))
+	assert.Equal(t, time.Minute, requestTimeout(WithRequestTimeout(ctx, time.Minute), time.Second))
+
+	deadlineCtx, cancel := context.WithTimeout(ctx, time.Hour)
+	defer cancel()
+	assert.Equal(t, time.Duration(0), requestTimeout(deadlineCtx, time.Second))
+}
+
+func TestGetKongClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		_, _ = w.Write([]byte(`{"version":"3.4.0"}`))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := GetKongClient(KongClientConfig{Address: server.URL, Timeout: 1})
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Root(WithRequestTimeout(context.Background(), 50*time.Millisecond))
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+
+	start = time.Now()
+	_, err = client.Root(context.Background())
+	require.Error(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), time.Second)
+}
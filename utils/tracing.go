@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/kong/deck"
+
+// adminAPICollections are the Admin API endpoints whose following
+// path segment identifies a single entity.
+var adminAPICollections = map[string]struct{}{
+	"services": {}, "routes": {}, "plugins": {}, "consumers": {},
+	"consumer_groups": {}, "upstreams": {}, "targets": {}, "certificates": {},
+	"ca_certificates": {}, "snis": {}, "vaults": {}, "keys": {}, "key-sets": {},
+	"key-auths": {}, "basic-auths": {}, "hmac-auths": {}, "jwts": {}, "acls": {},
+	"oauth2": {}, "mtls-auths": {}, "workspaces": {}, "roles": {}, "endpoints": {},
+	"entities": {}, "admins": {}, "developers": {}, "schemas": {}, "tags": {},
+	"licenses": {}, "filter-chains": {}, "partials": {}, "degraphql_routes": {},
+	"graphql_ratelimiting_advanced_cost_decoration": {}, "data-planes": {},
+	// credentials nested under consumers
+	"key-auth": {}, "basic-auth": {}, "hmac-auth": {}, "jwt": {}, "mtls-auth": {},
+	// RBAC
+	"users": {},
+	// Konnect
+	"runtime_groups": {}, "control-planes": {},
+}
+
+// konnectCollections are the collections of adminAPICollections only
+// found in Konnect, which has no workspaces.
+var konnectCollections = map[string]struct{}{
+	"runtime_groups": {}, "control-planes": {},
+}
+
+// adminAPIEndpoints are the top-level Admin API endpoints which are not
+// collections. They are never a workspace, and can follow one.
+var adminAPIEndpoints = map[string]struct{}{
+	"kong": {}, "status": {}, "config": {}, "clustering": {}, "rbac": {},
+	"timers": {}, "metrics": {}, "debug": {}, "userinfo": {}, "auth": {},
+}
+
+// isAdminAPIEndpoint returns whether segment is a known collection or
+// endpoint of the Admin API of Kong.
+func isAdminAPIEndpoint(segment string) bool {
+	_, collection := adminAPICollections[segment]
+	_, konnect := konnectCollections[segment]
+	_, endpoint := adminAPIEndpoints[segment]
+	return (collection && !konnect) || endpoint
+}
+
+// pathTemplate turns the path of an Admin API request into a template
+// with low cardinality, replacing entity IDs and names by `{id}`
+// and the workspace by `{workspace}`. The first segment is only a
+// workspace if it is followed by a known collection or endpoint.
+// It also returns the type of the entity targeted by the request.
+func pathTemplate(path string) (string, string) {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) == 1 && segments[0] == "" {
+		return "/", ""
+	}
+	var entityType string
+	for i, segment := range segments {
+		if _, ok := adminAPICollections[segment]; ok {
+			entityType = segment
+			continue
+		}
+		switch {
+		case i == 0 && len(segments) > 1 && !isAdminAPIEndpoint(segment) &&
+			isAdminAPIEndpoint(segments[1]):
+			segments[i] = "{workspace}"
+		case i > 0 && segments[i-1] == entityType && entityType != "":
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/"), entityType
+}
+
+// tracingTransport creates a span for each request sent to Kong
+// and propagates the trace context in the request headers.
+type tracingTransport struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+	next       http.RoundTripper
+}
+
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	template, entityType := pathTemplate(req.URL.Path)
+	ctx, span := t.tracer.Start(req.Context(),
+		fmt.Sprintf("%s %s", req.Method, template),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.route", template),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("kong.entity_type", entityType),
+		),
+	)
+	defer span.End()
+
+	req = req.Clone(ctx)
+	t.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
+// withTracing wraps transport so that a span is recorded
+// for every request, using tracerProvider.
+func withTracing(transport http.RoundTripper, tracerProvider trace.TracerProvider) http.RoundTripper {
+	if tracerProvider == nil {
+		return transport
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &tracingTransport{
+		tracer:     tracerProvider.Tracer(tracerName),
+		propagator: propagation.TraceContext{},
+		next:       transport,
+	}
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestPathTemplate(t *testing.T) {
+	tests := []struct {
+		path       string
+		template   string
+		entityType string
+	}{
+		{"/", "/", ""},
+		{"/services", "/services", "services"},
+		{"/services/foo", "/services/{id}", "services"},
+		{"/services/foo/routes", "/services/{id}/routes", "routes"},
+		{"/services/foo/routes/bar", "/services/{id}/routes/{id}", "routes"},
+		{"/ws1/services/foo", "/{workspace}/services/{id}", "services"},
+		{"/ws1/kong", "/{workspace}/kong", ""},
+		{"/status", "/status", ""},
+		{"/upstreams/u1/targets/t1/healthy", "/upstreams/{id}/targets/{id}/healthy", "targets"},
+		{"/status/ready", "/status/ready", ""},
+		{"/clustering/data-planes", "/clustering/data-planes", "data-planes"},
+		{"/clustering/status", "/clustering/status", ""},
+		{"/ws1/rbac/users/u1/roles", "/{workspace}/rbac/users/{id}/roles", "roles"},
+		{"/consumers/c1/key-auth/k1", "/consumers/{id}/key-auth/{id}", "key-auth"},
+		{"/consumers/c1/basic-auth/b1", "/consumers/{id}/basic-auth/{id}", "basic-auth"},
+		{"/consumers/c1/hmac-auth/h1", "/consumers/{id}/hmac-auth/{id}", "hmac-auth"},
+		{"/consumers/c1/jwt/j1", "/consumers/{id}/jwt/{id}", "jwt"},
+		{"/consumers/c1/oauth2/o1", "/consumers/{id}/oauth2/{id}", "oauth2"},
+		{"/consumers/c1/mtls-auth/m1", "/consumers/{id}/mtls-auth/{id}", "mtls-auth"},
+		{"/ws1/consumers/c1/acls/a1", "/{workspace}/consumers/{id}/acls/{id}", "acls"},
+		{
+			"/konnect-api/api/runtime_groups/rg1/services/s1",
+			"/konnect-api/api/runtime_groups/{id}/services/{id}", "services",
+		},
+		{
+			"/v2/control-planes/cp1/core-entities/services/s1",
+			"/v2/control-planes/{id}/core-entities/services/{id}", "services",
+		},
+		{"/unknown/path", "/unknown/path", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			template, entityType := pathTemplate(tt.path)
+			assert.Equal(t, tt.template, template)
+			assert.Equal(t, tt.entityType, entityType)
+		})
+	}
+}
+
+func TestGetKongClientTracing(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"message":"Not found"}`))
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	client, err := GetKongClient(KongClientConfig{
+		Address:        server.URL,
+		TracerProvider: provider,
+	})
+	require.NoError(t, err)
+
+	_, err = client.Services.Get(context.Background(), kong.String("foo"))
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /services/{id}", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("kong.entity_type", "services"))
+	assert.NotEmpty(t, traceparent)
+}
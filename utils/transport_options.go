@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportOptions tunes the connections opened to Kong, without having
+// to build a whole http.Client.
+type TransportOptions struct {
+	// ProxyURL, if set, is the proxy requests are sent through, e.g.
+	// http://proxy:3128 or socks5://bastion:1080.
+	// Defaults to the proxy set by the HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY environment variables.
+	ProxyURL string
+
+	// DialContext, if set, opens the connections to Kong, or to the proxy.
+	DialContext func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// MaxIdleConnsPerHost, if positive, is the number of idle connections
+	// kept open to Kong.
+	MaxIdleConnsPerHost int
+	// IdleConnTimeout, if positive, is how long idle connections
+	// are kept open.
+	IdleConnTimeout time.Duration
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+}
+
+func (o TransportOptions) isZero() bool {
+	return o.ProxyURL == "" && o.DialContext == nil && o.MaxIdleConnsPerHost == 0 &&
+		o.IdleConnTimeout == 0 && !o.DisableKeepAlives
+}
+
+// apply returns a copy of transport with the options of o.
+func (o TransportOptions) apply(transport *http.Transport) (*http.Transport, error) {
+	res := transport.Clone()
+	if o.ProxyURL != "" {
+		proxyURL, err := url.Parse(o.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("parsing proxy URL: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("unsupported proxy scheme '%s', must be one of: "+
+				"http, https, socks5, socks5h", proxyURL.Scheme)
+		}
+		res.Proxy = http.ProxyURL(proxyURL)
+	}
+	if o.DialContext != nil {
+		res.DialContext = o.DialContext
+	}
+	if o.MaxIdleConnsPerHost > 0 {
+		res.MaxIdleConnsPerHost = o.MaxIdleConnsPerHost
+		if res.MaxIdleConns > 0 && res.MaxIdleConns < o.MaxIdleConnsPerHost {
+			res.MaxIdleConns = o.MaxIdleConnsPerHost
+		}
+	}
+	if o.IdleConnTimeout > 0 {
+		res.IdleConnTimeout = o.IdleConnTimeout
+	}
+	res.DisableKeepAlives = o.DisableKeepAlives
+	return res, nil
+}
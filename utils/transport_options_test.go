@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransportOptionsApply(t *testing.T) {
+	base := &http.Transport{MaxIdleConns: 10}
+	res, err := TransportOptions{
+		ProxyURL:            "socks5://bastion:1080",
+		MaxIdleConnsPerHost: 20,
+		IdleConnTimeout:     time.Minute,
+		DisableKeepAlives:   true,
+	}.apply(base)
+	require.NoError(t, err)
+	assert.Nil(t, base.Proxy)
+	assert.Equal(t, 20, res.MaxIdleConnsPerHost)
+	assert.Equal(t, 20, res.MaxIdleConns)
+	assert.Equal(t, time.Minute, res.IdleConnTimeout)
+	assert.True(t, res.DisableKeepAlives)
+
+	req, err := http.NewRequest(http.MethodGet, "http://kong:8001/status", nil)
+	require.NoError(t, err)
+	proxy, err := res.Proxy(req)
+	require.NoError(t, err)
+	assert.Equal(t, "socks5://bastion:1080", proxy.String())
+
+	_, err = TransportOptions{ProxyURL: "ftp://proxy"}.apply(base)
+	assert.ErrorContains(t, err, "unsupported proxy scheme 'ftp'")
+}
+
+func TestGetKongClientDialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer server.Close()
+
+	var dials int32
+	client, err := GetKongClient(KongClientConfig{
+		// the dialer routes this unresolvable address to the server
+		Address: "http://kong.invalid:8001",
+		Transport: TransportOptions{
+			DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				atomic.AddInt32(&dials, 1)
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, network, server.Listener.Addr().String())
+			},
+			DisableKeepAlives: true,
+		},
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		_, _, err = client.Services.List(context.Background(), nil)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&dials))
+}
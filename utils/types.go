@@ -20,6 +20,7 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/kong/go-kong/kong/custom"
 	"github.com/ssgelm/cookiejarparser"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var clientTimeout time.Duration
@@ -91,7 +92,13 @@ type KongClientConfig struct {
 	TLSCACert string
 
 	TLSSkipVerify bool
-	Debug         bool
+
+	// DebugLevel controls the logging of requests and responses
+	// exchanged with Kong.
+	DebugLevel DebugLevel
+	// DebugSampleRate, if greater than 1, logs only 1 in
+	// DebugSampleRate requests.
+	DebugSampleRate int
 
 	SkipWorkspaceCrud bool
 
@@ -99,6 +106,13 @@ type KongClientConfig struct {
 
 	HTTPClient *http.Client
 
+	// Transport tunes the connections to Kong: proxy, dialer
+	// and connection pool.
+	Transport TransportOptions
+
+	// Timeout is the default timeout of requests, in seconds, applied
+	// when their context has no deadline. It can be overridden per
+	// request with WithRequestTimeout.
 	Timeout int
 
 	CookieJarPath string
@@ -109,17 +123,78 @@ type KongClientConfig struct {
 
 	// whether or not the client should retry on 429s
 	Retryable bool
+
+	// RateLimiter, if set, limits the rate of requests sent to Kong.
+	// It is shared by all configs copied from this one.
+	RateLimiter *RateLimiter
+
+	// CircuitBreaker, if set, makes requests fail fast while the Admin
+	// API keeps failing.
+	// It is shared by all configs copied from this one.
+	CircuitBreaker *CircuitBreaker
+
+	// TracerProvider, if set, is used to record a span
+	// for every request sent to Kong.
+	TracerProvider trace.TracerProvider
+
+	// MetricsCollector, if set, is notified of every request sent to Kong.
+	MetricsCollector MetricsCollector
+
+	// Logger receives the requests and responses exchanged with Kong,
+	// with secrets redacted, as configured by DebugLevel.
+	// Defaults to a logger writing to stderr.
+	Logger Logger
+
+	// ResponseCache, if set, is used to revalidate GET requests
+	// with the ETag of previously received responses.
+	ResponseCache *ResponseCache
+
+	// SchemaCache, if set, stores the schemas fetched from Kong across
+	// clients and runs.
+	SchemaCache *SchemaCache
+
+	// CompressionMinSize, if positive, is the size in bytes from which
+	// request bodies are compressed with gzip, e.g. large configurations
+	// sent to /config. Kong, or a proxy in front of it, must accept
+	// gzipped requests. Gzipped responses are always decompressed.
+	CompressionMinSize int
+
+	// DryRunRecorder, if set, captures requests modifying Kong
+	// instead of sending them. Read-only requests are sent as usual.
+	DryRunRecorder *MutationRecorder
+
+	// Middlewares, if set, intercept every request sent to Kong, after
+	// all headers have been set.
+	Middlewares *MiddlewareChain
+
+	// Fixture, if set, records the interactions with Kong to a file,
+	// or replays them from it instead of sending requests to Kong.
+	Fixture *Fixture
 }
 
 type KonnectConfig struct {
 	Email    string
 	Password string
 	Token    string
-	Debug    bool
+
+	// DebugLevel controls the logging of requests and responses
+	// exchanged with Konnect.
+	DebugLevel DebugLevel
+	// DebugSampleRate, if greater than 1, logs only 1 in
+	// DebugSampleRate requests.
+	DebugSampleRate int
 
 	Address string
 
 	Headers []string
+
+	// RateLimiter, if set, limits the rate of requests sent to Konnect.
+	RateLimiter *RateLimiter
+
+	// Logger receives the requests and responses exchanged with Konnect,
+	// with secrets redacted, as configured by DebugLevel.
+	// Defaults to a logger writing to stderr.
+	Logger Logger
 }
 
 // ForWorkspace returns a copy of KongClientConfig that produces a KongClient for the workspace specified by argument.
@@ -228,11 +303,43 @@ func GetKongClient(opt KongClientConfig) (*kong.Client, error) {
 	c := opt.HTTPClient
 	if c == nil {
 		c = HTTPClient()
+		// timeouts are enforced per request by the transport,
+		// allowing them to be overridden with WithRequestTimeout.
+		c.Timeout = 0
 	}
 	defaultTransport := http.DefaultTransport.(*http.Transport)
 	defaultTransport.TLSClientConfig = &tlsConfig
-	c.Transport = defaultTransport
-	address := CleanAddress(opt.Address)
+	if !opt.Transport.isZero() {
+		var err error
+		defaultTransport, err = opt.Transport.apply(defaultTransport)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var transport http.RoundTripper = defaultTransport
+	address, err := ParseAddress(opt.Address)
+	if err != nil {
+		return nil, err
+	}
+	if address.Scheme == "unix" {
+		transport = unixSocketTransport(defaultTransport, address.Path)
+		address, _ = url.Parse(unixSocketAddress)
+	}
+	transport = withCompression(transport, opt.CompressionMinSize)
+	transport = withFixture(transport, opt.Fixture)
+	transport = withDryRun(transport, opt.DryRunRecorder)
+	if opt.DebugLevel > DebugLevelNone {
+		transport = withDebugLogging(transport, debugLogger(opt.Logger),
+			opt.DebugLevel, opt.DebugSampleRate)
+	}
+	transport = withMiddlewares(transport, opt.Middlewares)
+	transport = withResponseCache(transport, opt.ResponseCache)
+	transport = withSchemaCache(transport, opt.SchemaCache)
+	transport = withTracing(transport, opt.TracerProvider)
+	transport = withMetrics(transport, opt.MetricsCollector)
+	transport = withRequestTimeout(transport, clientTimeout)
+	transport = withRateLimit(transport, opt.RateLimiter)
+	c.Transport = withCircuitBreaker(transport, opt.CircuitBreaker)
 
 	headers, err := parseHeaders(opt.Headers)
 	if err != nil {
@@ -244,10 +351,6 @@ func GetKongClient(opt KongClientConfig) (*kong.Client, error) {
 		c = getRetryableClient(c)
 	}
 
-	url, err := url.ParseRequestURI(address)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse kong address: %w", err)
-	}
 	// Add Session Cookie support if required
 	if opt.CookieJarPath != "" {
 		jar, err := cookiejarparser.LoadCookieJarFile(opt.CookieJarPath)
@@ -257,14 +360,10 @@ func GetKongClient(opt KongClientConfig) (*kong.Client, error) {
 		c.Jar = jar
 	}
 
-	kongClient, err := kong.NewClient(kong.String(url.String()), c)
+	kongClient, err := kong.NewClient(kong.String(address.String()), c)
 	if err != nil {
 		return nil, fmt.Errorf("creating client for Kong's Admin API: %w", err)
 	}
-	if opt.Debug {
-		kongClient.SetDebugMode(true)
-		kongClient.SetLogger(os.Stderr)
-	}
 	if opt.Workspace != "" {
 		kongClient.SetWorkspace(opt.Workspace)
 	}
@@ -288,31 +387,46 @@ func parseHeaders(headers []string) (http.Header, error) {
 func GetKonnectClient(httpClient *http.Client, config KonnectConfig) (*konnect.Client,
 	error,
 ) {
-	address := CleanAddress(config.Address)
+	address, err := ParseAddress(config.Address)
+	if err != nil {
+		return nil, err
+	}
 
 	if httpClient == nil {
 		defaultTransport := http.DefaultTransport.(*http.Transport)
 		httpClient = http.DefaultClient
 		httpClient.Transport = defaultTransport
 	}
+	if config.RateLimiter != nil || config.DebugLevel > DebugLevelNone {
+		wrapped := *httpClient
+		if config.DebugLevel > DebugLevelNone {
+			wrapped.Transport = withDebugLogging(wrapped.Transport, debugLogger(config.Logger),
+				config.DebugLevel, config.DebugSampleRate)
+		}
+		wrapped.Transport = withRateLimit(wrapped.Transport, config.RateLimiter)
+		httpClient = &wrapped
+	}
 	headers, err := parseHeaders(config.Headers)
 	if err != nil {
 		return nil, fmt.Errorf("parsing headers: %w", err)
 	}
 	httpClient = kong.HTTPClientWithHeaders(httpClient, headers)
 	client, err := konnect.NewClient(httpClient, konnect.ClientOpts{
-		BaseURL: address,
+		BaseURL: address.String(),
 	})
 	if err != nil {
 		return nil, err
 	}
-	if config.Debug {
-		client.SetDebugMode(true)
-		client.SetLogger(os.Stderr)
-	}
 	return client, nil
 }
 
+func debugLogger(logger Logger) Logger {
+	if logger != nil {
+		return logger
+	}
+	return NewWriterLogger(os.Stderr)
+}
+
 // CleanAddress removes trailling / from a URL.
 func CleanAddress(address string) string {
 	re := regexp.MustCompile("[/]+$")
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const (
+	unixSocketScheme = "unix://"
+	// unixSocketAddress is the address used in requests sent
+	// over a unix domain socket, where the host is irrelevant.
+	unixSocketAddress = "http://localhost"
+)
+
+// unixSocketPath returns the path of the socket if address
+// is a unix domain socket address, e.g. `unix:///run/kong/admin.sock`.
+func unixSocketPath(address string) (string, bool) {
+	if !strings.HasPrefix(address, unixSocketScheme) {
+		return "", false
+	}
+	return CleanAddress(strings.TrimPrefix(address, unixSocketScheme)), true
+}
+
+// unixSocketTransport returns a copy of transport which
+// connects to the unix domain socket at socketPath.
+func unixSocketTransport(transport *http.Transport, socketPath string) *http.Transport {
+	res := transport.Clone()
+	res.Proxy = nil
+	res.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, "unix", socketPath)
+	}
+	return res
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetKongClientUnixSocket(t *testing.T) {
+	socketPath := filepath.Join(t.TempDir(), "admin.sock")
+	listener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	server := &http.Server{ //nolint:gosec
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/", r.URL.Path)
+			_, _ = w.Write([]byte(`{"version":"3.4.0"}`))
+		}),
+	}
+	go func() { _ = server.Serve(listener) }()
+	defer server.Close()
+
+	client, err := GetKongClient(KongClientConfig{Address: "unix://" + socketPath})
+	require.NoError(t, err)
+
+	root, err := client.Root(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "3.4.0", root["version"])
+
+	_, err = GetKongClient(KongClientConfig{Address: "unix://"})
+	assert.Error(t, err)
+}
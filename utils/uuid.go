@@ -1,10 +1,37 @@
 package utils
 
 import (
+	"math/rand"
+	"sync"
+
 	"github.com/google/uuid"
 )
 
+var (
+	uuidMu   sync.Mutex
+	uuidRand *rand.Rand
+)
+
 // UUID will generate a random v4 unique identifier
 func UUID() string {
-	return uuid.NewString()
+	uuidMu.Lock()
+	defer uuidMu.Unlock()
+	if uuidRand == nil {
+		return uuid.NewString()
+	}
+	return uuid.Must(uuid.NewRandomFromReader(uuidRand)).String()
+}
+
+// SetUUIDSeed makes UUID generate the same sequence of identifiers for
+// the same seed, e.g. so that the IDs of the entities created by a sync
+// recorded in a Fixture are the same when it is replayed.
+// A seed of 0 restores random identifiers.
+func SetUUIDSeed(seed int64) {
+	uuidMu.Lock()
+	defer uuidMu.Unlock()
+	if seed == 0 {
+		uuidRand = nil
+		return
+	}
+	uuidRand = rand.New(rand.NewSource(seed)) //nolint:gosec
 }
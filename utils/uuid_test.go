@@ -15,3 +15,15 @@ func TestUUID(t *testing.T) {
 		"^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$"),
 		uuid)
 }
+
+func TestSetUUIDSeed(t *testing.T) {
+	defer SetUUIDSeed(0)
+	SetUUIDSeed(42)
+	first := []string{UUID(), UUID()}
+	SetUUIDSeed(42)
+	assert.Equal(t, first, []string{UUID(), UUID()})
+	assert.NotEqual(t, first[0], first[1])
+
+	SetUUIDSeed(0)
+	assert.NotEqual(t, first[0], UUID())
+}
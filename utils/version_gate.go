@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/blang/semver/v4"
+)
+
+// UnsupportedFieldsMode controls how fields unknown to the
+// targeted Kong version are handled before they are sent to Kong.
+type UnsupportedFieldsMode string
+
+const (
+	// UnsupportedFieldsIgnore sends all fields as is and lets the
+	// Admin API validate them.
+	UnsupportedFieldsIgnore UnsupportedFieldsMode = ""
+	// UnsupportedFieldsStrip removes fields that are not supported by the
+	// targeted Kong version.
+	UnsupportedFieldsStrip UnsupportedFieldsMode = "strip"
+	// UnsupportedFieldsReject returns an error if any field is not supported
+	// by the targeted Kong version.
+	UnsupportedFieldsReject UnsupportedFieldsMode = "reject"
+)
+
+// ParseUnsupportedFieldsMode validates mode and returns the
+// corresponding UnsupportedFieldsMode.
+func ParseUnsupportedFieldsMode(mode string) (UnsupportedFieldsMode, error) {
+	switch m := UnsupportedFieldsMode(mode); m {
+	case UnsupportedFieldsIgnore, UnsupportedFieldsStrip, UnsupportedFieldsReject:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid unsupported fields mode '%s', "+
+			"must be one of 'strip' or 'reject'", mode)
+	}
+}
+
+// versionedField is a field of an entity which is only understood
+// by Kong starting from minVersion.
+type versionedField struct {
+	field      string
+	jsonName   string
+	minVersion semver.Version
+}
+
+var (
+	kong220Version = semver.MustParse("2.2.0")
+
+	versionedFields = map[string][]versionedField{
+		"service": {
+			{field: "TLSVerify", jsonName: "tls_verify", minVersion: kong220Version},
+			{field: "TLSVerifyDepth", jsonName: "tls_verify_depth", minVersion: kong220Version},
+			{field: "CACertificates", jsonName: "ca_certificates", minVersion: kong220Version},
+		},
+		"route": {
+			{field: "RequestBuffering", jsonName: "request_buffering", minVersion: kong220Version},
+			{field: "ResponseBuffering", jsonName: "response_buffering", minVersion: kong220Version},
+			{field: "Expression", jsonName: "expression", minVersion: Kong300Version},
+			{field: "Priority", jsonName: "priority", minVersion: Kong300Version},
+		},
+		"upstream": {
+			{field: "HashOnQueryArg", jsonName: "hash_on_query_arg", minVersion: Kong300Version},
+			{field: "HashFallbackQueryArg", jsonName: "hash_fallback_query_arg", minVersion: Kong300Version},
+			{field: "HashOnURICapture", jsonName: "hash_on_uri_capture", minVersion: Kong300Version},
+			{field: "HashFallbackURICapture", jsonName: "hash_fallback_uri_capture", minVersion: Kong300Version},
+		},
+	}
+)
+
+// ShapeForKongVersion walks through all entities in rawState and handles
+// fields which are not supported by kongVersion according to mode.
+// With UnsupportedFieldsStrip, such fields are removed from the entities.
+// With UnsupportedFieldsReject, an ErrArray listing every offending field
+// is returned and rawState is left untouched.
+func ShapeForKongVersion(rawState *KongRawState, kongVersion semver.Version,
+	mode UnsupportedFieldsMode,
+) error {
+	if rawState == nil || mode == UnsupportedFieldsIgnore {
+		return nil
+	}
+	var errs []error
+	shape := func(entityType string, name string, obj interface{}) {
+		for _, f := range unsupportedFields(entityType, obj, kongVersion) {
+			if mode == UnsupportedFieldsStrip {
+				ZeroOutField(obj, f.field)
+				continue
+			}
+			errs = append(errs, fmt.Errorf("%s %s: field '%s' requires Kong %s or above",
+				entityType, name, f.jsonName, f.minVersion))
+		}
+	}
+	for _, s := range rawState.Services {
+		shape("service", s.FriendlyName(), s)
+	}
+	for _, r := range rawState.Routes {
+		shape("route", r.FriendlyName(), r)
+	}
+	for _, u := range rawState.Upstreams {
+		shape("upstream", u.FriendlyName(), u)
+	}
+	if len(errs) > 0 {
+		return ErrArray{Errors: errs}
+	}
+	return nil
+}
+
+func unsupportedFields(entityType string, obj interface{},
+	kongVersion semver.Version,
+) []versionedField {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	var res []versionedField
+	for _, f := range versionedFields[entityType] {
+		if kongVersion.GTE(f.minVersion) {
+			continue
+		}
+		fv := v.FieldByName(f.field)
+		if fv.IsValid() && !fv.IsZero() {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUnsupportedFieldsMode(t *testing.T) {
+	assert := assert.New(t)
+
+	mode, err := ParseUnsupportedFieldsMode("")
+	assert.Nil(err)
+	assert.Equal(UnsupportedFieldsIgnore, mode)
+
+	mode, err = ParseUnsupportedFieldsMode("strip")
+	assert.Nil(err)
+	assert.Equal(UnsupportedFieldsStrip, mode)
+
+	mode, err = ParseUnsupportedFieldsMode("reject")
+	assert.Nil(err)
+	assert.Equal(UnsupportedFieldsReject, mode)
+
+	_, err = ParseUnsupportedFieldsMode("drop")
+	assert.NotNil(err)
+}
+
+func newVersionGateState() *KongRawState {
+	return &KongRawState{
+		Services: []*kong.Service{
+			{
+				Name:      kong.String("svc1"),
+				Host:      kong.String("example.com"),
+				TLSVerify: kong.Bool(true),
+			},
+		},
+		Routes: []*kong.Route{
+			{
+				Name:       kong.String("r1"),
+				Expression: kong.String(`http.path == "/foo"`),
+			},
+			{
+				Name:  kong.String("r2"),
+				Paths: kong.StringSlice("/bar"),
+			},
+		},
+	}
+}
+
+func TestShapeForKongVersion(t *testing.T) {
+	assert := assert.New(t)
+
+	t.Run("ignore leaves entities untouched", func(t *testing.T) {
+		rawState := newVersionGateState()
+		err := ShapeForKongVersion(rawState, semver.MustParse("2.1.0"), UnsupportedFieldsIgnore)
+		assert.Nil(err)
+		assert.NotNil(rawState.Services[0].TLSVerify)
+		assert.NotNil(rawState.Routes[0].Expression)
+	})
+
+	t.Run("strip removes unsupported fields", func(t *testing.T) {
+		rawState := newVersionGateState()
+		err := ShapeForKongVersion(rawState, semver.MustParse("2.1.0"), UnsupportedFieldsStrip)
+		assert.Nil(err)
+		assert.Nil(rawState.Services[0].TLSVerify)
+		assert.Equal("example.com", *rawState.Services[0].Host)
+		assert.Nil(rawState.Routes[0].Expression)
+		assert.Equal("/bar", *rawState.Routes[1].Paths[0])
+	})
+
+	t.Run("strip keeps supported fields", func(t *testing.T) {
+		rawState := newVersionGateState()
+		err := ShapeForKongVersion(rawState, semver.MustParse("2.8.0"), UnsupportedFieldsStrip)
+		assert.Nil(err)
+		assert.NotNil(rawState.Services[0].TLSVerify)
+		assert.Nil(rawState.Routes[0].Expression)
+	})
+
+	t.Run("reject reports all unsupported fields", func(t *testing.T) {
+		rawState := newVersionGateState()
+		err := ShapeForKongVersion(rawState, semver.MustParse("2.1.0"), UnsupportedFieldsReject)
+		assert.NotNil(err)
+		errs, ok := err.(ErrArray)
+		assert.True(ok)
+		assert.Len(errs.Errors, 2)
+		assert.Contains(err.Error(), "service svc1: field 'tls_verify' requires Kong 2.2.0 or above")
+		assert.Contains(err.Error(), "route r1: field 'expression' requires Kong 3.0.0 or above")
+		assert.NotNil(rawState.Services[0].TLSVerify)
+	})
+
+	t.Run("reject passes on recent versions", func(t *testing.T) {
+		rawState := newVersionGateState()
+		err := ShapeForKongVersion(rawState, semver.MustParse("3.2.0"), UnsupportedFieldsReject)
+		assert.Nil(err)
+	})
+}
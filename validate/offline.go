@@ -0,0 +1,105 @@
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/deck/utils"
+)
+
+// versionRange is the range of Kong versions supporting a feature.
+// A zero max means the feature is still supported.
+type versionRange struct {
+	min semver.Version
+	max semver.Version
+}
+
+func (r versionRange) supports(v semver.Version) bool {
+	if v.LT(r.min) {
+		return false
+	}
+	return r.max.Equals(semver.Version{}) || v.LT(r.max)
+}
+
+func (r versionRange) String() string {
+	if r.max.Equals(semver.Version{}) {
+		return fmt.Sprintf("Kong %s or above", r.min)
+	}
+	if r.min.Equals(semver.Version{}) {
+		return fmt.Sprintf("Kong below %s", r.max)
+	}
+	return fmt.Sprintf("Kong %s to %s (excluded)", r.min, r.max)
+}
+
+var (
+	kong270Version = semver.MustParse("2.7.0")
+	kong280Version = semver.MustParse("2.8.0")
+	kong360Version = semver.MustParse("3.6.0")
+
+	// entityVersions lists the entities which are not supported by all
+	// Kong versions.
+	entityVersions = map[string]versionRange{
+		"consumer group": {min: kong270Version},
+		"vault":          {min: kong280Version},
+	}
+
+	// pluginVersions lists the bundled plugins which are not shipped with
+	// all Kong versions.
+	pluginVersions = map[string]versionRange{
+		"kubernetes-sidecar-injector": {max: utils.Kong300Version},
+		"opentelemetry":               {min: utils.Kong300Version},
+		"ai-proxy":                    {min: kong360Version},
+		"ai-prompt-decorator":         {min: kong360Version},
+		"ai-prompt-guard":             {min: kong360Version},
+		"ai-prompt-template":          {min: kong360Version},
+		"ai-request-transformer":      {min: kong360Version},
+		"ai-response-transformer":     {min: kong360Version},
+	}
+)
+
+// ValidateForKongVersion checks, without contacting Kong, that the entities
+// of rawState are supported by kongVersion.
+// It reports entities and bundled plugins which do not exist in
+// kongVersion, and fields which kongVersion does not understand.
+// It only knows the versions of a few entities, plugins and fields: no
+// schema is bundled, so unknown fields, invalid values and configurations
+// of plugins are not checked.
+func ValidateForKongVersion(rawState *utils.KongRawState, kongVersion semver.Version) []error {
+	var errs []error
+	checkEntities := func(kind string, count int) {
+		if r := entityVersions[kind]; count > 0 && !r.supports(kongVersion) {
+			errs = append(errs, fmt.Errorf("%s entities require %s", kind, r))
+		}
+	}
+	checkEntities("consumer group", len(rawState.ConsumerGroups))
+	checkEntities("vault", len(rawState.Vaults))
+
+	unsupportedPlugins := map[string]bool{}
+	for _, p := range rawState.Plugins {
+		if p.Name == nil {
+			continue
+		}
+		if r, ok := pluginVersions[*p.Name]; ok && !r.supports(kongVersion) {
+			unsupportedPlugins[*p.Name] = true
+		}
+	}
+	names := make([]string, 0, len(unsupportedPlugins))
+	for name := range unsupportedPlugins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		errs = append(errs, fmt.Errorf("plugin %s requires %s", name, pluginVersions[name]))
+	}
+
+	err := utils.ShapeForKongVersion(rawState, kongVersion, utils.UnsupportedFieldsReject)
+	var errArray utils.ErrArray
+	if errors.As(err, &errArray) {
+		errs = append(errs, errArray.Errors...)
+	} else if err != nil {
+		errs = append(errs, err)
+	}
+	return errs
+}
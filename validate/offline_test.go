@@ -0,0 +1,62 @@
+package validate
+
+import (
+	"testing"
+
+	"github.com/blang/semver/v4"
+	"github.com/kong/deck/utils"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateForKongVersion(t *testing.T) {
+	rawState := &utils.KongRawState{
+		Routes: []*kong.Route{
+			{Name: kong.String("r1"), Expression: kong.String(`http.path == "/foo"`)},
+		},
+		Plugins: []*kong.Plugin{
+			{Name: kong.String("opentelemetry")},
+			{Name: kong.String("opentelemetry")},
+			{Name: kong.String("kubernetes-sidecar-injector")},
+			{Name: kong.String("custom")},
+		},
+		Vaults: []*kong.Vault{{Prefix: kong.String("env")}},
+	}
+
+	tests := []struct {
+		version  string
+		expected []string
+	}{
+		{
+			version: "2.7.0",
+			expected: []string{
+				"vault entities require Kong 2.8.0 or above",
+				"plugin opentelemetry requires Kong 3.0.0 or above",
+				"route r1: field 'expression' requires Kong 3.0.0 or above",
+			},
+		},
+		{
+			version: "2.8.1",
+			expected: []string{
+				"plugin opentelemetry requires Kong 3.0.0 or above",
+				"route r1: field 'expression' requires Kong 3.0.0 or above",
+			},
+		},
+		{
+			version: "3.4.0",
+			expected: []string{
+				"plugin kubernetes-sidecar-injector requires Kong below 3.0.0",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			errs := ValidateForKongVersion(rawState, semver.MustParse(tt.version))
+			var messages []string
+			for _, err := range errs {
+				messages = append(messages, err.Error())
+			}
+			assert.Equal(t, tt.expected, messages)
+		})
+	}
+}